@@ -1,42 +1,76 @@
 package main
 
 import (
+	stderrors "errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/yammerjp/devslot/internal/command"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/hook"
 	"github.com/yammerjp/devslot/internal/logger"
+	"github.com/yammerjp/devslot/internal/version"
 )
 
-// version is set by ldflags during build
-var version = "dev"
-
 type CLI struct {
-	Verbose     bool                   `long:"verbose" help:"Enable verbose logging"`
-	Boilerplate command.BoilerplateCmd `cmd:"" help:"Generate initial project structure in the specified directory"`
-	Init        command.InitCmd        `cmd:"" help:"Sync bare repositories defined in devslot.yaml into repos/"`
-	Create      command.CreateCmd      `cmd:"" help:"Create a new slot (multi-repo worktree environment)"`
-	Destroy     command.DestroyCmd     `cmd:"" help:"Remove the specified slot (runs pre-destroy hook if exists)"`
-	Reload      command.ReloadCmd      `cmd:"" help:"Ensure all worktrees exist for the slot and run post-reload hook if exists"`
-	List        command.ListCmd        `cmd:"" help:"List all existing slots"`
-	Doctor      command.DoctorCmd      `cmd:"" help:"Check consistency of project structure and repositories"`
-	Version     command.VersionCmd     `cmd:"" help:"Show devslot version"`
+	Verbose       bool                     `long:"verbose" help:"Enable verbose logging (same as --log-level=debug)"`
+	LogLevel      string                   `name:"log-level" enum:"debug,info,warn,error" default:"warn" help:"Minimum level of log messages (debug, info, warn, error)"`
+	LogFormat     string                   `name:"log-format" enum:"text,json" default:"text" help:"Format of log messages (text, json)"`
+	LogFile       string                   `name:"log-file" type:"path" placeholder:"PATH" help:"Append log messages to PATH instead of stderr"`
+	Color         string                   `enum:"auto,always,never" default:"auto" help:"Use color and emoji in output (auto, always, never)"`
+	NoColor       bool                     `name:"no-color" help:"Same as --color=never"`
+	Quiet         bool                     `short:"q" help:"Suppress informational output (results, warnings and errors are still shown)"`
+	ProjectRoot   string                   `short:"C" name:"project-root" env:"DEVSLOT_PROJECT_ROOT" type:"path" placeholder:"DIR" help:"Run as if devslot was started in DIR"`
+	Config        string                   `name:"config" env:"DEVSLOT_CONFIG" type:"path" placeholder:"PATH" help:"Use the configuration file at PATH; its directory is the project root"`
+	Boilerplate   command.BoilerplateCmd   `cmd:"" help:"Generate initial project structure in the specified directory"`
+	Import        command.ImportCmd        `cmd:"" help:"Add the git clones in a directory to devslot.yaml"`
+	Init          command.InitCmd          `cmd:"" help:"Sync bare repositories defined in devslot.yaml into repos/"`
+	Fetch         command.FetchCmd         `cmd:"" help:"Fetch updates for all bare repositories in repos/"`
+	Unshallow     command.UnshallowCmd     `cmd:"" help:"Fetch the full history of shallow bare repositories"`
+	Create        command.CreateCmd        `cmd:"" help:"Create a new slot (multi-repo worktree environment)"`
+	Destroy       command.DestroyCmd       `cmd:"" help:"Remove the specified slots (runs pre-destroy hook if exists)"`
+	Archive       command.ArchiveCmd       `cmd:"" help:"Pack a slot into archives/ and remove its worktrees"`
+	Restore       command.RestoreCmd       `cmd:"" help:"Recreate an archived slot"`
+	Reload        command.ReloadCmd        `cmd:"" help:"Ensure all worktrees exist for the slot and run post-reload hook if exists"`
+	Sync          command.SyncCmd          `cmd:"" help:"Initialize repositories and reload every slot"`
+	Checkout      command.CheckoutCmd      `cmd:"" help:"Switch all repositories in a slot to a branch"`
+	List          command.ListCmd          `cmd:"" help:"List all existing slots"`
+	Pull          command.PullCmd          `cmd:"" help:"Update the worktrees of a slot from their upstream branches"`
+	Describe      command.DescribeCmd      `cmd:"" help:"Show or set the description of a slot"`
+	Status        command.StatusCmd        `cmd:"" help:"Show the branch checked out in each worktree of a slot"`
+	Info          command.InfoCmd          `cmd:"" help:"Show project information"`
+	Root          command.RootCmd          `cmd:"" help:"Print the project root directory"`
+	Path          command.PathCmd          `cmd:"" help:"Print the path of a slot or of a repository in a slot"`
+	Open          command.OpenCmd          `cmd:"" help:"Open a slot or a repository in a slot in your editor"`
+	Hooks         command.HooksCmd         `cmd:"" aliases:"hook" help:"Work with lifecycle hooks"`
+	Repo          command.RepoCmd          `cmd:"" aliases:"repos" help:"Inspect the bare repositories in repos/"`
+	Du            command.DuCmd            `cmd:"" help:"Show disk usage of repositories and slots"`
+	Gc            command.GcCmd            `cmd:"" help:"Prune stale worktree registrations, temporary directories and lock files"`
+	Doctor        command.DoctorCmd        `cmd:"" help:"Check consistency of project structure and repositories"`
+	MigrateConfig command.MigrateConfigCmd `cmd:"" name:"migrate-config" help:"Convert devslot.yaml to the latest configuration version"`
+	Version       command.VersionCmd       `cmd:"" help:"Show devslot version"`
 
 	VersionFlag kong.VersionFlag `short:"v" name:"version" help:"Show version"`
 }
 
 type App struct {
 	parser      *kong.Kong
-	writer      io.Writer
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
 	cli         *CLI
 	exitHandler func(int)
 }
 
-func NewApp(writer io.Writer) *App {
+func NewApp(stdout, stderr io.Writer) *App {
 	app := &App{
-		writer:      writer,
+		stdin:       os.Stdin,
+		stdout:      stdout,
+		stderr:      stderr,
 		exitHandler: os.Exit,
 	}
 
@@ -47,12 +81,12 @@ func NewApp(writer io.Writer) *App {
 		kong.ConfigureHelp(kong.HelpOptions{
 			Compact: true,
 		}),
-		kong.Writers(writer, writer),
+		kong.Writers(stdout, stderr),
 		kong.Exit(func(code int) {
 			app.exitHandler(code)
 		}),
 		kong.Vars{
-			"version": version,
+			"version": version.Version,
 		},
 	)
 	if err != nil {
@@ -81,31 +115,139 @@ func (app *App) Run(args []string) error {
 		return err
 	}
 
-	// Access the CLI struct to get verbose flag
+	// Create logger from the logging flags
+	logOpts, closeLog, err := app.logOptions()
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+	log := logger.New(logOpts)
+	slog.SetDefault(log)
+	hook.SetCommand(commandName(ctx.Command()))
 
-	// Create logger with appropriate log level
+	cmdCtx := &command.Context{
+		In:         app.stdin,
+		Out:        app.stdout,
+		Err:        app.stderr,
+		Logger:     log,
+		Verbose:    app.cli.Verbose,
+		Dir:        app.cli.ProjectRoot,
+		ConfigPath: app.cli.Config,
+	}
+	cmdCtx.Color = app.colorMode()
+	if app.cli.Quiet {
+		cmdCtx.Verbosity = command.VerbosityQuiet
+	}
+
+	return ctx.Run(cmdCtx)
+}
+
+// commandName returns the name of a parsed command without its arguments,
+// e.g. "hooks run" for "hooks run <hook-type> <slot-name>"
+func commandName(command string) string {
+	var words []string
+	for _, word := range strings.Fields(command) {
+		if !strings.HasPrefix(word, "<") {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// logOptions builds logger options from the logging flags.
+// The returned function closes the log file, if one was opened.
+func (app *App) logOptions() (logger.Options, func(), error) {
 	logOpts := logger.DefaultOptions()
-	logOpts.Writer = os.Stderr // Log to stderr to keep stdout clean
+	logOpts.Writer = app.stderr // Log to stderr to keep stdout clean
+	logOpts.Format = app.cli.LogFormat
+
+	level, err := logger.ParseLevel(app.cli.LogLevel)
+	if err != nil {
+		return logOpts, func() {}, err
+	}
+	logOpts.Level = level
 	if app.cli.Verbose {
 		logOpts.Level = slog.LevelDebug
 	}
-	log := logger.New(logOpts)
 
-	cmdCtx := &command.Context{
-		Writer: app.writer,
-		Logger: log,
+	if app.cli.LogFile == "" {
+		return logOpts, func() {}, nil
 	}
 
-	return ctx.Run(cmdCtx)
+	file, err := os.OpenFile(app.cli.LogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Fprintf(app.stderr, "Warning: failed to open log file %s, logging to stderr: %v\n", app.cli.LogFile, err)
+		return logOpts, func() {}, nil
+	}
+	logOpts.Writer = file
+	// Log files are read after the fact, so keep timestamps
+	logOpts.KeepTime = true
+	return logOpts, func() { _ = file.Close() }, nil
 }
 
-func main() {
-	// Set the version in the command package
-	command.Version = version
+const (
+	hintColor  = "\033[36m"
+	colorReset = "\033[0m"
+)
+
+// exitError carries the exit code chosen for an error to kong
+type exitError struct {
+	error
+	code int
+}
+
+func (e *exitError) Unwrap() error { return e.error }
+
+// ExitCode implements kong.ExitCoder
+func (e *exitError) ExitCode() int { return e.code }
+
+// exitCode maps an error to the documented exit codes
+func exitCode(err error) int {
+	var parseErr *kong.ParseError
+	if stderrors.As(err, &parseErr) {
+		return errors.ExitUsage
+	}
+	return errors.ExitCode(err)
+}
 
-	app := NewApp(os.Stdout)
+// Fatal reports err on stderr and exits with its exit code.
+// Suggestions of user errors are printed on separate "hint:" lines.
+func (app *App) Fatal(err error) {
+	code := exitCode(err)
+
+	var parseErr *kong.ParseError
+	if stderrors.As(err, &parseErr) {
+		// Let kong print the usage of the command
+		app.parser.FatalIfErrorf(&exitError{error: err, code: code})
+		return
+	}
+
+	message, hint := errors.Hint(err)
+	app.parser.Errorf("%s", message)
+	if hint != "" {
+		styled := (&command.Context{Out: app.stderr, Color: app.colorMode()}).Styled()
+		for _, line := range strings.Split(hint, "\n") {
+			if styled {
+				fmt.Fprintf(app.stderr, "%shint:%s %s\n", hintColor, colorReset, line)
+			} else {
+				fmt.Fprintf(app.stderr, "hint: %s\n", line)
+			}
+		}
+	}
+	app.exitHandler(code)
+}
+
+// colorMode returns the color mode selected by --color and --no-color
+func (app *App) colorMode() command.ColorMode {
+	if app.cli.NoColor {
+		return command.ColorNever
+	}
+	return command.ColorMode(app.cli.Color)
+}
+
+func main() {
+	app := NewApp(os.Stdout, os.Stderr)
 	if err := app.Run(os.Args[1:]); err != nil {
-		// FatalIfErrorf handles exit code and error display
-		app.parser.FatalIfErrorf(err)
+		app.Fatal(err)
 	}
 }
@@ -2,7 +2,14 @@ package main
 
 import (
 	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/testutil"
 )
 
 func TestApp_Run(t *testing.T) {
@@ -41,7 +48,7 @@ func TestApp_Run(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			app := NewApp(&buf)
+			app := NewApp(&buf, &buf)
 
 			// Override exit handler for testing
 			exitCode := -1
@@ -71,6 +78,340 @@ func TestApp_Run(t *testing.T) {
 	}
 }
 
+func TestApp_ProjectRoot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	// Run from a directory outside the project
+	defer testutil.Chdir(t, testutil.TempDir(t))()
+
+	tests := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{name: "short flag", args: []string{"-C", projectRoot, "root"}},
+		{name: "long flag", args: []string{"--project-root", projectRoot, "root"}},
+		{name: "environment variable", args: []string{"root"}, env: projectRoot},
+		{name: "flag overrides environment variable", args: []string{"-C", projectRoot, "root"}, env: t.TempDir()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEVSLOT_PROJECT_ROOT", tt.env)
+
+			var buf bytes.Buffer
+			app := NewApp(&buf, &buf)
+			if err := app.Run(tt.args); err != nil {
+				t.Fatalf("App.Run() error = %v", err)
+			}
+
+			if got := buf.String(); got != projectRoot+"\n" {
+				t.Errorf("App.Run() output = %q, want %q", got, projectRoot+"\n")
+			}
+		})
+	}
+}
+
+func TestApp_ProjectRootNotFound(t *testing.T) {
+	dir := testutil.TempDir(t)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+
+	var buf bytes.Buffer
+	app := NewApp(&buf, &buf)
+	err := app.Run([]string{"-C", dir, "list"})
+	if err == nil {
+		t.Fatal("expected error outside a devslot project")
+	}
+	if !strings.Contains(err.Error(), dir) {
+		t.Errorf("expected error to mention %s, got %v", dir, err)
+	}
+}
+
+func TestApp_Config(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+	scratchRoot := testutil.TempDir(t)
+	scratch := filepath.Join(scratchRoot, "scratch.yaml")
+	testutil.CreateFile(t, scratch, "version: 1\nrepositories: []\n")
+
+	// Discovery from inside the project is overridden
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+
+	tests := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{name: "flag", args: []string{"--config", scratch, "root"}},
+		{name: "relative flag", args: []string{"--config", filepath.Join("..", filepath.Base(scratchRoot), "scratch.yaml"), "root"}},
+		{name: "environment variable", args: []string{"root"}, env: scratch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEVSLOT_CONFIG", tt.env)
+
+			var buf bytes.Buffer
+			app := NewApp(&buf, &buf)
+			if err := app.Run(tt.args); err != nil {
+				t.Fatalf("App.Run() error = %v", err)
+			}
+			if got := buf.String(); got != scratchRoot+"\n" {
+				t.Errorf("App.Run() output = %q, want %q", got, scratchRoot+"\n")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("DEVSLOT_CONFIG", "")
+		missing := filepath.Join(scratchRoot, "missing.yaml")
+
+		var buf bytes.Buffer
+		app := NewApp(&buf, &buf)
+		err := app.Run([]string{"--config", missing, "list"})
+		if err == nil || !strings.Contains(err.Error(), missing) {
+			t.Fatalf("expected an error naming %s, got %v", missing, err)
+		}
+		if code := errors.ExitCode(err); code != errors.ExitNotInProject {
+			t.Errorf("ExitCode() = %d, want %d", code, errors.ExitNotInProject)
+		}
+	})
+}
+
+func TestApp_Color(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", projectRoot)
+
+	tests := []struct {
+		name        string
+		args        []string
+		noColor     string
+		wantContain []string
+		wantAbsent  []string
+	}{
+		{
+			name:        "always",
+			args:        []string{"--color=always", "doctor"},
+			wantContain: []string{"✅ \033[32mDirectory hooks exists", "❌ \033[31mRepository example-repo.git is not cloned"},
+			wantAbsent:  []string{"[OK]", "[FAIL]"},
+		},
+		{
+			name:        "always overrides NO_COLOR",
+			args:        []string{"--color=always", "doctor"},
+			noColor:     "1",
+			wantContain: []string{"✅"},
+		},
+		{
+			name:        "never",
+			args:        []string{"--color=never", "doctor"},
+			wantContain: []string{"  [OK] Directory hooks exists\n", "  [FAIL] Repository example-repo.git is not cloned", "[FAIL] Some issues were found"},
+			wantAbsent:  []string{"✅", "❌", "\033["},
+		},
+		{
+			name:        "no-color",
+			args:        []string{"--no-color", "doctor"},
+			wantContain: []string{"[OK] Directory hooks exists"},
+			wantAbsent:  []string{"✅"},
+		},
+		{
+			name:        "auto is plain when not a terminal",
+			args:        []string{"doctor"},
+			wantContain: []string{"[OK] Directory hooks exists"},
+			wantAbsent:  []string{"✅"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			var buf bytes.Buffer
+			app := NewApp(&buf, &buf)
+			_ = app.Run(tt.args) // doctor fails because the repository is not cloned
+
+			output := buf.String()
+			for _, want := range tt.wantContain {
+				if !strings.Contains(output, want) {
+					t.Errorf("output does not contain %q:\n%s", want, output)
+				}
+			}
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(output, absent) {
+					t.Errorf("output unexpectedly contains %q:\n%s", absent, output)
+				}
+			}
+		})
+	}
+}
+
+func TestApp_LogFlags(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", projectRoot)
+
+	t.Run("json log file keeps timestamps", func(t *testing.T) {
+		logFile := filepath.Join(testutil.TempDir(t), "devslot.log")
+		testutil.CreateFile(t, logFile, "previous line\n")
+
+		var buf bytes.Buffer
+		if err := NewApp(&buf, &buf).Run([]string{"--log-file", logFile, "--log-format=json", "--log-level=info", "list"}); err != nil {
+			t.Fatalf("App.Run() error = %v", err)
+		}
+
+		content := testutil.ReadFile(t, logFile)
+		if !strings.HasPrefix(content, "previous line\n") {
+			t.Errorf("expected the log file to be appended to, got:\n%s", content)
+		}
+		if !strings.Contains(content, `"msg":"no slots found"`) || !strings.Contains(content, `"time":`) {
+			t.Errorf("expected a JSON record with a timestamp, got:\n%s", content)
+		}
+		if strings.Contains(buf.String(), `"msg"`) {
+			t.Errorf("expected no log records on the terminal, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("level filters records", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewApp(&buf, &buf).Run([]string{"--log-level=error", "list"}); err != nil {
+			t.Fatalf("App.Run() error = %v", err)
+		}
+		if strings.Contains(buf.String(), "level=") {
+			t.Errorf("expected no log records below error, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("verbose is debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := NewApp(&buf, &buf).Run([]string{"--verbose", "list"}); err != nil {
+			t.Fatalf("App.Run() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "level=DEBUG") {
+			t.Errorf("expected debug records with --verbose, got:\n%s", buf.String())
+		}
+		if strings.Contains(buf.String(), "time=") {
+			t.Errorf("expected no timestamps on stderr, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("unwritable log file falls back to stderr", func(t *testing.T) {
+		logFile := filepath.Join(testutil.TempDir(t), "missing", "devslot.log")
+
+		var buf bytes.Buffer
+		if err := NewApp(&buf, &buf).Run([]string{"--log-file", logFile, "--log-level=info", "list"}); err != nil {
+			t.Fatalf("App.Run() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "Warning: failed to open log file "+logFile) {
+			t.Errorf("expected a warning about the log file, got:\n%s", buf.String())
+		}
+		if !strings.Contains(buf.String(), `msg="no slots found"`) {
+			t.Errorf("expected log records on stderr, got:\n%s", buf.String())
+		}
+	})
+}
+
+func TestApp_VersionFlagMatchesCommand(t *testing.T) {
+	for _, flag := range []string{"-v", "--version"} {
+		t.Run(flag, func(t *testing.T) {
+			var flagOut bytes.Buffer
+			app := NewApp(&flagOut, &flagOut)
+			app.SetExitHandler(func(int) {})
+			_ = app.Run([]string{flag})
+
+			var cmdOut bytes.Buffer
+			if err := NewApp(&cmdOut, &cmdOut).Run([]string{"version"}); err != nil {
+				t.Fatalf("App.Run() error = %v", err)
+			}
+
+			got := strings.TrimSpace(flagOut.String())
+			firstLine, _, _ := strings.Cut(cmdOut.String(), "\n")
+			if want := strings.TrimPrefix(firstLine, "devslot version "); got != want {
+				t.Errorf("%s printed %q, but 'devslot version' printed %q", flag, got, want)
+			}
+		})
+	}
+}
+
+func TestApp_ExitCodes(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "unknown command", args: []string{"unknown"}, want: 2},
+		{name: "missing argument", args: []string{"create"}, want: 2},
+		{name: "not in a project", args: []string{"-C", testutil.TempDir(t), "list"}, want: 3},
+		{name: "doctor finds issues", args: []string{"-C", projectRoot, "doctor"}, want: 6},
+		{name: "hooks run without a slot", args: []string{"-C", projectRoot, "hooks", "run", "post-create"}, want: 2},
+		{name: "slot not found", args: []string{"-C", projectRoot, "destroy", "missing"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewApp(&buf, &buf).Run(tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApp_FatalPrintsHint(t *testing.T) {
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+	dir := testutil.TempDir(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "plain",
+			args: []string{"-C", dir, "list"},
+			want: "devslot: error: not in a devslot project: devslot.yaml not found in " + dir + " or any parent directory: configuration not found\n" +
+				"hint: Run 'devslot boilerplate .' to create a new project, or point -C/--project-root at an existing one\n",
+		},
+		{
+			name: "colored",
+			args: []string{"--color=always", "-C", dir, "list"},
+			want: "devslot: error: not in a devslot project: devslot.yaml not found in " + dir + " or any parent directory: configuration not found\n" +
+				"\033[36mhint:\033[0m Run 'devslot boilerplate .' to create a new project, or point -C/--project-root at an existing one\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			app := NewApp(&stdout, &stderr)
+			exitCode := -1
+			app.SetExitHandler(func(code int) { exitCode = code })
+
+			err := app.Run(tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			app.Fatal(err)
+
+			if got := stderr.String(); got != tt.want {
+				t.Errorf("stderr = %q, want %q", got, tt.want)
+			}
+			if exitCode != 3 {
+				t.Errorf("exit code = %d, want 3", exitCode)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && testContainsHelper(s, substr)
 }
@@ -89,3 +430,118 @@ func testContainsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestApp_HookInvocation(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", projectRoot)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories:\n  - name: repo1\n    url: https://github.com/example/repo1.git\n")
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	outFile := filepath.Join(projectRoot, "env.txt")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"),
+		"#!/bin/sh\necho \"$DEVSLOT_COMMAND|$DEVSLOT_INVOCATION_ID\" > \""+outFile+"\"\n")
+
+	var buf bytes.Buffer
+	if err := NewApp(&buf, &buf).Run([]string{"--verbose", "create", "work"}); err != nil {
+		t.Fatalf("App.Run() error = %v\n%s", err, buf.String())
+	}
+
+	// The hook and the log lines of the run share the invocation ID
+	command, id, _ := strings.Cut(strings.TrimSpace(testutil.ReadFile(t, outFile)), "|")
+	if command != "create" || id == "" {
+		t.Errorf("hook got DEVSLOT_COMMAND=%q DEVSLOT_INVOCATION_ID=%q", command, id)
+	}
+	if !strings.Contains(buf.String(), "invocation="+id) {
+		t.Errorf("expected log lines with invocation=%s, got:\n%s", id, buf.String())
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := map[string]string{
+		"create <slot-name>":           "create",
+		"hooks run <hook-type> <slot>": "hooks run",
+		"list":                         "list",
+		"describe <slot-name> <text>":  "describe",
+	}
+	for in, want := range tests {
+		if got := commandName(in); got != want {
+			t.Errorf("commandName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestApp_CreatePrintPath(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+
+	var out, errOut bytes.Buffer
+	app := NewApp(&out, &errOut)
+	if err := app.Run([]string{"-C", projectRoot, "create", "--print-path", "feature-x"}); err != nil {
+		t.Fatalf("App.Run() error = %v\n%s", err, errOut.String())
+	}
+	if want := filepath.Join(projectRoot, "slots", "feature-x") + "\n"; out.String() != want {
+		t.Errorf("output = %q, want only the slot path %q", out.String(), want)
+	}
+}
+
+func TestApp_HooksRunExitCode(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories:\n  - name: repo1\n    url: https://github.com/example/repo1.git\n")
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+
+	var buf bytes.Buffer
+	if err := NewApp(&buf, &buf).Run([]string{"-C", projectRoot, "create", "work"}); err != nil {
+		t.Fatalf("App.Run(create) error = %v\n%s", err, buf.String())
+	}
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-reload"), "#!/bin/sh\nexit 3\n")
+
+	// The command exits with the hook's exit code, not the one of failed lifecycle hooks
+	err := NewApp(&buf, &buf).Run([]string{"-C", projectRoot, "hooks", "run", "post-reload", "work"})
+	if err == nil {
+		t.Fatal("expected the hook failure to be reported")
+	}
+	if got := exitCode(err); got != 3 {
+		t.Errorf("exitCode(%v) = %d, want 3", err, got)
+	}
+}
+
+func TestApp_CreatePorcelainStdout(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	t.Setenv("DEVSLOT_PROJECT_ROOT", "")
+
+	// Capture the stdout of the process, which git commands inherit, like main does
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var errOut bytes.Buffer
+	runErr := NewApp(w, &errOut).Run([]string{"-C", projectRoot, "create", "--porcelain", "feature-x"})
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatalf("App.Run() error = %v\n%s", runErr, errOut.String())
+	}
+	if want := filepath.Join(projectRoot, "slots", "feature-x") + "\n"; string(out) != want {
+		t.Errorf("stdout = %q, want only the slot path %q", out, want)
+	}
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type ArchiveCmd struct {
+	SlotName string `arg:"" help:"Name of the slot to archive"`
+}
+
+func (c *ArchiveCmd) Help() string {
+	return `Parks a slot that is not in use without losing any of its state.
+
+The slot directory, including uncommitted changes, untracked files and each
+worktree's index, is packed into archives/<slot>-<timestamp>.tar.gz. The
+worktrees are then removed, which frees their branches and disk space.
+
+No hooks are run. Bring the slot back with 'devslot restore <slot>'. A slot
+containing the current directory is not archived; change to another directory
+first.`
+}
+
+func (c *ArchiveCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a devslot project: %w", err)
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.Eprintf("Warning: failed to release lock: %v\n", err)
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+
+	if err := refuseCurrentSlot(mgr, "archive", projectRoot, []string{c.SlotName}); err != nil {
+		return err
+	}
+
+	ctx.Printf("Archiving slot '%s'...\n", c.SlotName)
+	ctx.LogInfo("archiving slot", "slot", c.SlotName)
+
+	archivePath, err := mgr.Archive(c.SlotName)
+	if err != nil {
+		return fmt.Errorf("failed to archive slot: %w", err)
+	}
+
+	ctx.Success("Slot '%s' archived to %s", c.SlotName, archivePath)
+	ctx.LogInfo("slot archived", "slot", c.SlotName, "archive", archivePath)
+
+	return nil
+}
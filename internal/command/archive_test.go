@@ -0,0 +1,100 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestArchiveRestore_RoundTrip(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "parked")
+	slotPath := filepath.Join(projectRoot, "slots", "parked")
+	worktreePath := filepath.Join(slotPath, "repo1")
+	bareRepoPath := filepath.Join(projectRoot, "repos", "repo1.git")
+	branch := gitOutput(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+
+	// A modified tracked file, a staged new file and an untracked file
+	testutil.CreateFile(t, filepath.Join(worktreePath, "README.md"), "changed\n")
+	testutil.CreateFile(t, filepath.Join(worktreePath, "staged.txt"), "staged\n")
+	gitOutput(t, worktreePath, "add", "staged.txt")
+	testutil.CreateFile(t, filepath.Join(worktreePath, "notes", "untracked.txt"), "untracked\n")
+	statusBefore := gitOutput(t, worktreePath, "status", "--porcelain")
+
+	var buf bytes.Buffer
+	if err := (&ArchiveCmd{SlotName: "parked"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ArchiveCmd.Run() error = %v", err)
+	}
+	if testutil.DirExists(t, slotPath) {
+		t.Fatal("slot directory should be removed after archiving")
+	}
+	if worktrees := gitOutput(t, bareRepoPath, "worktree", "list", "--porcelain"); strings.Contains(worktrees, worktreePath) {
+		t.Errorf("worktree should be unregistered after archiving, got:\n%s", worktrees)
+	}
+
+	buf.Reset()
+	if err := (&ListCmd{Archived: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "- parked (archived ") {
+		t.Errorf("expected archived slot in list, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&RestoreCmd{Archive: "parked"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("RestoreCmd.Run() error = %v\n%s", err, buf.String())
+	}
+
+	if got := testutil.ReadFile(t, filepath.Join(worktreePath, "notes", "untracked.txt")); got != "untracked\n" {
+		t.Errorf("untracked file = %q", got)
+	}
+	if got := gitOutput(t, worktreePath, "status", "--porcelain"); got != statusBefore {
+		t.Errorf("git status after restore = %q, want %q", got, statusBefore)
+	}
+	if got := gitOutput(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD"); got != branch {
+		t.Errorf("branch after restore = %q, want %q", got, branch)
+	}
+	if worktrees := gitOutput(t, bareRepoPath, "worktree", "list", "--porcelain"); !strings.Contains(worktrees, "worktree "+worktreePath) {
+		t.Errorf("worktree should be registered again, got:\n%s", worktrees)
+	}
+	if out := gitOutput(t, bareRepoPath, "worktree", "prune", "--dry-run", "--verbose"); out != "" {
+		t.Errorf("restored worktree should not be prunable, got:\n%s", out)
+	}
+
+	// The archive is consumed by the restore
+	entries, err := os.ReadDir(filepath.Join(projectRoot, "archives"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected the archive to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestRestoreCmd_Errors(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "busy")
+
+	err := (&RestoreCmd{Archive: "busy"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "archive not found") {
+		t.Errorf("expected archive not found, got %v", err)
+	}
+
+	if err := (&ArchiveCmd{SlotName: "busy"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("ArchiveCmd.Run() error = %v", err)
+	}
+	// A new slot with the same name blocks the restore
+	if err := (&CreateCmd{SlotName: "busy"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	err = (&RestoreCmd{Archive: "busy"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "slot busy already exists") {
+		t.Errorf("expected slot already exists, got %v", err)
+	}
+}
@@ -1,13 +1,35 @@
 package command
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/slot"
 )
 
+// boilerplateTemplates holds the built-in templates, one directory each
+//
+//go:embed all:templates
+var boilerplateTemplates embed.FS
+
 type BoilerplateCmd struct {
-	Dir string `arg:"" required:"" help:"Directory to create project structure in (use . for current directory)"`
+	Dir      string   `arg:"" required:"" help:"Directory to create project structure in (use . for current directory)"`
+	Template string   `default:"default" placeholder:"NAME|PATH" help:"Built-in template (default, minimal) or a directory to copy"`
+	Repo     []string `name:"repo" placeholder:"[NAME=]URL" help:"Add a repository to devslot.yaml (repeatable); the name defaults to the last part of the URL"`
+	Git      bool     `help:"Initialize a git repository and commit the generated files"`
+	Force    bool     `help:"Create the project even inside another devslot project"`
+	ForceGit bool     `name:"force-git" help:"With --git, commit the generated files even when the directory is inside an existing git repository"`
 }
 
 func (c *BoilerplateCmd) Help() string {
@@ -15,45 +37,113 @@ func (c *BoilerplateCmd) Help() string {
 
 Creates the following:
   - devslot.yaml    (project configuration template)
-  - .gitignore      (ignores repos/ and slots/)
+  - .gitignore      (ignores repos/, slots/ and archives/)
   - hooks/          (optional lifecycle scripts)
-    - post-init     (runs after 'devslot init')
-    - post-create   (runs after 'devslot create')
-    - pre-destroy   (runs before 'devslot destroy')
-    - post-destroy  (runs after 'devslot destroy')
-    - post-reload   (runs after 'devslot reload')
-  - repos/          (for bare repositories)
+` + hookListHelp() + `  - repos/          (for bare repositories)
   - slots/          (for worktrees)
 
-Creates the target directory if it doesn't exist.
-All hooks are optional and include helpful examples.`
+Creates the target directory if it doesn't exist. When the directory already
+has a devslot.yaml that sets repos_dir or slots_dir, those directories are
+created instead of repos/ and slots/.
+All hooks are optional and include helpful examples.
+
+--template chooses the files to generate. The built-in "default" template is
+described above; "minimal" has no example hooks. Any other value containing a
+path separator is a directory whose files are copied as they are, keeping
+their modes so that hooks stay executable. {{.ProjectName}} in text files is
+replaced by the name of the target directory. Existing files are never
+overwritten, except that .gitignore gets the template's entries appended when
+it does not ignore repos/ and slots/ yet. A template without devslot.yaml gets
+the one from "minimal".
+
+--repo lists a repository in devslot.yaml, e.g. --repo https://github.com/org/api.git
+or --repo backend=git@github.com:org/api.git. When the configuration file
+already exists, the repositories are added to it; ones already listed with the
+same URL are skipped.
+
+With --git, a git repository is initialized in the directory (on the branch
+set by init.defaultBranch) and the generated files are committed. A directory
+that is already inside a git repository is left alone unless --force-git is
+given, which commits only those files to that repository.
+
+A directory that already is a project root is reported as already initialized;
+only missing pieces are added. Creating a project inside another project is
+refused unless --force is given, since commands run there would no longer find
+the enclosing project.`
+}
+
+// hookListHelp lists the hook types for the help of boilerplate, one per line
+func hookListHelp() string {
+	var b strings.Builder
+	for _, hookType := range hook.Types {
+		fmt.Fprintf(&b, "    - %-13s (runs %s)\n", hookType, hook.Descriptions[hookType])
+	}
+	return b.String()
 }
 
 func (c *BoilerplateCmd) Run(ctx *Context) error {
+	repos, err := c.repositories()
+	if err != nil {
+		return err
+	}
+
 	// Resolve target directory
 	targetDir := c.Dir
 	if !filepath.IsAbs(targetDir) {
-		currentDir, err := os.Getwd()
+		currentDir, err := ctx.WorkingDir()
 		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+			return err
 		}
 		targetDir = filepath.Join(currentDir, targetDir)
 	}
 
+	template, err := c.template(ctx)
+	if err != nil {
+		return err
+	}
+
+	initialized := false
+	if root, err := config.FindProjectRoot(targetDir); err == nil {
+		if root == filepath.Clean(targetDir) {
+			initialized = true
+			describeProject(ctx, root)
+		} else if !c.Force {
+			return errors.NestedProject(targetDir, root)
+		} else {
+			ctx.Warn("Creating a project inside the devslot project at %s", root)
+		}
+	}
 	// Create target directory if it doesn't exist
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
+	// Respect the directory settings of an existing devslot.yaml
+	var cfg *config.Config
+	existingConfig, _ := config.FindFile(targetDir)
+	if existingConfig != "" {
+		var err error
+		cfg, err = config.Load(targetDir)
+		if err != nil {
+			return fmt.Errorf("failed to load existing %s: %w", filepath.Base(existingConfig), err)
+		}
+	}
+
 	// Create directories
 	directories := []string{
-		"hooks",
-		"repos",
-		"slots",
+		filepath.Join(targetDir, "hooks"),
+		cfg.ReposDir(targetDir),
+		cfg.SlotsDir(targetDir),
 	}
 
-	for _, dir := range directories {
-		dirPath := filepath.Join(targetDir, dir)
+	for _, dirPath := range directories {
+		dir := dirPath
+		if rel, err := filepath.Rel(targetDir, dirPath); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = rel
+		}
+		if _, err := os.Stat(dirPath); err == nil {
+			continue
+		}
 		if err := os.MkdirAll(dirPath, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
@@ -61,151 +151,39 @@ func (c *BoilerplateCmd) Run(ctx *Context) error {
 		ctx.LogInfo("directory created", "directory", dir)
 	}
 
-	// Create devslot.yaml
-	devslotYamlPath := filepath.Join(targetDir, "devslot.yaml")
-	devslotYamlContent := `# devslot configuration file
-version: 1
-repositories:
-  # Add your repositories here (without .git suffix)
-  # Example:
-  # - name: my-app
-  #   url: https://github.com/myorg/my-app.git
-  # - name: my-lib
-  #   url: https://github.com/myorg/my-lib.git
-`
-	if err := createFileIfNotExists(devslotYamlPath, devslotYamlContent); err != nil {
-		return fmt.Errorf("failed to create devslot.yaml: %w", err)
-	}
-	ctx.Printf("Created file: devslot.yaml\n")
-	ctx.LogInfo("devslot.yaml created")
-
-	// Create .gitignore
-	gitignorePath := filepath.Join(targetDir, ".gitignore")
-	gitignoreContent := `# devslot directories
-/repos/
-/slots/
-
-# OS files
-.DS_Store
-Thumbs.db
-
-# Editor files
-.vscode/
-.idea/
-*.swp
-*.swo
-*~
-`
-	if err := createOrAppendToFile(gitignorePath, gitignoreContent); err != nil {
-		return fmt.Errorf("failed to update .gitignore: %w", err)
-	}
-	ctx.Printf("Updated file: .gitignore\n")
-	ctx.LogInfo(".gitignore updated")
-
-	// Create hook scripts with executable permissions
-	hookScripts := map[string]string{
-		"post-init": `#!/bin/bash
-# This hook is called after 'devslot init' clones/updates repositories
-# Environment variables:
-#   DEVSLOT_ROOT: The root directory of the project
-#   DEVSLOT_REPOS_DIR: The full path to the repos directory
-#   DEVSLOT_REPOSITORIES: Space-separated list of repository names
-
-# echo "Repositories initialized: $DEVSLOT_REPOSITORIES"
-
-# Example: Set up git config for all repositories
-# for repo in "$DEVSLOT_REPOS_DIR"/*.git; do
-#     if [ -d "$repo" ]; then
-#         echo "Configuring $(basename "$repo")..."
-#         git -C "$repo" config core.hooksPath "$DEVSLOT_ROOT/hooks/git"
-#     fi
-# done
-
-# Example: Fetch all remote branches
-# for repo in "$DEVSLOT_REPOS_DIR"/*.git; do
-#     if [ -d "$repo" ]; then
-#         echo "Fetching all branches for $(basename "$repo")..."
-#         git -C "$repo" fetch --all
-#     fi
-# done
-`,
-		"post-create": `#!/bin/bash
-# This hook is called after a new slot is created
-# Environment variables:
-#   DEVSLOT_ROOT: The root directory of the project
-#   DEVSLOT_SLOT_NAME: The name of the slot
-#   DEVSLOT_SLOT_DIR: The full path to the slot directory
-#   DEVSLOT_REPOS_DIR: The full path to the repos directory
-#   DEVSLOT_REPOSITORIES: Space-separated list of repository names
-
-# echo "Slot $DEVSLOT_SLOT_NAME has been created with repos: $DEVSLOT_REPOSITORIES"
-
-# Example: Install dependencies for each repository
-# for repo in "$DEVSLOT_SLOT_DIR"/*; do
-#     if [ -f "$repo/package.json" ]; then
-#         echo "Installing npm dependencies in $(basename "$repo")..."
-#         (cd "$repo" && npm install)
-#     fi
-# done
-`,
-		"pre-destroy": `#!/bin/bash
-# This hook is called before a slot is destroyed
-# Environment variables:
-#   DEVSLOT_ROOT: The root directory of the project
-#   DEVSLOT_SLOT_NAME: The name of the slot
-#   DEVSLOT_SLOT_DIR: The full path to the slot directory
-#   DEVSLOT_REPOS_DIR: The full path to the repos directory
-#   DEVSLOT_REPOSITORIES: Space-separated list of repository names
-
-# echo "Slot $DEVSLOT_SLOT_NAME will be destroyed (repos: $DEVSLOT_REPOSITORIES)"
-
-# Example: Backup important files
-# backup_dir="$DEVSLOT_ROOT/backups/$DEVSLOT_SLOT_NAME-$(date +%Y%m%d-%H%M%S)"
-# mkdir -p "$backup_dir"
-# echo "Backing up slot to $backup_dir..."
-`,
-		"post-destroy": `#!/bin/bash
-# This hook is called after a slot is destroyed
-# Environment variables:
-#   DEVSLOT_ROOT: The root directory of the project
-#   DEVSLOT_SLOT_NAME: The name of the slot that was destroyed
-#   DEVSLOT_REPOS_DIR: The full path to the repos directory
-#   DEVSLOT_REPOSITORIES: Space-separated list of repository names
-
-# echo "Slot $DEVSLOT_SLOT_NAME has been destroyed"
-
-# Example: Clean up related resources
-# rm -f "$DEVSLOT_ROOT/.cache/$DEVSLOT_SLOT_NAME"*
-
-# Example: Log the destruction
-# echo "$(date): Destroyed slot $DEVSLOT_SLOT_NAME" >> "$DEVSLOT_ROOT/destruction.log"
-
-# Example: Send notification
-# notify-send "DevSlot" "Slot $DEVSLOT_SLOT_NAME was destroyed" || true
-`,
-		"post-reload": `#!/bin/bash
-# This hook is called after a slot is reloaded
-# Environment variables:
-#   DEVSLOT_ROOT: The root directory of the project
-#   DEVSLOT_SLOT_NAME: The name of the slot
-#   DEVSLOT_SLOT_DIR: The full path to the slot directory
-#   DEVSLOT_REPOS_DIR: The full path to the repos directory
-#   DEVSLOT_REPOSITORIES: Space-separated list of repository names
-
-# echo "Slot $DEVSLOT_SLOT_NAME has been reloaded (repos: $DEVSLOT_REPOSITORIES)"
-
-# Example: Sync dependencies or update configurations
-# echo "Updating dependencies..."
-`,
-	}
-
-	for hookName, content := range hookScripts {
-		hookPath := filepath.Join(targetDir, "hooks", hookName)
-		if err := createExecutableFile(hookPath, content); err != nil {
-			return fmt.Errorf("failed to create hook script %s: %w", hookName, err)
+	generated, err := template.copy(ctx, targetDir, existingConfig != "")
+	if err != nil {
+		return err
+	}
+
+	// A template without a configuration file gets the minimal one
+	configPath, _ := config.FindFile(targetDir)
+	if configPath == "" {
+		minimal, _ := builtinTemplate("minimal")
+		if err := minimal.copyFile(ctx, targetDir, config.FileName); err != nil {
+			return err
+		}
+		configPath = filepath.Join(targetDir, config.FileName)
+		generated = append(generated, config.FileName)
+	}
+
+	if len(repos) > 0 {
+		if err := addRepositories(ctx, configPath, repos); err != nil {
+			return err
 		}
-		ctx.Printf("Created hook script: hooks/%s\n", hookName)
-		ctx.LogInfo("hook script created", "hook", hookName)
+	}
+
+	if c.Git {
+		paths := append([]string{filepath.Base(configPath)}, generated...)
+		slices.Sort(paths)
+		if err := c.commitToGit(ctx, targetDir, slices.Compact(paths)); err != nil {
+			return err
+		}
+	}
+
+	if initialized {
+		ctx.LogInfo("boilerplate skipped: already initialized", "directory", targetDir)
+		return nil
 	}
 
 	ctx.Println("\nBoilerplate project structure created successfully!")
@@ -218,47 +196,325 @@ Thumbs.db
 	return nil
 }
 
-func createFileIfNotExists(path, content string) error {
-	if _, err := os.Stat(path); err == nil {
-		return nil // File already exists
+// boilerplateTemplate is a set of files to copy into a new project
+type boilerplateTemplate struct {
+	files fs.FS
+	// builtin templates are embedded, which loses file modes; their hooks are made executable
+	builtin bool
+}
+
+// template resolves --template to a built-in template or a directory
+func (c *BoilerplateCmd) template(ctx *Context) (*boilerplateTemplate, error) {
+	name := c.Template
+	if name == "" {
+		name = "default"
+	}
+	if !strings.ContainsRune(name, '/') && !strings.ContainsRune(name, filepath.Separator) && name != "." && name != ".." {
+		template, err := builtinTemplate(name)
+		if err != nil {
+			return nil, errors.InvalidUsage(fmt.Sprintf("unknown template %q", name),
+				fmt.Sprintf("Available templates: %s; or pass the path of a directory, e.g. ./my-template", strings.Join(builtinTemplateNames(), ", ")))
+		}
+		return template, nil
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	dir := name
+	if !filepath.IsAbs(dir) {
+		currentDir, err := ctx.WorkingDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(currentDir, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, errors.InvalidUsage(fmt.Sprintf("template directory %s does not exist", name),
+			fmt.Sprintf("Pass an existing directory, or one of the built-in templates: %s", strings.Join(builtinTemplateNames(), ", ")))
+	}
+	return &boilerplateTemplate{files: os.DirFS(dir)}, nil
 }
 
-func createExecutableFile(path, content string) error {
-	if _, err := os.Stat(path); err == nil {
-		return nil // File already exists
+// builtinTemplate returns an embedded template by name
+func builtinTemplate(name string) (*boilerplateTemplate, error) {
+	if !slices.Contains(builtinTemplateNames(), name) {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	files, err := fs.Sub(boilerplateTemplates, path.Join("templates", name))
+	if err != nil {
+		return nil, err
 	}
+	return &boilerplateTemplate{files: files, builtin: true}, nil
+}
 
-	return os.WriteFile(path, []byte(content), 0755)
+// builtinTemplateNames returns the names of the embedded templates
+func builtinTemplateNames() []string {
+	entries, _ := boilerplateTemplates.ReadDir("templates")
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
 }
 
-func createOrAppendToFile(path, content string) error {
-	// Check if file exists
-	existingContent := ""
-	if data, err := os.ReadFile(path); err == nil {
-		existingContent = string(data)
+// copy copies the template into targetDir and returns the top-level entries it generated.
+// The template's configuration file is skipped when the project already has one.
+func (t *boilerplateTemplate) copy(ctx *Context, targetDir string, hasConfig bool) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(t.files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(targetDir, filepath.FromSlash(name)), 0755)
+		}
+		if !d.Type().IsRegular() {
+			ctx.Warn("Skipping %s: not a regular file", name)
+			return nil
+		}
+		if hasConfig && (name == config.FileName || name == config.AltFileName) {
+			ctx.LogDebug("keeping existing configuration", "template_file", name)
+			return nil
+		}
+		files = append(files, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy template: %w", err)
 	}
 
-	// Check if devslot entries already exist
-	if contains(existingContent, "/repos/") && contains(existingContent, "/slots/") {
-		return nil // Already configured
+	// Configuration first, then .gitignore, then hooks in lifecycle order
+	slices.SortStableFunc(files, func(a, b string) int {
+		return templateFileRank(a) - templateFileRank(b)
+	})
+
+	var generated []string
+	for _, name := range files {
+		if err := t.copyFile(ctx, targetDir, name); err != nil {
+			return nil, err
+		}
+		top, _, _ := strings.Cut(name, "/")
+		generated = append(generated, top)
 	}
+	return generated, nil
+}
 
-	// Append to existing content or create new
-	var finalContent string
-	if existingContent != "" {
-		finalContent = existingContent
-		if len(existingContent) > 0 && existingContent[len(existingContent)-1] != '\n' {
-			finalContent += "\n"
+// templateFileRank orders the files of a template for copying
+func templateFileRank(name string) int {
+	switch name {
+	case config.FileName, config.AltFileName:
+		return 0
+	case ".gitignore":
+		return 1
+	}
+	if hookName, ok := strings.CutPrefix(name, "hooks/"); ok {
+		if i := slices.Index(hook.Types, hook.Type(hookName)); i >= 0 {
+			return 2 + i
 		}
-		finalContent += "\n" + content
+	}
+	return 2 + len(hook.Types)
+}
+
+// copyFile copies one file of the template, replacing placeholders in text files.
+// Existing files are kept; .gitignore is appended to when it lacks the devslot entries.
+func (t *boilerplateTemplate) copyFile(ctx *Context, targetDir, name string) error {
+	data, err := fs.ReadFile(t.files, name)
+	if err != nil {
+		return err
+	}
+	// Binary files are copied as they are
+	if !bytes.ContainsRune(data, 0) {
+		projectName := filepath.Base(targetDir)
+		data = []byte(strings.NewReplacer("{{.ProjectName}}", projectName, "{{ .ProjectName }}", projectName).Replace(string(data)))
+	}
+
+	targetPath := filepath.Join(targetDir, filepath.FromSlash(name))
+	if name == ".gitignore" {
+		updated, err := createOrAppendToFile(targetPath, string(data), []string{"/repos/", "/slots/"})
+		if err != nil {
+			return fmt.Errorf("failed to update .gitignore: %w", err)
+		}
+		if !updated {
+			return nil
+		}
+		ctx.Printf("Updated file: .gitignore\n")
+		ctx.LogInfo(".gitignore updated")
+		return nil
+	}
+
+	perm := fs.FileMode(0644)
+	if !t.builtin {
+		info, err := fs.Stat(t.files, name)
+		if err != nil {
+			return err
+		}
+		perm = info.Mode().Perm()
+	} else if strings.HasPrefix(name, "hooks/") {
+		perm = 0755
+	}
+	created, err := createFileIfNotExists(targetPath, data, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if !created {
+		return nil
+	}
+
+	if hookName, ok := strings.CutPrefix(name, "hooks/"); ok {
+		ctx.Printf("Created hook script: hooks/%s\n", hookName)
+		ctx.LogInfo("hook script created", "hook", hookName)
 	} else {
-		finalContent = content
+		ctx.Printf("Created file: %s\n", name)
+		ctx.LogInfo("file created", "file", name)
+	}
+	return nil
+}
+
+// repositories parses the --repo flags, deriving missing names from the URL
+func (c *BoilerplateCmd) repositories() ([]config.Repository, error) {
+	var repos []config.Repository
+	seen := make(map[string]string)
+	for _, spec := range c.Repo {
+		name, url, ok := strings.Cut(spec, "=")
+		// A URL may contain '='; only a prefix that cannot be part of a URL is a name
+		if !ok || strings.ContainsAny(name, ":@") {
+			name, url = "", spec
+		}
+		if url == "" {
+			return nil, errors.InvalidUsage(fmt.Sprintf("--repo %q has no URL", spec),
+				"Pass --repo URL or --repo NAME=URL")
+		}
+		if name == "" {
+			bareName, _ := git.ParseRepoURL(url)
+			name = strings.TrimSuffix(bareName, ".git")
+			if name == "" {
+				return nil, errors.InvalidUsage(fmt.Sprintf("cannot derive a repository name from %q", url),
+					"Name the repository with --repo NAME=URL")
+			}
+		}
+		if other, ok := seen[name]; ok {
+			return nil, errors.InvalidUsage(fmt.Sprintf("--repo %s and --repo %s both use the name %s", other, spec, name),
+				"Name one of them with --repo NAME=URL")
+		}
+		seen[name] = spec
+		repos = append(repos, config.Repository{Name: name, URL: url})
+	}
+	return repos, nil
+}
+
+// addRepositories adds repositories to an existing configuration file
+func addRepositories(ctx *Context, configPath string, repos []config.Repository) error {
+	configName := filepath.Base(configPath)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configName, err)
 	}
 
-	return os.WriteFile(path, []byte(finalContent), 0644)
+	updated, added, err := config.AddRepositories(data, repos)
+	if err != nil {
+		return err
+	}
+	if len(added) > 0 {
+		if err := os.WriteFile(configPath, updated, 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", configName, err)
+		}
+	}
+
+	for _, repo := range repos {
+		if slices.ContainsFunc(added, func(r config.Repository) bool { return r.Name == repo.Name }) {
+			ctx.Printf("Added repository to %s: %s\n", configName, repo.Name)
+			ctx.LogInfo("repository added", "name", repo.Name, "url", git.RedactURL(repo.URL))
+		} else {
+			ctx.Printf("Repository %s is already listed in %s\n", repo.Name, configName)
+		}
+	}
+	return nil
+}
+
+// commitToGit commits the generated files, creating a git repository in
+// targetDir unless it is already inside one
+func (c *BoilerplateCmd) commitToGit(ctx *Context, targetDir string, paths []string) error {
+	if topLevel, err := git.TopLevel(targetDir); err == nil {
+		if !c.ForceGit {
+			ctx.Warn("Not committing: %s is inside the git repository %s (use --force-git to commit the generated files there)", targetDir, topLevel)
+			return nil
+		}
+	} else {
+		if err := git.Init(targetDir); err != nil {
+			return errors.WithSuggestion(err, "failed to initialize a git repository",
+				"Run 'git init' in the directory yourself, or omit --git")
+		}
+		ctx.Printf("Initialized git repository in %s\n", targetDir)
+		ctx.LogInfo("git repository initialized", "directory", targetDir)
+	}
+
+	files := strings.Join(paths, ", ")
+	committed, err := git.CommitPaths(targetDir, "Set up devslot project", paths...)
+	if err != nil {
+		return errors.WithSuggestion(err, "failed to commit the generated files",
+			"Check that git user.name and user.email are set, then commit "+files+" yourself")
+	}
+	if !committed {
+		ctx.Printf("Nothing to commit: %s are already committed\n", files)
+		return nil
+	}
+	ctx.Printf("Committed %s\n", files)
+	ctx.LogInfo("generated files committed", "directory", targetDir)
+	return nil
+}
+
+// describeProject summarizes an existing project for a boilerplate run that has nothing to create
+func describeProject(ctx *Context, root string) {
+	ctx.Printf("Already initialized: %s is a devslot project\n", root)
+
+	configPath, _ := config.FindFile(root)
+	cfg, err := config.Load(root)
+	if err != nil {
+		ctx.Printf("  %s: cannot be loaded (%v)\n", filepath.Base(configPath), err)
+	} else if len(cfg.Repositories) == 1 {
+		ctx.Printf("  %s: 1 repository\n", filepath.Base(configPath))
+	} else {
+		ctx.Printf("  %s: %d repositories\n", filepath.Base(configPath), len(cfg.Repositories))
+	}
+
+	var hooks []string
+	runner := hook.NewRunner(root)
+	for _, hookType := range hook.Types {
+		if runner.Exists(hookType) {
+			hooks = append(hooks, string(hookType))
+		}
+	}
+	if len(hooks) == 0 {
+		ctx.Println("  hooks: none")
+	} else {
+		ctx.Printf("  hooks: %s\n", strings.Join(hooks, ", "))
+	}
+
+	if cfg != nil {
+		mgr := slot.NewManager(root)
+		mgr.SetDirs(cfg)
+		if slots, err := mgr.List(); err == nil {
+			ctx.Printf("  slots: %d\n", len(slots))
+		}
+	}
+}
+
+// createFileIfNotExists writes a file unless it exists and reports whether it did
+func createFileIfNotExists(path string, data []byte, perm fs.FileMode) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil // File already exists
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return false, err
+	}
+	// WriteFile applies the umask; the template's mode is what was asked for
+	return true, os.Chmod(path, perm)
 }
 
 func contains(s, substr string) bool {
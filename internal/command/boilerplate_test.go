@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/hook"
 	"github.com/yammerjp/devslot/internal/testutil"
 )
 
@@ -20,7 +24,7 @@ func TestBoilerplateCmd_Run(t *testing.T) {
 	// Run boilerplate command with current directory
 	var buf bytes.Buffer
 	cmd := &BoilerplateCmd{Dir: "."}
-	ctx := &Context{Writer: &buf}
+	ctx := testContext(&buf)
 
 	if err := cmd.Run(ctx); err != nil {
 		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
@@ -39,6 +43,7 @@ func TestBoilerplateCmd_Run(t *testing.T) {
 		"Created hook script: hooks/pre-destroy",
 		"Created hook script: hooks/post-destroy",
 		"Created hook script: hooks/post-reload",
+		"Created hook script: hooks/post-checkout",
 		"Boilerplate project structure created successfully!",
 	}
 
@@ -66,6 +71,7 @@ func TestBoilerplateCmd_Run(t *testing.T) {
 		"hooks/pre-destroy",
 		"hooks/post-destroy",
 		"hooks/post-reload",
+		"hooks/post-checkout",
 	}
 	for _, file := range files {
 		filePath := filepath.Join(tempDir, file)
@@ -76,8 +82,8 @@ func TestBoilerplateCmd_Run(t *testing.T) {
 
 	// Check devslot.yaml content
 	devslotYaml := testutil.ReadFile(t, filepath.Join(tempDir, "devslot.yaml"))
-	if !contains(devslotYaml, "version: 1") {
-		t.Error("devslot.yaml missing 'version: 1'")
+	if !contains(devslotYaml, "version: 2") {
+		t.Error("devslot.yaml missing 'version: 2'")
 	}
 	if !contains(devslotYaml, "repositories:") {
 		t.Error("devslot.yaml missing 'repositories:' section")
@@ -96,6 +102,7 @@ func TestBoilerplateCmd_Run(t *testing.T) {
 		"hooks/pre-destroy",
 		"hooks/post-destroy",
 		"hooks/post-reload",
+		"hooks/post-checkout",
 	}
 	for _, hook := range hookFiles {
 		hookPath := filepath.Join(tempDir, hook)
@@ -120,7 +127,7 @@ func TestBoilerplateCmd_RunTwice(t *testing.T) {
 
 	// Run boilerplate command twice
 	cmd := &BoilerplateCmd{Dir: "."}
-	ctx := &Context{Writer: &bytes.Buffer{}}
+	ctx := testContext(&bytes.Buffer{})
 
 	// First run
 	if err := cmd.Run(ctx); err != nil {
@@ -151,7 +158,7 @@ func TestBoilerplateCmd_ExistingGitignore(t *testing.T) {
 
 	// Run boilerplate command
 	cmd := &BoilerplateCmd{Dir: "."}
-	ctx := &Context{Writer: &bytes.Buffer{}}
+	ctx := testContext(&bytes.Buffer{})
 
 	if err := cmd.Run(ctx); err != nil {
 		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
@@ -177,7 +184,7 @@ func TestBoilerplateCmd_WithSubdirectory(t *testing.T) {
 	// Run boilerplate command with subdirectory
 	var buf bytes.Buffer
 	cmd := &BoilerplateCmd{Dir: "my-project"}
-	ctx := &Context{Writer: &buf}
+	ctx := testContext(&buf)
 
 	if err := cmd.Run(ctx); err != nil {
 		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
@@ -200,6 +207,7 @@ func TestBoilerplateCmd_WithSubdirectory(t *testing.T) {
 		"hooks/pre-destroy",
 		"hooks/post-destroy",
 		"hooks/post-reload",
+		"hooks/post-checkout",
 	}
 
 	for _, file := range expectedFiles {
@@ -227,7 +235,7 @@ func TestBoilerplateCmd_WithAbsolutePath(t *testing.T) {
 	// Run boilerplate command with absolute path
 	var buf bytes.Buffer
 	cmd := &BoilerplateCmd{Dir: projectDir}
-	ctx := &Context{Writer: &buf}
+	ctx := testContext(&buf)
 
 	if err := cmd.Run(ctx); err != nil {
 		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
@@ -243,3 +251,288 @@ func TestBoilerplateCmd_WithAbsolutePath(t *testing.T) {
 		t.Error("devslot.yaml was not created at absolute path")
 	}
 }
+
+func TestBoilerplateCmd_Git(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	// init.defaultBranch of the user
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "init.defaultBranch")
+	t.Setenv("GIT_CONFIG_VALUE_0", "trunk")
+
+	projectDir := filepath.Join(testutil.TempDir(t), "project")
+	defer testutil.Chdir(t, filepath.Dir(projectDir))()
+
+	var buf bytes.Buffer
+	if err := (&BoilerplateCmd{Dir: "project", Git: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+
+	if got := gitOutput(t, projectDir, "branch", "--show-current"); got != "trunk" {
+		t.Errorf("branch = %q, want the configured default branch trunk", got)
+	}
+	if got := gitOutput(t, projectDir, "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("expected one commit, got %s", got)
+	}
+	want := []string{".gitignore", "devslot.yaml", "hooks/post-checkout", "hooks/post-create", "hooks/post-destroy", "hooks/post-init", "hooks/post-reload", "hooks/pre-destroy"}
+	if got := strings.Fields(gitOutput(t, projectDir, "ls-tree", "-r", "--name-only", "HEAD")); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("committed files = %v, want %v", got, want)
+	}
+
+	// Running it again finds nothing new
+	buf.Reset()
+	if err := (&BoilerplateCmd{Dir: "project", Git: true, ForceGit: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Nothing to commit") {
+		t.Errorf("expected nothing to commit, got:\n%s", buf.String())
+	}
+
+	// A directory inside the repository is left alone without --force-git
+	buf.Reset()
+	if err := (&BoilerplateCmd{Dir: "project/nested", Git: true, Force: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Not committing") {
+		t.Errorf("expected a warning about the enclosing repository, got:\n%s", buf.String())
+	}
+	if testutil.DirExists(t, filepath.Join(projectDir, "nested", ".git")) {
+		t.Error("a nested repository was initialized")
+	}
+	if got := gitOutput(t, projectDir, "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("expected no new commit without --force-git, got %s commits", got)
+	}
+
+	buf.Reset()
+	if err := (&BoilerplateCmd{Dir: "project/nested", Git: true, ForceGit: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	files := gitOutput(t, projectDir, "show", "--name-only", "--format=", "HEAD")
+	if !strings.Contains(files, "nested/devslot.yaml") || !strings.Contains(files, "nested/hooks/post-init") {
+		t.Errorf("expected the nested files to be committed, got:\n%s", files)
+	}
+}
+
+func TestBoilerplateCmd_Repo(t *testing.T) {
+	projectDir := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectDir)()
+
+	var buf bytes.Buffer
+	cmd := &BoilerplateCmd{Dir: ".", Repo: []string{"https://github.com/example/api.git", "frontend=git@github.com:example/web.git"}}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		t.Fatalf("generated devslot.yaml does not load: %v", err)
+	}
+	want := []string{"api https://github.com/example/api.git", "frontend git@github.com:example/web.git"}
+	var got []string
+	for _, repo := range cfg.Repositories {
+		got = append(got, repo.Name+" "+repo.URL)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("repositories = %v, want %v", got, want)
+	}
+
+	// Running it again merges into the existing file
+	buf.Reset()
+	cmd = &BoilerplateCmd{Dir: ".", Repo: []string{"https://github.com/example/api", "https://github.com/example/lib.git"}}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{"Repository api is already listed in devslot.yaml", "Added repository to devslot.yaml: lib"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+	if cfg, err = config.Load(projectDir); err != nil || len(cfg.Repositories) != 3 || cfg.Repositories[2].Name != "lib" {
+		t.Errorf("expected lib to be appended, got %+v (err = %v)", cfg, err)
+	}
+}
+
+func TestBoilerplateCmd_RepoErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		repos       []string
+		errContains string
+	}{
+		{name: "duplicate derived name", repos: []string{"https://github.com/a/api.git", "https://github.com/b/api.git"}, errContains: "both use the name api"},
+		{name: "missing URL", repos: []string{"api="}, errContains: "has no URL"},
+		{name: "conflicting URL", repos: []string{"existing=https://github.com/other/existing.git"}, errContains: "already listed with URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := testutil.TempDir(t)
+			defer testutil.Chdir(t, projectDir)()
+			testutil.CreateFile(t, filepath.Join(projectDir, "devslot.yaml"), "version: 2\nrepositories:\n  - name: existing\n    url: https://github.com/example/existing.git\n")
+
+			err := (&BoilerplateCmd{Dir: ".", Repo: tt.repos}).Run(testContext(&bytes.Buffer{}))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("BoilerplateCmd.Run() error = %v, want error containing %q", err, tt.errContains)
+			}
+			if errors.ExitCode(err) != errors.ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d", errors.ExitCode(err), errors.ExitUsage)
+			}
+		})
+	}
+}
+
+func TestBoilerplateCmd_MinimalTemplate(t *testing.T) {
+	projectDir := filepath.Join(testutil.TempDir(t), "myproject")
+
+	var buf bytes.Buffer
+	if err := (&BoilerplateCmd{Dir: projectDir, Template: "minimal"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(projectDir, "hooks"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected an empty hooks directory, got %v (err = %v)", entries, err)
+	}
+	content := testutil.ReadFile(t, filepath.Join(projectDir, "devslot.yaml"))
+	if !strings.Contains(content, "for myproject") {
+		t.Errorf("expected the project name in devslot.yaml:\n%s", content)
+	}
+	if _, err := config.Load(projectDir); err != nil {
+		t.Errorf("generated devslot.yaml does not load: %v", err)
+	}
+	if strings.Contains(buf.String(), "Created hook script") {
+		t.Errorf("expected no hook scripts:\n%s", buf.String())
+	}
+}
+
+func TestBoilerplateCmd_DirectoryTemplate(t *testing.T) {
+	baseDir := testutil.TempDir(t)
+	defer testutil.Chdir(t, baseDir)()
+
+	templateDir := filepath.Join(baseDir, "my-template")
+	testutil.CreateExecutable(t, filepath.Join(templateDir, "hooks", "post-create"), "#!/bin/sh\necho {{.ProjectName}}\n")
+	testutil.CreateFile(t, filepath.Join(templateDir, "docs", "README.md"), "# {{ .ProjectName }}\n")
+	testutil.CreateFile(t, filepath.Join(templateDir, ".gitignore"), "/repos/\n/slots/\n")
+
+	var buf bytes.Buffer
+	cmd := &BoilerplateCmd{Dir: "api", Template: "./my-template", Repo: []string{"https://github.com/example/api.git"}}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+
+	projectDir := filepath.Join(baseDir, "api")
+	info, err := os.Stat(filepath.Join(projectDir, "hooks", "post-create"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("expected hooks/post-create to stay executable, got %v (err = %v)", info, err)
+	}
+	if got := testutil.ReadFile(t, filepath.Join(projectDir, "hooks", "post-create")); got != "#!/bin/sh\necho api\n" {
+		t.Errorf("hooks/post-create = %q", got)
+	}
+	if got := testutil.ReadFile(t, filepath.Join(projectDir, "docs", "README.md")); got != "# api\n" {
+		t.Errorf("docs/README.md = %q", got)
+	}
+
+	// The template has no devslot.yaml, so the minimal one is used
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		t.Fatalf("generated devslot.yaml does not load: %v", err)
+	}
+	if len(cfg.Repositories) != 1 || cfg.Repositories[0].Name != "api" {
+		t.Errorf("repositories = %+v, want api", cfg.Repositories)
+	}
+	for _, want := range []string{"Created file: docs/README.md", "Created hook script: hooks/post-create", "Created file: devslot.yaml"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestBoilerplateCmd_UnknownTemplate(t *testing.T) {
+	baseDir := testutil.TempDir(t)
+	defer testutil.Chdir(t, baseDir)()
+
+	for _, template := range []string{"fancy", "./missing"} {
+		err := (&BoilerplateCmd{Dir: "project", Template: template}).Run(testContext(&bytes.Buffer{}))
+		if errors.ExitCode(err) != errors.ExitUsage {
+			t.Fatalf("--template %s: ExitCode() = %d, want %d (err = %v)", template, errors.ExitCode(err), errors.ExitUsage, err)
+		}
+		if _, hint := errors.Hint(err); !strings.Contains(hint, "default, minimal") {
+			t.Errorf("--template %s: expected the built-in templates in the hint, got %q", template, hint)
+		}
+	}
+	if testutil.DirExists(t, filepath.Join(baseDir, "project")) {
+		t.Error("expected nothing to be created for an unknown template")
+	}
+}
+
+func TestBoilerplateCmd_ExistingProject(t *testing.T) {
+	projectDir := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectDir)()
+	if err := (&BoilerplateCmd{Dir: "."}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+
+	// The project root itself is reported, with nothing created
+	var buf bytes.Buffer
+	if err := (&BoilerplateCmd{Dir: "."}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{"Already initialized: " + projectDir, "devslot.yaml: 0 repositories", "hooks: post-init, post-create", "slots: 0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"Created", "Updated", "successfully"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("expected nothing to be created, got:\n%s", output)
+		}
+	}
+
+	// A subdirectory of the project is refused unless forced
+	err := (&BoilerplateCmd{Dir: "sub"}).Run(testContext(&bytes.Buffer{}))
+	if errors.ExitCode(err) != errors.ExitUsage || !strings.Contains(err.Error(), "inside the devslot project at "+projectDir) {
+		t.Fatalf("BoilerplateCmd.Run() error = %v, want a nested project error", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectDir, "sub")) {
+		t.Error("expected nothing to be created for a refused nested project")
+	}
+
+	buf.Reset()
+	if err := (&BoilerplateCmd{Dir: "sub", Force: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() with --force error = %v", err)
+	}
+	if !testutil.FileExists(t, filepath.Join(projectDir, "sub", "devslot.yaml")) {
+		t.Error("expected a nested project with --force")
+	}
+}
+
+func TestBoilerplateCmd_DefaultHooksMatchHookTypes(t *testing.T) {
+	entries, err := boilerplateTemplates.ReadDir("templates/default/hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	for _, hookType := range hook.Types {
+		if !slices.Contains(names, string(hookType)) {
+			t.Errorf("the default template has no %s hook", hookType)
+			continue
+		}
+		data, err := boilerplateTemplates.ReadFile("templates/default/hooks/" + string(hookType))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "# This hook is called " + hook.Descriptions[hookType] + "\n"; !strings.Contains(string(data), want) {
+			t.Errorf("hooks/%s does not describe when it runs with %q", hookType, want)
+		}
+	}
+	for _, name := range names {
+		if _, err := hook.ParseType(name); err != nil {
+			t.Errorf("the default template has a hook of unknown type %s", name)
+		}
+	}
+}
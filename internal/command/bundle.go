@@ -0,0 +1,35 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// resolveBundleDir returns the absolute path of the --from-bundles directory, or an empty string when unset
+func resolveBundleDir(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve bundle directory: %w", err)
+	}
+	if info, err := os.Stat(absDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("bundle directory %s does not exist", dir)
+	}
+	return absDir, nil
+}
+
+// findBundle returns the bundle of a repository within the bundle directory
+func findBundle(repo config.Repository, bundleDir string) (string, error) {
+	bundlePath := repo.BundlePath(bundleDir)
+	if _, err := os.Stat(bundlePath); err != nil {
+		return "", errors.BundleNotFound(repo.Name, bundlePath)
+	}
+	return bundlePath, nil
+}
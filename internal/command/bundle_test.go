@@ -0,0 +1,109 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestInitCmd_FromBundles(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	// The URL is unreachable; everything must come from the bundle
+	yamlContent := `version: 1
+repositories:
+  - name: repo1
+    url: https://devslot.invalid/example/repo1.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	sourceRepo := testutil.TempDir(t)
+	testutil.InitGitRepo(t, sourceRepo)
+	testutil.CreateFile(t, filepath.Join(sourceRepo, "README.md"), "# Repo")
+	gitOutput(t, sourceRepo, "add", ".")
+	gitOutput(t, sourceRepo, "commit", "-m", "Initial commit")
+	branch := gitOutput(t, sourceRepo, "symbolic-ref", "--short", "HEAD")
+	firstCommit := gitOutput(t, sourceRepo, "rev-parse", "HEAD")
+
+	bundleDir := testutil.TempDir(t)
+	gitOutput(t, sourceRepo, "bundle", "create", filepath.Join(bundleDir, "repo1.bundle"), "--all")
+
+	initCmd := &InitCmd{FromBundles: bundleDir}
+	if err := initCmd.Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+
+	bareRepoPath := filepath.Join(projectRoot, "repos", "repo1.git")
+	if got := gitOutput(t, bareRepoPath, "remote", "get-url", "origin"); got != "https://devslot.invalid/example/repo1.git" {
+		t.Errorf("origin URL = %q, want the configured URL", got)
+	}
+
+	// Creating a slot must not try to reach origin
+	createCmd := &CreateCmd{SlotName: "offline"}
+	if err := createCmd.Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "slots", "offline", "repo1", "README.md")); err != nil {
+		t.Errorf("expected worktree to be created from the bundle: %v", err)
+	}
+
+	// An incremental bundle only carries the new commit
+	testutil.CreateFile(t, filepath.Join(sourceRepo, "CHANGELOG.md"), "update")
+	gitOutput(t, sourceRepo, "add", ".")
+	gitOutput(t, sourceRepo, "commit", "-m", "Second commit")
+	secondCommit := gitOutput(t, sourceRepo, "rev-parse", "HEAD")
+	gitOutput(t, sourceRepo, "bundle", "create", filepath.Join(bundleDir, "repo1.bundle"), firstCommit+".."+branch)
+
+	fetchCmd := &FetchCmd{FromBundles: bundleDir}
+	if err := fetchCmd.Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("FetchCmd.Run() error = %v", err)
+	}
+	if got := gitOutput(t, bareRepoPath, "rev-parse", "refs/remotes/origin/"+branch); got != secondCommit {
+		t.Errorf("origin/%s = %s, want %s", branch, got, secondCommit)
+	}
+
+	// Doctor notes the bundle origin
+	var buf bytes.Buffer
+	_ = (&DoctorCmd{}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "origin has never been fetched directly") {
+		t.Errorf("expected doctor to report bundle initialization, got:\n%s", buf.String())
+	}
+}
+
+func TestInitCmd_FromBundlesMissingBundle(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	yamlContent := `version: 1
+repositories:
+  - name: repo1
+    url: https://devslot.invalid/example/repo1.git
+    bundle: custom/repo1.bundle
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	initCmd := &InitCmd{FromBundles: testutil.TempDir(t)}
+	err := initCmd.Run(testContext(&bytes.Buffer{}))
+	if err == nil {
+		t.Fatal("expected error for missing bundle")
+	}
+	if !strings.Contains(err.Error(), "no bundle found for repo1") || !strings.Contains(err.Error(), "custom/repo1.bundle") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type CheckoutCmd struct {
+	SlotName string `arg:"" help:"Name of the slot"`
+	Branch   string `arg:"" help:"Branch to switch to"`
+	Stash    bool   `help:"Stash uncommitted changes before switching instead of skipping dirty worktrees"`
+	Missing  string `enum:"skip,default,fail" default:"skip" help:"What to do with repositories without the branch: skip, default (switch to the default branch) or fail"`
+}
+
+func (c *CheckoutCmd) Help() string {
+	return `Switches every repository in a slot to the given branch.
+
+Each bare repository is fetched first. Repositories where origin/<branch>
+exists are switched to a local branch tracking it (created if needed).
+Repositories without the branch stay on their current branch and are listed.
+With --missing=default they are switched to their default branch instead, and
+with --missing=fail nothing is switched if any repository lacks the branch.
+Repositories without a worktree in the slot are listed separately and are
+not affected by --missing; 'devslot reload' creates their worktrees.
+
+Worktrees with uncommitted changes are left untouched unless --stash is
+given, in which case the changes are stashed before switching.
+
+The slot's recorded branches are updated and the post-checkout hook runs
+if it exists.`
+}
+
+func (c *CheckoutCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	ctx.Printf("Checking out '%s' in slot '%s'...\n", c.Branch, c.SlotName)
+	ctx.LogInfo("checking out branch", "slot", c.SlotName, "branch", c.Branch, "stash", c.Stash, "missing", c.Missing)
+
+	result, err := mgr.Checkout(c.SlotName, c.Branch, cfg, &slot.CheckoutOptions{Stash: c.Stash, Missing: c.Missing})
+	if err != nil {
+		return fmt.Errorf("failed to checkout branch: %w", err)
+	}
+
+	for _, name := range result.Switched {
+		ctx.Printf("  - %s: switched to %s\n", name, c.Branch)
+	}
+	if len(result.Stashed) > 0 {
+		ctx.Println("\nStashed uncommitted changes in:")
+		for _, name := range result.Stashed {
+			ctx.Printf("  - %s (restore with 'git stash pop')\n", name)
+		}
+	}
+	if len(result.Missing) > 0 && c.Missing == slot.MissingDefault {
+		ctx.Printf("\nBranch '%s' not found, switched to the default branch:\n", c.Branch)
+		for _, name := range result.Missing {
+			if defaultBranch, ok := result.Defaulted[name]; ok {
+				ctx.Printf("  - %s (%s)\n", name, defaultBranch)
+			} else {
+				ctx.Printf("  - %s (left unchanged)\n", name)
+			}
+		}
+	} else if len(result.Missing) > 0 {
+		ctx.Printf("\nBranch '%s' not found, left unchanged:\n", c.Branch)
+		for _, name := range result.Missing {
+			ctx.Printf("  - %s\n", name)
+		}
+	}
+	if len(result.NoWorktree) > 0 {
+		ctx.Printf("\nNo worktree in the slot, left unchanged (run 'devslot reload %s' to create it):\n", c.SlotName)
+		for _, name := range result.NoWorktree {
+			ctx.Printf("  - %s\n", name)
+		}
+	}
+	if len(result.Dirty) > 0 {
+		ctx.Println("\nUncommitted changes, left unchanged (use --stash to switch anyway):")
+		for _, name := range result.Dirty {
+			ctx.Printf("  - %s\n", name)
+		}
+	}
+
+	ctx.Printf("\nSwitched %d of %d repositories to '%s'\n", len(result.Switched), result.Total, c.Branch)
+	ctx.LogInfo("checkout completed", "slot", c.SlotName, "switched", len(result.Switched))
+
+	return nil
+}
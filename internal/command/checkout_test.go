@@ -0,0 +1,205 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/slot"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestCheckoutCmd_Run(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "review")
+
+	bareRepoPath := filepath.Join(projectRoot, "repos", "repo1.git")
+	worktreePath := filepath.Join(projectRoot, "slots", "review", "repo1")
+
+	// Create the branch to review in the bare repository
+	if output, err := exec.Command("git", "-C", bareRepoPath, "branch", "pr-branch", "HEAD").CombinedOutput(); err != nil {
+		t.Fatalf("failed to create branch: %v\n%s", err, output)
+	}
+
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-checkout"), `#!/bin/bash
+echo "$DEVSLOT_BRANCH_NAME" > "$DEVSLOT_ROOT/post-checkout-marker"
+`)
+
+	t.Run("missing branch leaves worktree unchanged", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "no-such-branch"}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("CheckoutCmd.Run() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "Branch 'no-such-branch' not found, left unchanged:\n  - repo1") {
+			t.Errorf("expected repo1 to be listed as missing, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("dirty worktree is skipped without --stash", func(t *testing.T) {
+		testutil.CreateFile(t, filepath.Join(worktreePath, "wip.txt"), "work in progress")
+
+		var buf bytes.Buffer
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "pr-branch"}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("CheckoutCmd.Run() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "use --stash") {
+			t.Errorf("expected dirty worktree to be reported, got:\n%s", buf.String())
+		}
+		if branch := currentBranch(t, worktreePath); branch == "pr-branch" {
+			t.Error("dirty worktree should not have been switched")
+		}
+	})
+
+	t.Run("--stash switches and records the branch", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "pr-branch", Stash: true}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("CheckoutCmd.Run() error = %v", err)
+		}
+		if branch := currentBranch(t, worktreePath); branch != "pr-branch" {
+			t.Errorf("expected worktree on pr-branch, got %q", branch)
+		}
+		if testutil.FileExists(t, filepath.Join(worktreePath, "wip.txt")) {
+			t.Error("expected uncommitted file to be stashed")
+		}
+
+		meta, err := slot.LoadMetadata(filepath.Join(projectRoot, "slots", "review"))
+		if err != nil || meta == nil {
+			t.Fatalf("failed to load metadata: %v", err)
+		}
+		if meta.Branches["repo1"] != "pr-branch" {
+			t.Errorf("recorded branch = %q, want pr-branch", meta.Branches["repo1"])
+		}
+
+		marker := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "post-checkout-marker")))
+		if marker != "pr-branch" {
+			t.Errorf("post-checkout hook got branch %q, want pr-branch", marker)
+		}
+	})
+}
+
+func TestCheckoutCmd_Missing(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: https://github.com/example/app.git
+  - name: lib
+    url: https://github.com/example/lib.git
+`)
+	for _, name := range []string{"app", "lib"} {
+		testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", name+".git"))
+	}
+	if err := (&CreateCmd{SlotName: "review"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	// Only app has the branch under review
+	gitOutput(t, filepath.Join(projectRoot, "repos", "app.git"), "branch", "pr-branch", "HEAD")
+
+	appWorktree := filepath.Join(projectRoot, "slots", "review", "app")
+	libWorktree := filepath.Join(projectRoot, "slots", "review", "lib")
+
+	t.Run("fail aborts before switching anything", func(t *testing.T) {
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "pr-branch", Missing: slot.MissingFail}
+		err := cmd.Run(testContext(&bytes.Buffer{}))
+		if err == nil || !strings.Contains(err.Error(), "branch pr-branch not found") || !strings.Contains(err.Error(), "lib") {
+			t.Fatalf("expected a branch not found error for lib, got %v", err)
+		}
+		if branch := currentBranch(t, appWorktree); branch != "test/review" {
+			t.Errorf("app should not have been switched, got %q", branch)
+		}
+	})
+
+	t.Run("default switches missing repositories to their default branch", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "pr-branch", Missing: slot.MissingDefault}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("CheckoutCmd.Run() error = %v", err)
+		}
+		if branch := currentBranch(t, appWorktree); branch != "pr-branch" {
+			t.Errorf("app branch = %q, want pr-branch", branch)
+		}
+		if branch := currentBranch(t, libWorktree); branch != "main" {
+			t.Errorf("lib branch = %q, want main", branch)
+		}
+		if !strings.Contains(buf.String(), "switched to the default branch:\n  - lib (main)") {
+			t.Errorf("expected lib to be reported on its default branch, got:\n%s", buf.String())
+		}
+
+		meta, err := slot.LoadMetadata(filepath.Join(projectRoot, "slots", "review"))
+		if err != nil || meta == nil {
+			t.Fatalf("failed to load metadata: %v", err)
+		}
+		if meta.Branches["app"] != "pr-branch" || meta.Branches["lib"] != "main" {
+			t.Errorf("recorded branches = %v", meta.Branches)
+		}
+	})
+
+	t.Run("repositories without a worktree are not treated as missing the branch", func(t *testing.T) {
+		if err := os.RemoveAll(libWorktree); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		cmd := &CheckoutCmd{SlotName: "review", Branch: "pr-branch", Missing: slot.MissingFail}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("CheckoutCmd.Run() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "No worktree in the slot, left unchanged (run 'devslot reload review' to create it):\n  - lib") {
+			t.Errorf("expected lib to be reported without a worktree, got:\n%s", buf.String())
+		}
+		if strings.Contains(buf.String(), "not found") {
+			t.Errorf("lib should not be reported as missing the branch, got:\n%s", buf.String())
+		}
+	})
+}
+
+func TestCheckoutCmd_GroupSlot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: https://github.com/example/app.git
+    groups: [web]
+  - name: lib
+    url: https://github.com/example/lib.git
+    groups: [core]
+`)
+	for _, name := range []string{"app", "lib"} {
+		testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", name+".git"))
+	}
+	if err := (&CreateCmd{SlotName: "ui", Group: []string{"web"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	gitOutput(t, filepath.Join(projectRoot, "repos", "app.git"), "branch", "pr-branch", "HEAD")
+
+	// Only the repositories of the slot are counted
+	var buf bytes.Buffer
+	if err := (&CheckoutCmd{SlotName: "ui", Branch: "pr-branch"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CheckoutCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Switched 1 of 1 repositories to 'pr-branch'") {
+		t.Errorf("expected the summary to count the slot's repositories, got:\n%s", buf.String())
+	}
+}
+
+func currentBranch(t *testing.T, worktreePath string) string {
+	t.Helper()
+	output, err := exec.Command("git", "-C", worktreePath, "branch", "--show-current").Output()
+	if err != nil {
+		t.Fatalf("failed to get current branch: %v", err)
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -1,17 +1,54 @@
 package command
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+// Verbosity controls how much informational output commands print
+type Verbosity int
+
+const (
+	// VerbosityNormal prints informational output
+	VerbosityNormal Verbosity = iota
+	// VerbosityQuiet suppresses informational output; results, warnings and errors still surface
+	VerbosityQuiet
 )
 
 // Context provides shared resources to commands
 type Context struct {
-	Writer io.Writer
-	Logger *slog.Logger
-	ctx    context.Context
+	// In supplies answers to confirmation prompts
+	In io.Reader
+	// Out receives command output such as listings and summaries
+	Out io.Writer
+	// Err receives warnings and prompts so they stay visible when Out is piped
+	Err     io.Writer
+	Logger  *slog.Logger
+	Verbose bool
+	// Verbosity decides whether Printf and Println produce output
+	Verbosity Verbosity
+	// Color decides whether status lines use color and emoji; empty means ColorAuto
+	Color ColorMode
+	// Dir is the directory commands start from (-C/--project-root); empty means the current directory
+	Dir string
+	// ConfigPath is the configuration file to use instead of discovering devslot.yaml (--config)
+	ConfigPath string
+	ctx        context.Context
+	// finder caches the project root lookups of this command
+	finder *config.Finder
 }
 
 // WithContext returns the underlying context.Context
@@ -27,14 +64,202 @@ func (c *Context) SetContext(ctx context.Context) {
 	c.ctx = ctx
 }
 
-// Printf writes formatted output to the user
+// WorkingDir returns the absolute directory commands start from
+func (c *Context) WorkingDir() (string, error) {
+	if c.Dir != "" {
+		return filepath.Abs(c.Dir)
+	}
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return currentDir, nil
+}
+
+// CurrentSlot returns the name of the slot WorkingDir is in, or an empty string when it
+// is not inside a slot
+func (c *Context) CurrentSlot(mgr *slot.Manager) string {
+	dir, err := c.WorkingDir()
+	if err != nil {
+		return ""
+	}
+	return mgr.SlotAt(dir)
+}
+
+// slotOrCurrent returns name, or the slot WorkingDir is in when name is empty. action
+// completes the suggestion when neither is given, e.g. "reload".
+func (c *Context) slotOrCurrent(mgr *slot.Manager, name, action string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if current := c.CurrentSlot(mgr); current != "" {
+		c.LogDebug("using the slot of the current directory", "slot", current)
+		return current, nil
+	}
+	return "", errors.InvalidUsage("no slot given and the current directory is not inside a slot",
+		fmt.Sprintf("Pass the name of the slot to %s, or run the command inside a slot", action))
+}
+
+// refuseCurrentSlot fails when the current directory of the process is inside one of
+// the slots, since removing that slot would leave the shell in a deleted directory and
+// break the git commands run from it. It looks at the process, not WorkingDir, as -C
+// doesn't move the shell.
+func refuseCurrentSlot(mgr *slot.Manager, action, projectRoot string, names []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if current := mgr.SlotAt(dir); current != "" && slices.Contains(names, current) {
+		return errors.InsideSlot(action, current, projectRoot)
+	}
+	return nil
+}
+
+// Interactive reports whether a user can answer prompts, i.e. In is a terminal
+func (c *Context) Interactive() bool {
+	return isTerminal(c.In)
+}
+
+// Confirm asks a yes/no question on Err and reads the answer from In.
+// Only "y" and "yes" (in any case) confirm; an empty answer or end of input declines.
+func (c *Context) Confirm(question string) (bool, error) {
+	fmt.Fprintf(c.Err, "%s [y/N] ", question)
+	if c.In == nil {
+		fmt.Fprintln(c.Err)
+		return false, nil
+	}
+
+	answer, err := bufio.NewReader(c.In).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	if err == io.EOF {
+		fmt.Fprintln(c.Err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// FindProjectRoot searches upward from WorkingDir for the project root.
+// With ConfigPath set, the project root is the directory containing that file.
+func (c *Context) FindProjectRoot() (string, error) {
+	if c.ConfigPath != "" {
+		c.LogDebug("using configuration file", "path", c.ConfigPath)
+		return config.ProjectRootOf(c.configPath())
+	}
+
+	dir, err := c.WorkingDir()
+	if err != nil {
+		return "", err
+	}
+	c.LogDebug("looking for project root", "dir", dir)
+	if c.finder == nil {
+		c.finder = &config.Finder{}
+	}
+	return c.finder.FindProjectRoot(dir)
+}
+
+// ConfigFile returns the configuration file of the project at projectRoot
+func (c *Context) ConfigFile(projectRoot string) string {
+	if c.ConfigPath != "" {
+		return c.configPath()
+	}
+	if path, _ := config.FindFile(projectRoot); path != "" {
+		return path
+	}
+	return filepath.Join(projectRoot, config.FileName)
+}
+
+// LoadConfig loads the configuration of the project at projectRoot.
+// It warns when the project has both devslot.yaml and devslot.yml.
+func (c *Context) LoadConfig(projectRoot string) (*config.Config, error) {
+	if c.ConfigPath == "" {
+		if _, ambiguous := config.FindFile(projectRoot); ambiguous {
+			c.Eprintf("Warning: both %s and %s exist in %s; using %s\n", config.FileName, config.AltFileName, projectRoot, config.FileName)
+			c.LogWarn("ambiguous configuration files", "dir", projectRoot)
+		}
+	}
+	cfg, err := config.LoadFile(projectRoot, c.ConfigFile(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	// Every git command of the project gets its git_env and runs its git_binary
+	git.SetEnv(cfg.GitEnv)
+	git.SetBinary(cfg.GitBinary)
+	c.LogDebug("git binary", "path", git.Binary())
+	for _, name := range slices.Sorted(maps.Keys(cfg.GitEnv)) {
+		c.LogDebug("git environment override", "name", name, "value", git.RedactEnv(name, cfg.GitEnv[name]))
+	}
+	return cfg, nil
+}
+
+// RequireGit fails with installation instructions when the git devslot runs is
+// missing, before commands take the lock. The configuration is only loaded under
+// the lock, so git_binary is read here on its own; a configuration that fails to
+// load is reported by LoadConfig instead.
+func (c *Context) RequireGit(projectRoot string) error {
+	if cfg, err := config.LoadFile(projectRoot, c.ConfigFile(projectRoot)); err == nil {
+		git.SetBinary(cfg.GitBinary)
+	}
+	path, err := git.CheckInstalled()
+	if err != nil {
+		return err
+	}
+	c.LogDebug("found git", "path", path)
+	return nil
+}
+
+// configPath resolves a relative ConfigPath against WorkingDir
+func (c *Context) configPath() string {
+	if filepath.IsAbs(c.ConfigPath) {
+		return c.ConfigPath
+	}
+	if dir, err := c.WorkingDir(); err == nil {
+		return filepath.Join(dir, c.ConfigPath)
+	}
+	return c.ConfigPath
+}
+
+// Printf writes formatted informational output to the user
 func (c *Context) Printf(format string, args ...interface{}) {
-	fmt.Fprintf(c.Writer, format, args...)
+	if c.Verbosity == VerbosityQuiet {
+		return
+	}
+	fmt.Fprintf(c.Out, format, args...)
 }
 
-// Println writes a line to the user
+// Println writes an informational line to the user
 func (c *Context) Println(args ...interface{}) {
-	fmt.Fprintln(c.Writer, args...)
+	if c.Verbosity == VerbosityQuiet {
+		return
+	}
+	fmt.Fprintln(c.Out, args...)
+}
+
+// Resultln writes a line of a command's result, such as a path meant for scripts.
+// Results are printed regardless of Verbosity.
+func (c *Context) Resultln(args ...interface{}) {
+	fmt.Fprintln(c.Out, args...)
+}
+
+// Porcelain switches the context to machine-friendly output:
+// informational output is suppressed and only results are printed
+func (c *Context) Porcelain() {
+	c.Verbosity = VerbosityQuiet
+}
+
+// Eprintf writes a formatted warning or prompt to the user
+func (c *Context) Eprintf(format string, args ...interface{}) {
+	fmt.Fprintf(c.Err, format, args...)
+}
+
+// Eprintln writes a warning or prompt line to the user
+func (c *Context) Eprintln(args ...interface{}) {
+	fmt.Fprintln(c.Err, args...)
 }
 
 // LogInfo logs an informational message
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// testContext returns a Context that writes both output and warnings to buf
+func testContext(buf *bytes.Buffer) *Context {
+	return &Context{Out: buf, Err: buf}
+}
+
+// fakeTerminal is stdin typed by a user at a terminal
+type fakeTerminal struct {
+	*strings.Reader
+}
+
+func (fakeTerminal) IsTerminal() bool { return true }
+
+func TestContext_Confirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"\n", false},
+		{"n\n", false},
+		{"sure\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		var errOut bytes.Buffer
+		ctx := &Context{In: strings.NewReader(tt.input), Out: &bytes.Buffer{}, Err: &errOut}
+		got, err := ctx.Confirm("Proceed?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) error = %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.HasPrefix(errOut.String(), "Proceed? [y/N] ") {
+			t.Errorf("prompt = %q", errOut.String())
+		}
+	}
+
+	if (&Context{In: strings.NewReader("")}).Interactive() {
+		t.Error("a plain reader should not be interactive")
+	}
+	if !(&Context{In: fakeTerminal{strings.NewReader("")}}).Interactive() {
+		t.Error("a terminal should be interactive")
+	}
+}
+
+func TestContext_ConfigPath(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories:\n  - example/app\n")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "scratch", "devslot.scratch.yaml"), "version: 1\nrepositories:\n  - example/experiment\n")
+
+	ctx := &Context{ConfigPath: filepath.Join("scratch", "devslot.scratch.yaml")}
+	root, err := ctx.FindProjectRoot()
+	if err != nil {
+		t.Fatalf("FindProjectRoot() error = %v", err)
+	}
+	if want := filepath.Join(projectRoot, "scratch"); root != want {
+		t.Errorf("FindProjectRoot() = %q, want %q", root, want)
+	}
+
+	cfg, err := ctx.LoadConfig(root)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(cfg.Repositories) != 1 || cfg.Repositories[0].Name != "experiment" {
+		t.Errorf("LoadConfig() read the wrong file: %+v", cfg.Repositories)
+	}
+
+	ctx.ConfigPath = "scratch"
+	if _, err := ctx.FindProjectRoot(); err == nil || !strings.Contains(err.Error(), "cannot read configuration file") {
+		t.Errorf("FindProjectRoot() error = %v, want unreadable file error", err)
+	}
+}
+
+func TestContext_Streams(t *testing.T) {
+	var out, errOut bytes.Buffer
+	ctx := &Context{Out: &out, Err: &errOut}
+
+	ctx.Printf("result %d\n", 1)
+	ctx.Println("done")
+	ctx.Eprintf("Warning: %s\n", "careful")
+	ctx.Eprintln("Continue?")
+
+	if got, want := out.String(), "result 1\ndone\n"; got != want {
+		t.Errorf("Out = %q, want %q", got, want)
+	}
+	if got, want := errOut.String(), "Warning: careful\nContinue?\n"; got != want {
+		t.Errorf("Err = %q, want %q", got, want)
+	}
+}
+
+func TestStatusCmd_DriftWarningOnErr(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "drift-slot")
+
+	worktree := filepath.Join(projectRoot, "slots", "drift-slot", "repo1")
+	gitOutput(t, worktree, "checkout", "-b", "other")
+
+	var out, errOut bytes.Buffer
+	if err := (&StatusCmd{SlotName: "drift-slot"}).Run(&Context{Out: &out, Err: &errOut}); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+
+	if strings.Contains(out.String(), "Warning") {
+		t.Errorf("expected no warning on Out, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "repo1") {
+		t.Errorf("expected status table on Out, got:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "Warning: 1 worktree(s) are not on the branch recorded at creation") {
+		t.Errorf("expected drift warning on Err, got:\n%s", errOut.String())
+	}
+}
+
+func TestDestroyCmd_WarningsOnErr(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "broken")
+
+	// A failing post-destroy hook only produces a warning
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-destroy"), "#!/bin/sh\nexit 1\n")
+
+	var out, errOut bytes.Buffer
+	if err := (&DestroyCmd{Slots: []string{"broken"}}).Run(&Context{Out: &out, Err: &errOut}); err != nil {
+		t.Fatalf("destroy failed: %v", err)
+	}
+
+	if !strings.Contains(errOut.String(), "Warning: post-destroy hook failed") {
+		t.Errorf("expected hook warning on Err, got:\n%s", errOut.String())
+	}
+	if strings.Contains(out.String(), "Warning") {
+		t.Errorf("expected no warning on Out, got:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "slots", "broken")); !os.IsNotExist(err) {
+		t.Errorf("expected slot directory to be removed")
+	}
+}
@@ -2,48 +2,112 @@ package command
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/yammerjp/devslot/internal/config"
 	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
 	"github.com/yammerjp/devslot/internal/lock"
 	"github.com/yammerjp/devslot/internal/slot"
+	"github.com/yammerjp/devslot/internal/version"
 )
 
 type CreateCmd struct {
-	SlotName string `arg:"" help:"Name of the slot to create"`
-	Branch   string `short:"b" help:"Branch to checkout (if not specified, creates new branch named devslot/<git-email-localpart>/<slot-name>)"`
+	SlotName    string   `arg:"" help:"Name of the slot to create"`
+	Branch      string   `short:"b" help:"Branch to checkout (if not specified, creates a new branch named from the branch prefix template)"`
+	Porcelain   bool     `aliases:"print-path" help:"Print only the absolute slot path on success"`
+	FreshBranch bool     `name:"fresh-branch" help:"Create a new branch with a numeric suffix (-2, -3, ...) when the branch name already exists"`
+	Description string   `help:"What the slot is for, shown by 'devslot list'"`
+	Group       []string `help:"Only create worktrees for repositories in these groups (repeatable)" placeholder:"GROUP"`
+	DryRun      bool     `name:"dry-run" help:"Show the worktrees and branch that would be created without creating them"`
+	Template    string   `help:"Create the slot from a template in slot_templates of devslot.yaml"`
+	KeepPartial bool     `name:"keep-partial" help:"Keep the worktrees that were created when others fail, to finish the slot with 'devslot reload'"`
+	Timings     bool     `help:"Print how long the worktree of each repository took"`
 }
 
 func (c *CreateCmd) Help() string {
 	return `Creates a new slot with git worktrees for all repositories.
 
-When -b/--branch is not specified, a new branch is created with the naming pattern:
-  devslot/<prefix>/<slot-name>
-
-The <prefix> is determined by (in order of precedence):
+When -b/--branch is not specified, a new branch is created from a branch name
+template followed by the slot name. The template is determined by (in order of precedence):
   1. DEVSLOT_BRANCH_PREFIX environment variable
-  2. git config devslot.branchPrefix
-  3. Local part of git user.email (e.g., "john.doe" from "john.doe@example.com")
-  4. "user" (fallback)
+  2. branch_prefix in devslot.yaml (shared by the project)
+  3. git config devslot.branchPrefix
+  4. "devslot/{user}/" (default)
+
+Templates may contain placeholders:
+  {user}         Local part of git user.email (e.g., "john-doe" from "john.doe@example.com")
+  {slot}         The slot name; when present, the slot name is not appended again
+  {date}         Today's date as 2006-01-02
+  {date:LAYOUT}  Today's date in a Go time layout (e.g., {date:20060102})
 
 Example: For user "john.doe@example.com" creating slot "feature-x":
-  Branch name: devslot/john-doe/feature-x`
+  Branch name: devslot/john-doe/feature-x
+
+If the branch already exists, for example after destroying and recreating a slot,
+it is checked out again; a branch that only exists on origin is tracked. With
+--fresh-branch, a numeric suffix is added instead (devslot/john-doe/feature-x-2).
+
+With --group, only repositories tagged with one of the groups in devslot.yaml
+get a worktree. The groups are recorded in the slot, so reload, status and
+the other slot commands keep to the same repositories.
+
+Each worktree is listed with its branch and path, followed by the command to
+change into the slot. With --porcelain (or its alias --print-path), only the
+absolute slot path is printed, and hook output is sent to stderr, so the result
+can be used as cd "$(devslot create --print-path x)".
+
+With --template, the repositories, per-repository branches and description
+of a template under slot_templates in devslot.yaml are used. --branch,
+--group and --description take precedence over the template.
+
+The files listed under seed_files in devslot.yaml are copied into the slot
+once the worktrees exist, before the post-create hook runs. Creating the slot
+fails when one of them is missing from the project. The paths listed under
+shared_links become symlinks into the project's shared cache in .devslot/cache.
+
+Before anything is created, the slot is checked as a whole: repositories that
+are not cloned, branches checked out in another worktree, bases that cannot
+exist and missing seed files are all reported in one error.
+
+When a worktree cannot be created, the slot is removed again. With
+--keep-partial, the worktrees that were created are kept and the slot is
+marked incomplete; once the problem is fixed, 'devslot reload' creates the
+missing worktrees on the slot's branch and runs the post-reload hook. The
+post-create hook does not run for an incomplete slot.
+
+With --timings, how long the worktree of each repository took is printed on
+stderr, slowest first. The table is also printed without it when creating the
+slot took more than 30 seconds, unless --quiet is given.
+
+With --dry-run, the worktrees, the resolved branch name and prefix and the
+hooks that would run are shown; nothing is created and no hook runs.`
 }
 
 func (c *CreateCmd) Run(ctx *Context) error {
-	// Find project root
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+	if c.Porcelain {
+		ctx.Porcelain()
+	}
+	if c.FreshBranch && c.Branch != "" {
+		return errors.InvalidUsage("--fresh-branch cannot be combined with --branch",
+			"--fresh-branch only applies to the branch named from the branch prefix")
+	}
+	if c.DryRun && c.Porcelain {
+		return errors.InvalidUsage("--dry-run cannot be combined with --porcelain",
+			"--porcelain prints the path of a slot that was created")
 	}
 
-	projectRoot, err := config.FindProjectRoot(currentDir)
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
 	if err != nil {
 		return err // config.FindProjectRoot already returns a user-friendly error
 	}
 
+	// Fail before cloning or creating worktrees when git is missing
+	if err := ctx.RequireGit(projectRoot); err != nil {
+		return err
+	}
+
 	// Acquire lock
 	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
 	if err := lockFile.Acquire(); err != nil {
@@ -56,34 +120,108 @@ func (c *CreateCmd) Run(ctx *Context) error {
 	}()
 
 	// Load configuration
-	cfg, err := config.Load(projectRoot)
+	cfg, err := ctx.LoadConfig(projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Create slot
 	mgr := slot.NewManager(projectRoot)
-	ctx.Printf("Creating slot '%s'...\n", c.SlotName)
-	ctx.LogInfo("creating slot", "name", c.SlotName, "branch", c.Branch)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+	mgr.Timings = ctx.newTimings()
+	if c.Porcelain {
+		mgr.SetHookStdout(ctx.Err)
+	}
 
 	// Prepare options
 	opts := &slot.CreateOptions{
-		Branch: c.Branch,
+		Branch:       c.Branch,
+		FreshBranch:  c.FreshBranch,
+		Description:  c.Description,
+		Groups:       c.Group,
+		BranchPrefix: git.GetBranchPrefix(cfg.BranchPrefix),
+		Version:      version.Version,
+		Args:         invocationArgs(),
+		KeepPartial:  c.KeepPartial,
+	}
+	if c.Template != "" {
+		if err := opts.ApplyTemplate(cfg, c.Template); err != nil {
+			return err
+		}
 	}
 
-	// Show repositories that will be created
-	ctx.LogDebug("repositories to create", "count", len(cfg.Repositories))
-	for _, repo := range cfg.Repositories {
-		ctx.Printf("  - %s\n", repo.Name)
+	if c.DryRun {
+		plan, err := mgr.PlanCreate(c.SlotName, cfg, opts)
+		if err != nil {
+			return fmt.Errorf("failed to create slot: %w", err)
+		}
+		printCreatePlan(ctx, plan)
+		ctx.Println(dryRunNotice)
+		return nil
 	}
 
-	if err := mgr.Create(c.SlotName, cfg, opts); err != nil {
+	ctx.Printf("Creating slot '%s'...\n", c.SlotName)
+	ctx.LogInfo("creating slot", "name", c.SlotName, "branch", c.Branch, "template", c.Template)
+
+	result, err := mgr.Create(c.SlotName, cfg, opts)
+	if err != nil {
+		if result != nil {
+			// The slot was kept with the worktrees created before the failure
+			for _, repo := range result.Repos {
+				ctx.Printf("  %s: %s -> %s\n", repo.Name, repo.Branch, displayPath(projectRoot, filepath.Join(result.SlotPath, repo.Name)))
+			}
+		}
 		return fmt.Errorf("failed to create slot: %w", err)
 	}
 
-	ctx.Printf("\nSlot '%s' created successfully!\n", c.SlotName)
-	ctx.Printf("You can now work in: %s/slots/%s\n", projectRoot, c.SlotName)
-	ctx.LogInfo("slot created successfully", "name", c.SlotName, "path", filepath.Join(projectRoot, "slots", c.SlotName))
+	existing := 0
+	for _, repo := range result.Repos {
+		line := fmt.Sprintf("  %s: %s -> %s", repo.Name, repo.Branch, displayPath(projectRoot, filepath.Join(result.SlotPath, repo.Name)))
+		if repo.ExistingBranch {
+			line += " (existing branch)"
+			existing++
+		}
+		ctx.Println(line)
+	}
+	for _, path := range result.Linked {
+		ctx.Printf("  Linked %s to the shared cache\n", path)
+	}
+	for _, path := range result.Seeded {
+		ctx.Printf("  Seeded %s\n", path)
+	}
+
+	ctx.Println()
+	summary := pluralize(len(result.Repos), "worktree")
+	if existing > 0 {
+		summary += fmt.Sprintf(", %d on an existing branch", existing)
+	}
+	ctx.Success("Slot '%s' created successfully with %s!", c.SlotName, summary)
+	ctx.Printf("You can now work in: %s\n", result.SlotPath)
+	ctx.Printf("  cd %s\n", shellQuote(result.SlotPath))
+	ctx.LogInfo("slot created successfully", "name", c.SlotName, "path", result.SlotPath, "branch", result.Branch, "worktrees", len(result.Repos))
+	ctx.printTimings(mgr.Timings, c.Timings)
+	if c.Porcelain {
+		ctx.Resultln(result.SlotPath)
+	}
 
 	return nil
 }
+
+// displayPath returns path relative to the project root when it is inside it, so
+// output stays short; paths elsewhere, such as a relocated slots directory, stay absolute
+func displayPath(projectRoot, path string) string {
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// shellQuote quotes a path for a POSIX shell when it contains characters the shell would interpret
+func shellQuote(path string) string {
+	if path != "" && strings.Trim(path, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/._-+:@") == "" {
+		return path
+	}
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
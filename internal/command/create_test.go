@@ -2,12 +2,15 @@ package command
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/slot"
 	"github.com/yammerjp/devslot/internal/testutil"
 )
 
@@ -88,18 +91,19 @@ repositories:
 				testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
 
 				// Create a bare repository with a branch
-				repo1Path := filepath.Join(projectRoot, "repos", "repo1.git")
-				if err := os.MkdirAll(filepath.Dir(repo1Path), 0755); err != nil {
-					return err
-				}
-				testutil.InitBareRepo(t, repo1Path)
+				testutil.NewRepoFixture(t).
+					Branch("feature-branch").Commit("feature.txt", "feature").
+					Push(filepath.Join(projectRoot, "repos", "repo1.git"))
 				return nil
 			},
 			wantErr: false,
 			validateFunc: func(t *testing.T, projectRoot string) {
-				slotPath := filepath.Join(projectRoot, "slots", "feature-slot")
-				if _, err := os.Stat(slotPath); os.IsNotExist(err) {
-					t.Error("expected slot directory to exist")
+				worktreePath := filepath.Join(projectRoot, "slots", "feature-slot", "repo1")
+				if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "feature-branch" {
+					t.Errorf("expected branch feature-branch, got %q", got)
+				}
+				if !testutil.FileExists(t, filepath.Join(worktreePath, "feature.txt")) {
+					t.Error("expected the commit of feature-branch to be checked out")
 				}
 			},
 		},
@@ -180,7 +184,7 @@ repositories: []
 				SlotName: tt.slotName,
 				Branch:   tt.branch,
 			}
-			ctx := &Context{Writer: &buf, Logger: nil}
+			ctx := testContext(&buf)
 
 			err := cmd.Run(ctx)
 			if (err != nil) != tt.wantErr {
@@ -199,3 +203,700 @@ repositories: []
 		})
 	}
 }
+
+func TestCreateCmd_HookEnvironment(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is not installed")
+	}
+
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	yamlContent := `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+	repo1Path := filepath.Join(projectRoot, "repos", "repo1.git")
+	if err := os.MkdirAll(filepath.Dir(repo1Path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.InitBareRepo(t, repo1Path)
+
+	hookScript := `#!/bin/bash
+set -e
+echo "$DEVSLOT_HOOK_TYPE" > "$DEVSLOT_ROOT/hook-type"
+echo "$DEVSLOT_BRANCH_NAME" > "$DEVSLOT_ROOT/hook-branch"
+echo "$DEVSLOT_REPOSITORIES_JSON" | jq -r '.[] | "\(.name)|\(.url)|\(.worktree_path)|\(.branch)"' > "$DEVSLOT_ROOT/hook-repos"
+`
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), hookScript)
+
+	var buf bytes.Buffer
+	cmd := &CreateCmd{SlotName: "env-slot", Branch: "feature-env"}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+
+	if got := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "hook-type"))); got != "post-create" {
+		t.Errorf("DEVSLOT_HOOK_TYPE = %q, want %q", got, "post-create")
+	}
+	if got := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "hook-branch"))); got != "feature-env" {
+		t.Errorf("DEVSLOT_BRANCH_NAME = %q, want %q", got, "feature-env")
+	}
+
+	worktreePath := filepath.Join(projectRoot, "slots", "env-slot", "repo1")
+	want := "repo1|https://github.com/example/repo1.git|" + worktreePath + "|feature-env"
+	if got := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "hook-repos"))); got != want {
+		t.Errorf("DEVSLOT_REPOSITORIES_JSON parsed = %q, want %q", got, want)
+	}
+}
+
+func TestCreateCmd_ProjectBranchPrefix(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "")
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+branch_prefix: "team/{slot}/work"
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	repo1Path := filepath.Join(projectRoot, "repos", "repo1.git")
+	if err := os.MkdirAll(filepath.Dir(repo1Path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.InitBareRepo(t, repo1Path)
+
+	if err := (&CreateCmd{SlotName: "feature-x"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	worktreePath := filepath.Join(projectRoot, "slots", "feature-x", "repo1")
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "team/feature-x/work" {
+		t.Errorf("branch = %q, want %q", got, "team/feature-x/work")
+	}
+
+	// DEVSLOT_BRANCH_PREFIX still overrides the project setting
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "mine/")
+	if err := (&CreateCmd{SlotName: "feature-y"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	worktreePath = filepath.Join(projectRoot, "slots", "feature-y", "repo1")
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "mine/feature-y" {
+		t.Errorf("branch = %q, want %q", got, "mine/feature-y")
+	}
+}
+
+func TestCreateCmd_ExistingBranch(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+	setupProjectWithSlot(t, projectRoot, "again")
+
+	// Recreating a destroyed slot checks out the branch it left behind
+	if err := (&DestroyCmd{Slots: []string{"again"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (&CreateCmd{SlotName: "again"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Using existing branch test/again in repo1") {
+		t.Errorf("expected a note about the existing branch, got:\n%s", buf.String())
+	}
+	for _, want := range []string{"  repo1: test/again -> " + filepath.Join("slots", "again", "repo1") + " (existing branch)", "created successfully with 1 worktree, 1 on an existing branch"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	worktreePath := filepath.Join(projectRoot, "slots", "again", "repo1")
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "test/again" {
+		t.Errorf("branch = %q, want %q", got, "test/again")
+	}
+
+	// --fresh-branch picks the first free suffix
+	if err := (&DestroyCmd{Slots: []string{"again"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if err := (&CreateCmd{SlotName: "again", FreshBranch: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run(--fresh-branch) error = %v", err)
+	}
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "test/again-2" {
+		t.Errorf("branch = %q, want %q", got, "test/again-2")
+	}
+
+	if err := (&CreateCmd{SlotName: "other", Branch: "x", FreshBranch: true}).Run(testContext(&bytes.Buffer{})); err == nil {
+		t.Error("expected --fresh-branch with --branch to fail")
+	}
+}
+
+func TestCreateCmd_BranchPrefixLookedUpOnce(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "")
+
+	yamlContent := "version: 1\nrepositories:\n"
+	for _, name := range []string{"repo1", "repo2", "repo3"} {
+		yamlContent += fmt.Sprintf("  - name: %s\n    url: https://github.com/example/%s.git\n", name, name)
+		repoPath := filepath.Join(projectRoot, "repos", name+".git")
+		if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		testutil.InitBareRepo(t, repoPath)
+	}
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	// GIT_TRACE logs every git invocation, including those of devslot itself
+	tracePath := filepath.Join(t.TempDir(), "trace")
+	t.Setenv("GIT_TRACE", tracePath)
+	if err := (&CreateCmd{SlotName: "traced"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+
+	trace := testutil.ReadFile(t, tracePath)
+	if !strings.Contains(trace, "git config --get ") {
+		t.Fatalf("GIT_TRACE recorded no git config lookups:\n%s", trace)
+	}
+	for _, key := range []string{"devslot.branchPrefix", "user.email"} {
+		if count := strings.Count(trace, "git config --get "+key+"\n"); count > 1 {
+			t.Errorf("git config --get %s ran %d times, want at most once", key, count)
+		}
+	}
+}
+
+func TestCreateCmd_CaseInsensitiveCollision(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+	if err := os.MkdirAll(filepath.Join(projectRoot, "slots", "foo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := (&CreateCmd{SlotName: "Foo"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "slot foo already exists") {
+		t.Fatalf("expected the existing slot foo to be reported, got %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(projectRoot, "slots")); len(entries) != 1 {
+		t.Errorf("slot Foo should not have been created, slots/ has %d entries", len(entries))
+	}
+}
+
+func TestCreateCmd_InlineHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		hooks       string
+		wantErr     bool
+		errContains string
+		wantMarker  string
+	}{
+		{
+			name: "inline hooks run after file hook",
+			hooks: `hooks:
+  post-create:
+    - echo inline-1 >> "$DEVSLOT_ROOT/marker"
+    - echo "inline-2 $DEVSLOT_SLOT_NAME" >> "$DEVSLOT_ROOT/marker"
+`,
+			wantMarker: "file\ninline-1\ninline-2 inline-slot\n",
+		},
+		{
+			name: "failing inline command is reported",
+			hooks: `hooks:
+  post-create:
+    - "true"
+    - exit 3
+`,
+			wantErr:     true,
+			errContains: `inline hook post-create command "exit 3" failed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := testutil.TempDir(t)
+			defer testutil.Chdir(t, projectRoot)()
+
+			yamlContent := `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+` + tt.hooks
+			testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+			repo1Path := filepath.Join(projectRoot, "repos", "repo1.git")
+			if err := os.MkdirAll(filepath.Dir(repo1Path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			testutil.InitBareRepo(t, repo1Path)
+			testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), `#!/bin/bash
+echo file >> "$DEVSLOT_ROOT/marker"
+`)
+
+			var buf bytes.Buffer
+			cmd := &CreateCmd{SlotName: "inline-slot"}
+			err := cmd.Run(testContext(&buf))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateCmd.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("CreateCmd.Run() error = %v, want error containing %q", err, tt.errContains)
+			}
+			if tt.wantMarker != "" {
+				testutil.AssertFileContent(t, filepath.Join(projectRoot, "marker"), tt.wantMarker)
+			}
+		})
+	}
+}
+
+func TestCreateCmd_QuietAndPorcelain(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), "#!/bin/sh\necho hook output\n")
+
+	t.Run("quiet", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		ctx := &Context{Out: &out, Err: &errOut, Verbosity: VerbosityQuiet}
+		if err := (&CreateCmd{SlotName: "quiet-slot"}).Run(ctx); err != nil {
+			t.Fatalf("CreateCmd.Run() error = %v", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("expected no output in quiet mode, got %q", out.String())
+		}
+		if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "quiet-slot", "repo1")) {
+			t.Error("expected slot to be created")
+		}
+	})
+
+	t.Run("porcelain", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		if err := (&CreateCmd{SlotName: "porcelain-slot", Porcelain: true}).Run(&Context{Out: &out, Err: &errOut}); err != nil {
+			t.Fatalf("CreateCmd.Run() error = %v", err)
+		}
+		want := filepath.Join(projectRoot, "slots", "porcelain-slot") + "\n"
+		if got := out.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+		if !strings.Contains(errOut.String(), "hook output") {
+			t.Errorf("expected hook output on Err, got %q", errOut.String())
+		}
+	})
+}
+
+func TestCreateCmd_EnvrcTemplate(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+envrc_template: envrc.tmpl
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "envrc.tmpl"), "export DEVSLOT_SLOT_NAME={{ .SlotName }}\n")
+	repo1Path := filepath.Join(projectRoot, "repos", "repo1.git")
+	if err := os.MkdirAll(filepath.Dir(repo1Path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.InitBareRepo(t, repo1Path)
+
+	if err := (&CreateCmd{SlotName: "feature-x"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	envrcPath := filepath.Join(projectRoot, "slots", "feature-x", ".envrc")
+	if got := testutil.ReadFile(t, envrcPath); got != "export DEVSLOT_SLOT_NAME=feature-x\n" {
+		t.Errorf(".envrc = %q", got)
+	}
+
+	// Reload renders the template again
+	testutil.CreateFile(t, filepath.Join(projectRoot, "envrc.tmpl"), "export SLOT={{ .SlotName }}\n")
+	if err := (&ReloadCmd{SlotName: "feature-x"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if got := testutil.ReadFile(t, envrcPath); got != "export SLOT=feature-x\n" {
+		t.Errorf(".envrc after reload = %q", got)
+	}
+
+	// A broken template fails before any worktree is created
+	testutil.CreateFile(t, filepath.Join(projectRoot, "envrc.tmpl"), "export A=1\n{{ .Nope }\n")
+	err := (&CreateCmd{SlotName: "feature-y"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "envrc.tmpl:2") {
+		t.Errorf("expected a template error with its line, got %v", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "feature-y")) {
+		t.Error("slot should not be created when the template is broken")
+	}
+}
+
+func TestCreateCmd_StartsFromDefaultBranch(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	fixture := testutil.NewRepoFixture(t).
+		Commit("main.txt", "main").
+		Branch("develop").Commit("develop.txt", "develop").Commit("develop.txt", "develop 2").Tag("v2").
+		Default("develop")
+	fixture.Push(filepath.Join(projectRoot, "repos", "repo1.git"))
+
+	if err := (&CreateCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+
+	worktreePath := filepath.Join(projectRoot, "slots", "work", "repo1")
+	if got := gitOutput(t, worktreePath, "rev-parse", "HEAD"); got != fixture.Rev("v2") {
+		t.Errorf("new branch starts at %s, want the tip of develop %s", got, fixture.Rev("v2"))
+	}
+	if got := testutil.ReadFile(t, filepath.Join(worktreePath, "develop.txt")); got != "develop 2" {
+		t.Errorf("develop.txt = %q, want %q", got, "develop 2")
+	}
+}
+
+func TestCreateCmd_DryRun(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+	setupProjectWithSlot(t, projectRoot, "existing")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), "#!/bin/sh\ntouch \"$DEVSLOT_ROOT/post-create-ran\"\n")
+
+	var buf bytes.Buffer
+	if err := (&CreateCmd{SlotName: "planned", DryRun: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"Would create slot 'planned' in " + filepath.Join(projectRoot, "slots", "planned"),
+		`Branch: test/planned (from branch prefix "test/")`,
+		"Would create worktree repo1 on new branch test/planned",
+		"Would run post-create hook",
+		dryRunNotice,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "planned")) {
+		t.Error("dry run must not create the slot")
+	}
+	if testutil.FileExists(t, filepath.Join(projectRoot, "post-create-ran")) {
+		t.Error("dry run must not run hooks")
+	}
+
+	// Problems are reported as they would be without --dry-run
+	if err := (&CreateCmd{SlotName: "existing", DryRun: true}).Run(testContext(&bytes.Buffer{})); err == nil {
+		t.Error("expected an error for a slot that already exists")
+	}
+	if err := (&CreateCmd{SlotName: "planned", DryRun: true, Porcelain: true}).Run(testContext(&bytes.Buffer{})); err == nil {
+		t.Error("expected --dry-run with --porcelain to fail")
+	}
+}
+
+func TestCreateCmd_KeepPartial(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+  - name: repo2
+    url: https://github.com/example/repo2.git
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	// Fetching repo2 fails because its origin is gone
+	repo2 := filepath.Join(projectRoot, "repos", "repo2.git")
+	testutil.InitBareRepo(t, repo2)
+	if output, err := exec.Command("git", "-C", repo2, "remote", "add", "origin", filepath.Join(projectRoot, "missing.git")).CombinedOutput(); err != nil {
+		t.Fatalf("git remote add: %v: %s", err, output)
+	}
+
+	// Without --keep-partial the slot is removed again
+	if err := (&CreateCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err == nil {
+		t.Fatal("expected the create to fail")
+	}
+	slotPath := filepath.Join(projectRoot, "slots", "work")
+	if testutil.DirExists(t, slotPath) {
+		t.Fatal("slot should have been removed")
+	}
+
+	err := (&CreateCmd{SlotName: "work", KeepPartial: true}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "slot work created without worktrees for repo2") || !strings.Contains(err.Error(), "devslot reload work") {
+		t.Fatalf("CreateCmd.Run() error = %v, want an incomplete slot error", err)
+	}
+	if !testutil.DirExists(t, filepath.Join(slotPath, "repo1")) {
+		t.Error("the worktree of repo1 should have been kept")
+	}
+
+	var found bool
+	for _, finding := range (&DoctorCmd{}).Diagnose(testContext(&bytes.Buffer{}), projectRoot) {
+		if finding.Severity == SeverityWarning && strings.Contains(finding.Message, "Slot work is incomplete") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected doctor to report the incomplete slot")
+	}
+
+	// Reload finishes the slot
+	if output, err := exec.Command("git", "-C", repo2, "remote", "remove", "origin").CombinedOutput(); err != nil {
+		t.Fatalf("git remote remove: %v: %s", err, output)
+	}
+	if err := (&ReloadCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if !testutil.DirExists(t, filepath.Join(slotPath, "repo2")) {
+		t.Fatal("reload should have created the worktree of repo2")
+	}
+	if branch := currentBranch(t, filepath.Join(slotPath, "repo2")); branch != "test/work" {
+		t.Errorf("repo2 branch = %q, want the slot branch test/work", branch)
+	}
+	meta, err := slot.LoadMetadata(slotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Incomplete) != 0 {
+		t.Errorf("reload should have cleared the incomplete marker, got %v", meta.Incomplete)
+	}
+	if meta.Branches["repo2"] != "test/work" {
+		t.Errorf("recorded branch of repo2 = %q, want test/work", meta.Branches["repo2"])
+	}
+}
+
+func TestCreateCmd_Template(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+  - name: repo2
+    url: https://github.com/example/repo2.git
+  - name: repo3
+    url: https://github.com/example/repo3.git
+slot_templates:
+  release:
+    description: Release preparation
+    repositories: [repo1, repo2]
+    branches:
+      repo1:
+        branch: release/1.x
+      repo2:
+        base: v1
+`)
+	testutil.NewRepoFixture(t).
+		Branch("release/1.x").Commit("release.txt", "release").
+		Push(filepath.Join(projectRoot, "repos", "repo1.git"))
+	repo2 := testutil.NewRepoFixture(t).Commit("version.txt", "1").Tag("v1").Commit("version.txt", "2")
+	repo2.Push(filepath.Join(projectRoot, "repos", "repo2.git"))
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo3.git"))
+
+	if err := (&CreateCmd{SlotName: "rel", Template: "release"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	slotPath := filepath.Join(projectRoot, "slots", "rel")
+	if got := gitOutput(t, filepath.Join(slotPath, "repo1"), "branch", "--show-current"); got != "release/1.x" {
+		t.Errorf("repo1 is on %q, want the template branch release/1.x", got)
+	}
+	if got := gitOutput(t, filepath.Join(slotPath, "repo2"), "branch", "--show-current"); got != "test/rel" {
+		t.Errorf("repo2 is on %q, want the slot branch test/rel", got)
+	}
+	if got := gitOutput(t, filepath.Join(slotPath, "repo2"), "rev-parse", "HEAD"); got != repo2.Rev("v1") {
+		t.Errorf("repo2 starts at %s, want the template base v1 %s", got, repo2.Rev("v1"))
+	}
+	if testutil.DirExists(t, filepath.Join(slotPath, "repo3")) {
+		t.Error("repo3 is not in the template and should have no worktree")
+	}
+	meta, err := slot.LoadMetadata(slotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Template != "release" || meta.Description != "Release preparation" {
+		t.Errorf("metadata template = %q, description = %q", meta.Template, meta.Description)
+	}
+
+	var buf bytes.Buffer
+	ctx := testContext(&buf)
+	ctx.Verbose = true
+	if err := (&ListCmd{}).Run(ctx); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "rel (template release)") {
+		t.Errorf("expected list --verbose to show the template, got:\n%s", buf.String())
+	}
+
+	// Flags take precedence over the template
+	if err := (&CreateCmd{SlotName: "custom", Template: "release", Branch: "main", Description: "Custom"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	slotPath = filepath.Join(projectRoot, "slots", "custom")
+	for _, repo := range []string{"repo1", "repo2"} {
+		if got := gitOutput(t, filepath.Join(slotPath, repo), "branch", "--show-current"); got != "main" {
+			t.Errorf("%s is on %q, want main from --branch", repo, got)
+		}
+	}
+	if meta, err := slot.LoadMetadata(slotPath); err != nil || meta.Description != "Custom" {
+		t.Errorf("metadata = %+v, %v; want the description from --description", meta, err)
+	}
+
+	err = (&CreateCmd{SlotName: "other", Template: "hotfix"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "unknown template hotfix") || !strings.Contains(err.Error(), "Known templates: release") {
+		t.Errorf("CreateCmd.Run() error = %v, want an unknown template error", err)
+	}
+}
+
+func TestCreateCmd_NamedRemote(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	mirror := filepath.Join(testutil.TempDir(t), "repo1.git")
+	fixture := testutil.NewRepoFixture(t).Commit("README.md", "mirror")
+	fixture.Push(mirror)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"),
+		"version: 1\nrepositories:\n  - name: repo1\n    url: "+mirror+"\n    remote: upstream\n")
+
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	bareRepoPath := filepath.Join(projectRoot, "repos", "repo1.git")
+	if got := gitOutput(t, bareRepoPath, "remote"); got != "upstream" {
+		t.Errorf("remotes = %q, want only upstream", got)
+	}
+
+	// The slot branch starts from the fetched state of upstream
+	fixture.Commit("CHANGES.md", "new").Push(mirror)
+	if err := (&CreateCmd{SlotName: "s"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	worktree := filepath.Join(projectRoot, "slots", "s", "repo1")
+	if got, want := gitOutput(t, worktree, "rev-parse", "HEAD"), gitOutput(t, mirror, "rev-parse", "HEAD"); got != want {
+		t.Errorf("slot starts at %s, want upstream's HEAD %s", got, want)
+	}
+
+	// doctor reports a remote that the bare repository does not have
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"),
+		"version: 1\nrepositories:\n  - name: repo1\n    url: "+mirror+"\n    remote: mirror\n")
+	var buf bytes.Buffer
+	_ = (&DoctorCmd{}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "Repository repo1 has no remote mirror (it has upstream") {
+		t.Errorf("doctor did not report the missing remote:\n%s", buf.String())
+	}
+}
+
+func TestCreateCmd_PreflightReportsAllProblems(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "first")
+	if err := (&CreateCmd{SlotName: "pinned", Branch: "shared"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+  - name: api
+    url: https://github.com/example/api.git
+  - name: web
+    url: https://github.com/example/web.git
+seed_files:
+  templates/.env: .env
+`)
+
+	err := (&CreateCmd{SlotName: "second", Branch: "shared"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil {
+		t.Fatal("expected the pre-flight checks to fail")
+	}
+	for _, want := range []string{
+		"cannot create slot second: 4 problems found",
+		"bare repository api does not exist",
+		"bare repository web does not exist",
+		"branch shared of repo1 is already checked out in ",
+		"seed files not found: templates/.env",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error:\n%v", want, err)
+		}
+	}
+
+	// Nothing was created
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "second")) {
+		t.Error("slot directory should not have been created")
+	}
+	worktrees, err := git.ListWorktrees(filepath.Join(projectRoot, "repos", "repo1.git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 2 {
+		t.Errorf("expected only the worktrees of the existing slots, got %v", worktrees)
+	}
+}
+
+func TestCreateCmd_Timings(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "first")
+
+	var quiet bytes.Buffer
+	if err := (&CreateCmd{SlotName: "second"}).Run(testContext(&quiet)); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+	if strings.Contains(quiet.String(), "Timings:") {
+		t.Errorf("timings of a fast create should only be printed with --timings:\n%s", quiet.String())
+	}
+
+	var buf bytes.Buffer
+	if err := (&CreateCmd{SlotName: "third", Timings: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+	for _, want := range []string{"Timings:", "  worktree repo1 ", "  Total "} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCreateCmd_PrintsBranchesAndPaths(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "first")
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "devslot/yamada/")
+
+	var buf bytes.Buffer
+	if err := (&CreateCmd{SlotName: "feature-x"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	slotPath := filepath.Join(projectRoot, "slots", "feature-x")
+	for _, want := range []string{
+		"  repo1: devslot/yamada/feature-x -> " + filepath.Join("slots", "feature-x", "repo1") + "\n",
+		"You can now work in: " + slotPath + "\n",
+		"  cd " + shellQuote(slotPath) + "\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"/home/me/proj/slots/feature-x": "/home/me/proj/slots/feature-x",
+		"/home/me/my proj/slots/x":      "'/home/me/my proj/slots/x'",
+		"/tmp/it's":                     `'/tmp/it'\''s'`,
+	}
+	for path, want := range tests {
+		if got := shellQuote(path); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
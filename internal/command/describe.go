@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type DescribeCmd struct {
+	SlotName string `arg:"" help:"Name of the slot"`
+	Text     string `arg:"" optional:"" help:"New description of the slot"`
+	Clear    bool   `help:"Remove the description"`
+}
+
+func (c *DescribeCmd) Help() string {
+	return `Shows or changes what a slot is for.
+
+Without text, the slot's description is printed (nothing when it has none).
+With text, the description is replaced; --clear removes it. Descriptions are
+shown by 'devslot list' and can also be set with 'devslot create --description'.`
+}
+
+func (c *DescribeCmd) Run(ctx *Context) error {
+	if c.Clear && c.Text != "" {
+		return errors.InvalidUsage("--clear cannot be combined with a description",
+			"Pass either the new description or --clear")
+	}
+
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a devslot project: %w", err)
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+
+	if c.Text == "" && !c.Clear {
+		description, err := mgr.Description(c.SlotName)
+		if err != nil {
+			return err
+		}
+		if description != "" {
+			ctx.Resultln(description)
+		}
+		return nil
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.Eprintf("Warning: failed to release lock: %v\n", err)
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	if err := mgr.SetDescription(c.SlotName, c.Text); err != nil {
+		return err
+	}
+	if c.Clear {
+		ctx.Printf("Description of slot '%s' removed.\n", c.SlotName)
+	} else {
+		ctx.Printf("Description of slot '%s' updated.\n", c.SlotName)
+	}
+	ctx.LogInfo("slot description updated", "slot", c.SlotName)
+
+	return nil
+}
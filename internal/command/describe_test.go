@@ -0,0 +1,68 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestDescribeCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "ticket-123")
+
+	describe := func(cmd *DescribeCmd) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("DescribeCmd.Run() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	if got := describe(&DescribeCmd{SlotName: "ticket-123"}); got != "" {
+		t.Errorf("expected no output without a description, got %q", got)
+	}
+	describe(&DescribeCmd{SlotName: "ticket-123", Text: "Fix login redirect"})
+	if got := describe(&DescribeCmd{SlotName: "ticket-123"}); got != "Fix login redirect\n" {
+		t.Errorf("description = %q", got)
+	}
+	describe(&DescribeCmd{SlotName: "ticket-123", Clear: true})
+	if got := describe(&DescribeCmd{SlotName: "ticket-123"}); got != "" {
+		t.Errorf("expected the description to be cleared, got %q", got)
+	}
+}
+
+func TestDescribeCmd_SlotNotFound(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "existing")
+
+	for _, cmd := range []*DescribeCmd{{SlotName: "missing"}, {SlotName: "missing", Text: "text"}} {
+		err := cmd.Run(testContext(&bytes.Buffer{}))
+		if err == nil || !strings.Contains(err.Error(), "slot missing does not exist") || !strings.Contains(err.Error(), "devslot list") {
+			t.Errorf("expected SlotNotFound, got %v", err)
+		}
+	}
+}
+
+func TestDescribeCmd_RejectsPathsOutsideSlots(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "existing")
+
+	for _, name := range []string{"../hooks", "..", "existing/../../repos"} {
+		err := (&DescribeCmd{SlotName: name, Text: "text"}).Run(testContext(&bytes.Buffer{}))
+		if err == nil || !strings.Contains(err.Error(), "invalid slot name") {
+			t.Errorf("describe %s: expected an invalid slot name error, got %v", name, err)
+		}
+	}
+	for _, dir := range []string{"hooks", ".", "repos"} {
+		if testutil.FileExists(t, filepath.Join(projectRoot, dir, ".devslot.json")) {
+			t.Errorf("describe wrote metadata into %s", dir)
+		}
+	}
+}
@@ -1,37 +1,81 @@
 package command
 
 import (
+	stderrors "errors"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
 	"github.com/yammerjp/devslot/internal/lock"
 	"github.com/yammerjp/devslot/internal/slot"
 )
 
 type DestroyCmd struct {
-	SlotName string `arg:"" help:"Name of the slot to destroy"`
+	Slots       []string `arg:"" name:"slots" optional:"" help:"Names of the slots to destroy"`
+	All         bool     `help:"Destroy every slot of the project"`
+	Porcelain   bool     `help:"Print only the names of the destroyed slots"`
+	Yes         bool     `short:"y" help:"Destroy without asking for confirmation"`
+	Force       bool     `help:"Also remove git worktrees of repositories that are not in devslot.yaml"`
+	DryRun      bool     `name:"dry-run" help:"Show what would be removed without destroying anything"`
+	StrictHooks bool     `name:"strict-hooks" help:"Fail when the post-destroy hook fails instead of only warning"`
 }
 
 func (c *DestroyCmd) Help() string {
-	return `Removes a slot and all its worktrees.
+	return `Removes slots and all their worktrees.
+
+Several slots can be given at once, or --all destroys every slot of the project.
+Each slot is destroyed on its own: if one fails, the others are still destroyed
+and the failures are reported at the end.
 
 Runs pre-destroy hook before removal. If the hook fails (non-zero exit),
 the destruction is aborted and the slot remains intact.
 
 Runs post-destroy hook after successful removal. If this hook fails,
-the slot is already destroyed and only a warning is shown.`
+the slot is already destroyed and only a warning is shown. With --strict-hooks
+or strict_hooks: true in devslot.yaml, the command fails instead (exit code 5),
+for example so that CI notices a hook that could not release resources.
+
+When stdin is a terminal, the repositories of every slot and any uncommitted
+changes are listed and the destruction has to be confirmed once; --yes skips
+the question. When stdin is not a terminal (scripts, CI), the slots are
+destroyed without asking.
+
+Worktrees are removed through the repositories of devslot.yaml. Other
+directories in a slot are removed with it after a warning. A slot containing
+git worktrees of repositories that are not in devslot.yaml is only destroyed
+with --force.
+
+Slots containing the current directory are not destroyed, since that would
+leave the shell in a deleted directory; change to another directory first.
+
+With --porcelain, only the name of each slot is printed once it has been
+destroyed, and hook output is sent to stderr.
+
+With --dry-run, the worktrees and directories that would be removed and the
+hooks that would run are shown; nothing is removed and no hook runs.`
 }
 
 func (c *DestroyCmd) Run(ctx *Context) error {
-	// Find project root
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+	if c.All && len(c.Slots) > 0 {
+		return errors.InvalidUsage("--all cannot be combined with slot names",
+			"Pass either --all or the names of the slots to destroy")
+	}
+	if !c.All && len(c.Slots) == 0 {
+		return errors.InvalidUsage("no slot to destroy",
+			"Pass the names of the slots to destroy, or --all to destroy every slot")
+	}
+	if c.DryRun && c.Porcelain {
+		return errors.InvalidUsage("--dry-run cannot be combined with --porcelain",
+			"--porcelain prints the names of slots that were destroyed")
+	}
+	if c.Porcelain {
+		ctx.Porcelain()
 	}
 
-	projectRoot, err := config.FindProjectRoot(currentDir)
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
 	if err != nil {
 		return fmt.Errorf("not in a devslot project: %w", err)
 	}
@@ -39,32 +83,164 @@ func (c *DestroyCmd) Run(ctx *Context) error {
 	// Acquire lock
 	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
 	if err := lockFile.Acquire(); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+		return errors.LockFailed(err)
 	}
 	defer func() {
 		if err := lockFile.Release(); err != nil {
-			ctx.Printf("Warning: failed to release lock: %v\n", err)
+			ctx.Eprintf("Warning: failed to release lock: %v\n", err)
 			ctx.LogWarn("failed to release lock", "error", err)
 		}
 	}()
 
 	// Load configuration
-	cfg, err := config.Load(projectRoot)
+	cfg, err := ctx.LoadConfig(projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Destroy slot
 	mgr := slot.NewManager(projectRoot)
-	ctx.Printf("Destroying slot '%s'...\n", c.SlotName)
-	ctx.LogInfo("destroying slot", "slot", c.SlotName)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+	if c.Porcelain {
+		mgr.SetHookStdout(ctx.Err)
+	}
+
+	targets := c.Slots
+	if c.All {
+		targets, err = mgr.List()
+		if err != nil {
+			return fmt.Errorf("failed to list slots: %w", err)
+		}
+		if len(targets) == 0 {
+			ctx.Println("No slots to destroy.")
+			return nil
+		}
+	}
+
+	if c.DryRun {
+		return c.dryRun(ctx, mgr, cfg, targets)
+	}
+	if err := refuseCurrentSlot(mgr, "destroy", projectRoot, targets); err != nil {
+		return err
+	}
+
+	if !c.Yes && ctx.Interactive() {
+		confirmed, err := c.confirm(ctx, mgr, projectRoot, cfg, targets)
+		if err != nil {
+			return fmt.Errorf("failed to destroy slot: %w", err)
+		}
+		if !confirmed {
+			if len(targets) == 1 {
+				ctx.Eprintf("Slot '%s' was not destroyed.\n", targets[0])
+			} else {
+				ctx.Eprintf("No slots were destroyed.\n")
+			}
+			return nil
+		}
+	}
+
+	// Destroy slots
+	var failed []string
+	var hookErrs []error
+	for _, name := range targets {
+		ctx.Printf("Destroying slot '%s'...\n", name)
+		ctx.LogInfo("destroying slot", "slot", name)
 
-	if err := mgr.Destroy(c.SlotName, cfg); err != nil {
-		return fmt.Errorf("failed to destroy slot: %w", err)
+		result, err := mgr.Destroy(name, cfg, &slot.DestroyOptions{Force: c.Force, StrictHooks: c.StrictHooks})
+		if result != nil {
+			for _, warning := range result.Warnings {
+				ctx.Eprintf("Warning: %s\n", warning)
+				ctx.LogWarn("problem while destroying slot", "slot", name, "warning", warning)
+			}
+		}
+		if stderrors.Is(err, slot.ErrPostDestroyHook) {
+			// The slot is gone; only its post-destroy hook failed
+			if c.Porcelain {
+				ctx.Resultln(name)
+			}
+			if len(targets) == 1 {
+				return err
+			}
+			ctx.Failure("%v", err)
+			ctx.LogError("post-destroy hook failed", "slot", name, "error", err)
+			hookErrs = append(hookErrs, err)
+			continue
+		}
+		if err != nil {
+			if len(targets) == 1 {
+				return fmt.Errorf("failed to destroy slot: %w", err)
+			}
+			ctx.Failure("Slot '%s' not destroyed: %v", name, err)
+			ctx.LogError("failed to destroy slot", "slot", name, "error", err)
+			failed = append(failed, name)
+			continue
+		}
+
+		ctx.Success("Slot '%s' destroyed successfully! (%s removed)", name, pluralize(len(result.RemovedWorktrees), "worktree"))
+		ctx.LogInfo("slot destroyed", "slot", name, "worktrees", len(result.RemovedWorktrees))
+		if c.Porcelain {
+			ctx.Resultln(name)
+		}
 	}
 
-	ctx.Printf("Slot '%s' destroyed successfully!\n", c.SlotName)
-	ctx.LogInfo("slot destroyed", "slot", c.SlotName)
+	if len(failed) > 0 {
+		return errors.DestroyIncomplete(failed, len(targets))
+	}
+	return stderrors.Join(hookErrs...)
+}
+
+// dryRun shows what destroying the slots would remove
+func (c *DestroyCmd) dryRun(ctx *Context, mgr *slot.Manager, cfg *config.Config, targets []string) error {
+	var failed []string
+	for _, name := range targets {
+		plan, err := mgr.PlanDestroy(name, cfg, &slot.DestroyOptions{Force: c.Force})
+		if err != nil {
+			if len(targets) == 1 {
+				return fmt.Errorf("failed to destroy slot: %w", err)
+			}
+			ctx.Failure("Slot '%s' could not be destroyed: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		printDestroyPlan(ctx, plan)
+	}
+	ctx.Println(dryRunNotice)
 
+	if len(failed) > 0 {
+		return errors.DestroyIncomplete(failed, len(targets))
+	}
 	return nil
 }
+
+// confirm lists what destroying the slots removes and asks the user to go ahead
+func (c *DestroyCmd) confirm(ctx *Context, mgr *slot.Manager, projectRoot string, cfg *config.Config, targets []string) (bool, error) {
+	for _, name := range targets {
+		statuses, err := mgr.Status(name, cfg)
+		if err != nil {
+			return false, err
+		}
+
+		slotPath := filepath.Join(cfg.SlotsDir(projectRoot), name)
+		ctx.Eprintf("Slot '%s' (%s) contains:\n", name, slotPath)
+		for _, status := range statuses {
+			if !status.Exists {
+				ctx.Eprintf("  - %s (missing)\n", status.Name)
+				continue
+			}
+			dirty, err := git.IsDirty(filepath.Join(slotPath, status.Name))
+			switch {
+			case err != nil:
+				ctx.Eprintf("  - %s on %s, state unknown: %v\n", status.Name, status.Branch, err)
+			case dirty:
+				ctx.Eprintf("  - %s on %s, UNCOMMITTED CHANGES will be lost\n", status.Name, status.Branch)
+			default:
+				ctx.Eprintf("  - %s on %s\n", status.Name, status.Branch)
+			}
+		}
+	}
+
+	if len(targets) == 1 {
+		return ctx.Confirm(fmt.Sprintf("Destroy slot '%s'?", targets[0]))
+	}
+	return ctx.Confirm(fmt.Sprintf("Destroy these %d slots?", len(targets)))
+}
@@ -0,0 +1,375 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestDestroyCmd_Confirmation(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "debug")
+	slotPath := filepath.Join(projectRoot, "slots", "debug")
+	testutil.CreateFile(t, filepath.Join(slotPath, "repo1", "wip.txt"), "work in progress")
+
+	// Declining keeps the slot
+	var buf bytes.Buffer
+	ctx := testContext(&buf)
+	ctx.In = fakeTerminal{strings.NewReader("n\n")}
+	if err := (&DestroyCmd{Slots: []string{"debug"}}).Run(ctx); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"Slot 'debug' (" + slotPath + ") contains:",
+		"repo1 on ",
+		"UNCOMMITTED CHANGES will be lost",
+		"Destroy slot 'debug'? [y/N]",
+		"Slot 'debug' was not destroyed.",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if !testutil.DirExists(t, slotPath) {
+		t.Fatal("slot should not have been destroyed")
+	}
+
+	// Confirming destroys it
+	buf.Reset()
+	ctx = testContext(&buf)
+	ctx.In = fakeTerminal{strings.NewReader("y\n")}
+	if err := (&DestroyCmd{Slots: []string{"debug"}}).Run(ctx); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if testutil.DirExists(t, slotPath) {
+		t.Error("slot should have been destroyed after confirming")
+	}
+}
+
+func TestDestroyCmd_NoPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() *Context
+		yes  bool
+	}{
+		{name: "--yes at a terminal", yes: true, ctx: func() *Context {
+			ctx := testContext(&bytes.Buffer{})
+			ctx.In = fakeTerminal{strings.NewReader("")}
+			return ctx
+		}},
+		{name: "stdin is not a terminal", ctx: func() *Context {
+			ctx := testContext(&bytes.Buffer{})
+			ctx.In = strings.NewReader("")
+			return ctx
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := testutil.TempDir(t)
+			defer testutil.Chdir(t, projectRoot)()
+			setupProjectWithSlot(t, projectRoot, "debug")
+
+			if err := (&DestroyCmd{Slots: []string{"debug"}, Yes: tt.yes}).Run(tt.ctx()); err != nil {
+				t.Fatalf("DestroyCmd.Run() error = %v", err)
+			}
+			if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "debug")) {
+				t.Error("slot should have been destroyed without a prompt")
+			}
+		})
+	}
+}
+
+func TestDestroyCmd_Multiple(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "one")
+	for _, name := range []string{"two", "three"} {
+		if err := (&CreateCmd{SlotName: name}).Run(testContext(&bytes.Buffer{})); err != nil {
+			t.Fatalf("failed to create slot: %v", err)
+		}
+	}
+	// The hook refuses to destroy "two" only
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "pre-destroy"),
+		"#!/bin/sh\n[ \"$DEVSLOT_SLOT_NAME\" != two ]\n")
+
+	var buf bytes.Buffer
+	err := (&DestroyCmd{Slots: []string{"one", "two", "three"}}).Run(testContext(&buf))
+	if err == nil {
+		t.Fatal("expected an error when one of the slots fails")
+	}
+	if !strings.Contains(err.Error(), "1 of 3 slots not destroyed: two") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Slot 'two' not destroyed") {
+		t.Errorf("expected per-slot failure in output, got:\n%s", buf.String())
+	}
+	for name, want := range map[string]bool{"one": false, "two": true, "three": false} {
+		if got := testutil.DirExists(t, filepath.Join(projectRoot, "slots", name)); got != want {
+			t.Errorf("slot %s exists = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDestroyCmd_All(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "one")
+	if err := (&CreateCmd{SlotName: "two"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+
+	// The prompt lists every slot once
+	var buf bytes.Buffer
+	ctx := testContext(&buf)
+	ctx.In = fakeTerminal{strings.NewReader("n\n")}
+	if err := (&DestroyCmd{All: true}).Run(ctx); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "[y/N]"); got != 1 {
+		t.Errorf("expected a single question, got %d:\n%s", got, buf.String())
+	}
+	for _, want := range []string{"Slot 'one' (", "Slot 'two' (", "Destroy these 2 slots?", "No slots were destroyed."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+
+	if err := (&DestroyCmd{All: true, Yes: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(projectRoot, "slots"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no slots left, got %d", len(entries))
+	}
+}
+
+func TestDestroyCmd_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *DestroyCmd
+	}{
+		{name: "no slots", cmd: &DestroyCmd{}},
+		{name: "--all with names", cmd: &DestroyCmd{All: true, Slots: []string{"one"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Run(testContext(&bytes.Buffer{}))
+			if got := errors.ExitCode(err); got != errors.ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d (err = %v)", got, errors.ExitUsage, err)
+			}
+		})
+	}
+}
+
+func TestDestroyCmd_DryRun(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "doomed")
+	slotPath := filepath.Join(projectRoot, "slots", "doomed")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "pre-destroy"), "#!/bin/sh\ntouch \"$DEVSLOT_ROOT/pre-destroy-ran\"\n")
+
+	var buf bytes.Buffer
+	ctx := testContext(&buf)
+	ctx.In = fakeTerminal{strings.NewReader("")}
+	if err := (&DestroyCmd{Slots: []string{"doomed"}, DryRun: true}).Run(ctx); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"Would destroy slot 'doomed' and remove " + slotPath,
+		"Would remove worktree repo1",
+		"Would run pre-destroy hook",
+		dryRunNotice,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "[y/N]") {
+		t.Errorf("dry run must not ask for confirmation, got:\n%s", buf.String())
+	}
+	if !testutil.DirExists(t, slotPath) {
+		t.Error("dry run must not destroy the slot")
+	}
+	if testutil.FileExists(t, filepath.Join(projectRoot, "pre-destroy-ran")) {
+		t.Error("dry run must not run hooks")
+	}
+
+	err := (&DestroyCmd{Slots: []string{"doomed", "missing"}, DryRun: true}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 slots not destroyed: missing") {
+		t.Errorf("expected the missing slot to be reported, got %v", err)
+	}
+}
+
+func TestDestroyCmd_RenamedRepository(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+
+	// repo1 is renamed in devslot.yaml after the slot was created; the slot
+	// metadata still knows the repository its worktree belongs to
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: https://github.com/example/repo1.git
+`)
+
+	var buf bytes.Buffer
+	if err := (&DestroyCmd{Slots: []string{"work"}}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "(1 worktree removed)") {
+		t.Errorf("expected the worktree to be removed, got:\n%s", buf.String())
+	}
+	worktrees, err := git.ListWorktrees(filepath.Join(projectRoot, "repos", "repo1.git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 0 {
+		t.Errorf("worktree is still registered: %v", worktrees)
+	}
+}
+
+func TestDestroyCmd_UnknownDirectories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+	slotPath := filepath.Join(projectRoot, "slots", "work")
+	testutil.CreateFile(t, filepath.Join(slotPath, "node_modules", "pkg", "index.js"), "")
+
+	var out, errOut bytes.Buffer
+	ctx := testContext(&out)
+	ctx.Err = &errOut
+	if err := (&DestroyCmd{Slots: []string{"work"}}).Run(ctx); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Warning: removing node_modules, which is not a worktree of a configured repository") {
+		t.Errorf("expected a warning about node_modules, got:\n%s", errOut.String())
+	}
+	if !strings.Contains(out.String(), "(1 worktree removed)") {
+		t.Errorf("expected only repo1 to be removed as a worktree, got:\n%s", out.String())
+	}
+	if testutil.DirExists(t, slotPath) {
+		t.Error("slot should have been destroyed")
+	}
+}
+
+func TestDestroyCmd_ForeignWorktreeNeedsForce(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+	slotPath := filepath.Join(projectRoot, "slots", "work")
+	if err := os.Mkdir(filepath.Join(slotPath, "scratch"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := git.Init(filepath.Join(slotPath, "scratch")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := (&DestroyCmd{Slots: []string{"work"}}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "not in devslot.yaml: scratch") || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("DestroyCmd.Run() error = %v, want a refusal suggesting --force", err)
+	}
+	if !testutil.DirExists(t, slotPath) {
+		t.Fatal("slot must be kept without --force")
+	}
+
+	if err := (&DestroyCmd{Slots: []string{"work"}, Force: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() with --force error = %v", err)
+	}
+	if testutil.DirExists(t, slotPath) {
+		t.Error("slot should have been destroyed with --force")
+	}
+}
+
+func TestDestroyCmd_InsideSlot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "here")
+	if err := (&CreateCmd{SlotName: "other"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+	defer testutil.Chdir(t, filepath.Join(projectRoot, "slots", "here", "repo1"))()
+
+	var buf bytes.Buffer
+	if err := (&StatusCmd{}).Run(testContext(&buf)); err != nil || !strings.Contains(buf.String(), "Slot 'here':") {
+		t.Errorf("expected status to default to the current slot, got %v:\n%s", err, buf.String())
+	}
+
+	for name, run := range map[string]func() error{
+		"destroy": func() error { return (&DestroyCmd{Slots: []string{"other", "here"}}).Run(testContext(&bytes.Buffer{})) },
+		"archive": func() error { return (&ArchiveCmd{SlotName: "here"}).Run(testContext(&bytes.Buffer{})) },
+	} {
+		err := run()
+		if err == nil || !strings.Contains(err.Error(), "refusing to "+name+" slot here") {
+			t.Errorf("%s: expected a refusal, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"here", "other"} {
+		if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", name, "repo1")) {
+			t.Errorf("slot %s should have been kept", name)
+		}
+	}
+
+	// Other slots can still be destroyed from inside a slot
+	if err := (&DestroyCmd{Slots: []string{"other"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run(other) error = %v", err)
+	}
+}
+
+func TestDestroyCmd_StrictHooks(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "warned")
+	for _, name := range []string{"flag", "config"} {
+		if err := (&CreateCmd{SlotName: name}).Run(testContext(&bytes.Buffer{})); err != nil {
+			t.Fatalf("failed to create slot: %v", err)
+		}
+	}
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-destroy"), "#!/bin/sh\necho cleanup failed >&2\nexit 1\n")
+
+	// By default the failure is only a warning
+	var buf bytes.Buffer
+	if err := (&DestroyCmd{Slots: []string{"warned"}}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Warning: post-destroy hook failed") {
+		t.Errorf("expected a warning about the hook, got:\n%s", buf.String())
+	}
+
+	// --strict-hooks fails with the hook's exit code, after destroying the slot
+	buf.Reset()
+	err := (&DestroyCmd{Slots: []string{"flag"}, StrictHooks: true}).Run(testContext(&buf))
+	if code := errors.ExitCode(err); code != errors.ExitHookFailed {
+		t.Errorf("ExitCode() = %d, want %d (err = %v)", code, errors.ExitHookFailed, err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "slot flag was destroyed, but its post-destroy hook failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "flag")) {
+		t.Error("slot flag should have been destroyed")
+	}
+
+	// So does strict_hooks in devslot.yaml
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+strict_hooks: true
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`)
+	err = (&DestroyCmd{Slots: []string{"config"}}).Run(testContext(&bytes.Buffer{}))
+	if code := errors.ExitCode(err); code != errors.ExitHookFailed {
+		t.Errorf("ExitCode() = %d, want %d (err = %v)", code, errors.ExitHookFailed, err)
+	}
+}
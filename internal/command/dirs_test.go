@@ -0,0 +1,115 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestConfiguredDirectories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	if err := os.MkdirAll(filepath.Join(projectRoot, "hooks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// repos_dir goes through a symlink, slots_dir is outside the project
+	cache := testutil.TempDir(t)
+	if err := os.Symlink(cache, filepath.Join(projectRoot, "cache")); err != nil {
+		t.Fatal(err)
+	}
+	slotsDir := filepath.Join(testutil.TempDir(t), "slots")
+	reposDir := filepath.Join(projectRoot, "cache", "repos")
+
+	source := filepath.Join(testutil.TempDir(t), "app.git")
+	testutil.InitBareRepo(t, source)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repos_dir: cache/repos
+slots_dir: `+slotsDir+`
+repositories:
+  - name: app
+    url: `+source+`
+hooks:
+  post-create:
+    - echo "$DEVSLOT_REPOS_DIR $DEVSLOT_SLOT_DIR" > "$DEVSLOT_ROOT/hook-env"
+`)
+
+	var buf bytes.Buffer
+	if err := (&InitCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !testutil.DirExists(t, filepath.Join(cache, "repos", "app.git")) {
+		t.Fatal("expected the bare repository in repos_dir")
+	}
+
+	buf.Reset()
+	if err := (&CreateCmd{SlotName: "dev"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	slotPath := filepath.Join(slotsDir, "dev")
+	if !testutil.DirExists(t, filepath.Join(slotPath, "app")) {
+		t.Fatal("expected the worktree in slots_dir")
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots")) {
+		t.Error("slots/ should not be created when slots_dir is set")
+	}
+	if got, want := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "hook-env"))), reposDir+" "+slotPath; got != want {
+		t.Errorf("hook environment = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := (&PathCmd{SlotName: "dev", Repo: "app"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("PathCmd.Run() error = %v", err)
+	}
+	if got, want := buf.String(), filepath.Join(slotPath, "app")+"\n"; got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := (&ListCmd{Porcelain: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if buf.String() != "dev\n" {
+		t.Errorf("list = %q, want %q", buf.String(), "dev\n")
+	}
+
+	buf.Reset()
+	if err := (&DoctorCmd{}).Run(testContext(&buf)); err != nil {
+		t.Errorf("DoctorCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"Directory cache/repos exists", "Directory " + slotsDir + " exists"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in doctor output, got:\n%s", want, buf.String())
+		}
+	}
+
+	if err := (&DestroyCmd{Slots: []string{"dev"}, Yes: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if testutil.DirExists(t, slotPath) {
+		t.Error("slot should have been removed from slots_dir")
+	}
+}
+
+func TestBoilerplateCmd_ConfiguredDirectories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepos_dir: .cache/repos\nslots_dir: work\nrepositories: []\n")
+
+	if err := (&BoilerplateCmd{Dir: projectRoot}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+	}
+	for _, dir := range []string{"hooks", ".cache/repos", "work"} {
+		if !testutil.DirExists(t, filepath.Join(projectRoot, dir)) {
+			t.Errorf("expected %s to be created", dir)
+		}
+	}
+	for _, dir := range []string{"repos", "slots"} {
+		if testutil.DirExists(t, filepath.Join(projectRoot, dir)) {
+			t.Errorf("%s should not be created", dir)
+		}
+	}
+}
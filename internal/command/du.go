@@ -0,0 +1,184 @@
+package command
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+type DuCmd struct {
+	SlotName string `arg:"" optional:"" help:"Only report the size of this slot"`
+	JSON     bool   `name:"json" help:"Print sizes in bytes as JSON"`
+}
+
+func (c *DuCmd) Help() string {
+	return `Reports disk usage of the project: the size of each bare repository in
+repos/, the size of each slot in slots/, and totals. Entries are sorted by
+size, largest first.
+
+Sizes are the apparent sizes of regular files. Directories that cannot be
+read are skipped with a warning.`
+}
+
+// duEntry is the size of a repository or slot
+type duEntry struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+// duReport is the result of 'devslot du'
+type duReport struct {
+	Repositories      []duEntry `json:"repositories"`
+	Slots             []duEntry `json:"slots"`
+	RepositoriesTotal int64     `json:"repositories_total"`
+	SlotsTotal        int64     `json:"slots_total"`
+	Total             int64     `json:"total"`
+}
+
+func (c *DuCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	report := duReport{Repositories: []duEntry{}, Slots: []duEntry{}}
+
+	if c.SlotName != "" {
+		slotPath := filepath.Join(cfg.SlotsDir(projectRoot), c.SlotName)
+		if info, err := os.Stat(slotPath); err != nil || !info.IsDir() {
+			return errors.SlotNotFound(c.SlotName)
+		}
+		size := diskUsage(ctx, slotPath, func(string) string { return c.SlotName })
+		report.Slots, report.SlotsTotal = sortedEntries(size)
+	} else {
+		// Namespaced repositories live in subdirectories; attribute files to the enclosing *.git directory
+		repos := diskUsage(ctx, cfg.ReposDir(projectRoot), func(rel string) string {
+			parts := strings.Split(rel, string(filepath.Separator))
+			for i, part := range parts {
+				if strings.HasSuffix(part, ".git") {
+					return strings.TrimSuffix(filepath.Join(parts[:i+1]...), ".git")
+				}
+			}
+			return parts[0]
+		})
+		report.Repositories, report.RepositoriesTotal = sortedEntries(repos)
+
+		slots := diskUsage(ctx, cfg.SlotsDir(projectRoot), func(rel string) string {
+			slotName, _, _ := strings.Cut(rel, string(filepath.Separator))
+			return slotName
+		})
+		report.Slots, report.SlotsTotal = sortedEntries(slots)
+	}
+	report.Total = report.RepositoriesTotal + report.SlotsTotal
+
+	if c.JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode disk usage: %w", err)
+		}
+		ctx.Println(string(data))
+		return nil
+	}
+
+	if c.SlotName == "" {
+		printDuSection(ctx, "Repositories", report.Repositories, report.RepositoriesTotal)
+		ctx.Println()
+	}
+	printDuSection(ctx, "Slots", report.Slots, report.SlotsTotal)
+	if c.SlotName == "" {
+		ctx.Printf("\nTotal: %s\n", formatSize(report.Total))
+	}
+
+	return nil
+}
+
+// diskUsage walks root once and sums the sizes of regular files by the key
+// that group returns for their path relative to root. Unreadable entries are
+// skipped with a warning.
+func diskUsage(ctx *Context, root string, group func(rel string) string) map[string]int64 {
+	sizes := map[string]int64{}
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return nil
+			}
+			ctx.Eprintf("Warning: skipping %s: %v\n", path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == root || !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			ctx.Eprintf("Warning: skipping %s: %v\n", path, err)
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		sizes[group(rel)] += info.Size()
+		return nil
+	})
+	return sizes
+}
+
+// sortedEntries returns sizes sorted largest first (then by name) and their total
+func sortedEntries(sizes map[string]int64) ([]duEntry, int64) {
+	entries := make([]duEntry, 0, len(sizes))
+	var total int64
+	for name, size := range sizes {
+		entries = append(entries, duEntry{Name: name, Bytes: size})
+		total += size
+	}
+	slices.SortFunc(entries, func(a, b duEntry) int {
+		if c := cmp.Compare(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return entries, total
+}
+
+// printDuSection prints one table of 'devslot du'
+func printDuSection(ctx *Context, title string, entries []duEntry, total int64) {
+	ctx.Printf("%s:\n", title)
+	for _, entry := range entries {
+		ctx.Printf("  %10s  %s\n", formatSize(entry.Bytes), entry.Name)
+	}
+	ctx.Printf("  %10s  total\n", formatSize(total))
+}
+
+// formatSize formats a byte count with binary units
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size := float64(bytes) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		if size < unit || suffix == "TiB" {
+			return fmt.Sprintf("%.1f %s", size, suffix)
+		}
+		size /= unit
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
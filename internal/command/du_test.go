@@ -0,0 +1,75 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSize(tt.bytes); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestDuCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "small")
+	setupProjectWithSlot(t, projectRoot, "large")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "slots", "large", "repo1", "big.txt"), strings.Repeat("x", 64*1024))
+
+	var buf bytes.Buffer
+	if err := (&DuCmd{JSON: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("du --json failed: %v", err)
+	}
+	var report duReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(report.Repositories) != 1 || report.Repositories[0].Name != "repo1" || report.Repositories[0].Bytes == 0 {
+		t.Errorf("unexpected repositories: %+v", report.Repositories)
+	}
+	if len(report.Slots) != 2 || report.Slots[0].Name != "large" || report.Slots[1].Name != "small" {
+		t.Fatalf("expected slots sorted largest first, got %+v", report.Slots)
+	}
+	if report.Slots[0].Bytes-report.Slots[1].Bytes < 64*1024 {
+		t.Errorf("expected large slot to include big.txt: %+v", report.Slots)
+	}
+	if report.Total != report.RepositoriesTotal+report.SlotsTotal {
+		t.Errorf("Total = %d, want %d", report.Total, report.RepositoriesTotal+report.SlotsTotal)
+	}
+
+	// A slot name limits the scan to that slot
+	buf.Reset()
+	if err := (&DuCmd{SlotName: "small"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("du small failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "small") || strings.Contains(output, "large") || strings.Contains(output, "Repositories") {
+		t.Errorf("unexpected output for a single slot:\n%s", output)
+	}
+
+	if err := (&DuCmd{SlotName: "missing"}).Run(testContext(&buf)); err == nil {
+		t.Error("expected an error for a missing slot")
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+)
+
+type FetchCmd struct {
+	FromBundles string `help:"Fetch updates from git bundles in this directory instead of origin" placeholder:"DIR"`
+}
+
+func (c *FetchCmd) Help() string {
+	return `Fetches updates for every bare repository in repos/.
+
+By default each repository is fetched from origin. With --from-bundles DIR,
+updates are read from DIR/<name>.bundle (or the 'bundle' path set in
+devslot.yaml) instead; only objects missing locally are transferred, so
+incremental bundles can be used.
+
+Repositories that have not been cloned yet are skipped (run 'devslot init').`
+}
+
+func (c *FetchCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	bundleDir, err := resolveBundleDir(c.FromBundles)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range cfg.Repositories {
+		bareRepoPath := filepath.Join(cfg.ReposDir(projectRoot), repo.BareRepoName())
+		if !git.IsValidRepository(bareRepoPath) {
+			ctx.Printf("Repository %s is not cloned, skipping (run 'devslot init')\n", repo.Name)
+			ctx.LogWarn("repository not cloned", "name", repo.Name)
+			continue
+		}
+
+		if bundleDir != "" {
+			bundlePath, err := findBundle(repo, bundleDir)
+			if err != nil {
+				return err
+			}
+			ctx.Printf("Fetching %s from bundle %s...\n", repo.Name, bundlePath)
+			ctx.LogInfo("fetching repository from bundle", "name", repo.Name, "bundle", bundlePath)
+			if err := git.FetchBundle(bareRepoPath, repo.RemoteName(), bundlePath); err != nil {
+				return errors.BundleFailed(repo.Name, err)
+			}
+			continue
+		}
+
+		ctx.Printf("Fetching %s...\n", repo.Name)
+		ctx.LogInfo("fetching repository", "name", repo.Name)
+		if err := git.Fetch(bareRepoPath, repo.RemoteName()); err != nil {
+			return errors.FetchFailed(err)
+		}
+	}
+
+	ctx.Println("\nFetch complete!")
+	ctx.LogInfo("fetch completed")
+
+	return nil
+}
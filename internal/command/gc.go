@@ -0,0 +1,171 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type GcCmd struct {
+	DryRun bool `name:"dry-run" help:"Only report what would be cleaned"`
+}
+
+func (c *GcCmd) Help() string {
+	return `Cleans up leftovers that accumulate over time:
+
+  - worktree registrations in repos/ whose directories no longer exist
+    (e.g. slots deleted by hand), via 'git worktree prune'
+  - temporary directories in slots/ (named ` + slot.TempPrefix + `*)
+  - the holder recorded in the project lock file when that process is no longer running
+
+Existing slots and their worktrees are never touched. Repositories with
+worktrees broken by moving the project are skipped; run 'devslot doctor --fix'
+first so that their registrations are repaired instead of pruned.`
+}
+
+func (c *GcCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	// The lock file records the PID of its last holder; read it before taking the lock ourselves
+	lockPath := filepath.Join(projectRoot, ".devslot.lock")
+	stalePID, stale := lock.Stale(lockPath)
+
+	// Acquire lock, except for a dry run: taking it would replace the stale holder it reports
+	if !c.DryRun {
+		lockFile := lock.New(lockPath)
+		if err := lockFile.Acquire(); err != nil {
+			return errors.LockFailed(err)
+		}
+		defer func() {
+			if err := lockFile.Release(); err != nil {
+				ctx.LogWarn("failed to release lock", "error", err)
+			}
+		}()
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if c.DryRun {
+		ctx.Println("Dry run: nothing will be removed.")
+	}
+
+	cleaned := 0
+
+	n, err := c.pruneWorktrees(ctx, projectRoot, cfg)
+	if err != nil {
+		return err
+	}
+	cleaned += n
+
+	n, err = c.removeTempDirs(ctx, projectRoot, cfg)
+	if err != nil {
+		return err
+	}
+	cleaned += n
+
+	if stale {
+		if c.DryRun {
+			ctx.Printf("Would clear stale lock file .devslot.lock (PID %d is not running)\n", stalePID)
+		} else {
+			// Taking the lock replaced the dead holder and releasing it clears the file. The
+			// file is never removed: another process could lock a new file while we hold the old one.
+			ctx.Printf("Cleared stale lock file .devslot.lock (PID %d is not running)\n", stalePID)
+		}
+		ctx.LogInfo("stale lock file", "pid", stalePID, "dryRun", c.DryRun)
+		cleaned++
+	}
+
+	switch {
+	case cleaned == 0:
+		ctx.Println("Nothing to clean.")
+	case c.DryRun:
+		ctx.Printf("%d item(s) would be cleaned.\n", cleaned)
+	default:
+		ctx.Printf("%d item(s) cleaned.\n", cleaned)
+	}
+
+	return nil
+}
+
+// pruneWorktrees prunes stale worktree registrations of every cloned repository, in devslot.yaml order
+func (c *GcCmd) pruneWorktrees(ctx *Context, projectRoot string, cfg *config.Config) (int, error) {
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	slots, err := mgr.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list slots: %w", err)
+	}
+	moved := map[string]bool{}
+	for _, slotName := range slots {
+		for _, wt := range movedWorktrees(projectRoot, slotName, cfg) {
+			moved[wt.bareRepoPath] = true
+		}
+	}
+
+	pruned := 0
+	for _, repo := range cfg.Repositories {
+		bareRepoPath := filepath.Join(cfg.ReposDir(projectRoot), repo.BareRepoName())
+		if !git.IsValidRepository(bareRepoPath) {
+			continue
+		}
+		if moved[bareRepoPath] {
+			ctx.Eprintf("Warning: skipping %s because some of its worktrees were moved (run 'devslot doctor --fix')\n", repo.Name)
+			continue
+		}
+
+		lines, err := git.PruneWorktrees(bareRepoPath, c.DryRun)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to prune worktrees of %s: %w", repo.Name, err)
+		}
+		for _, line := range lines {
+			ctx.Printf("%s: %s\n", repo.Name, line)
+		}
+		ctx.LogInfo("pruned worktrees", "repository", repo.Name, "count", len(lines), "dryRun", c.DryRun)
+		pruned += len(lines)
+	}
+	return pruned, nil
+}
+
+// removeTempDirs removes temporary directories left in slots/
+func (c *GcCmd) removeTempDirs(ctx *Context, projectRoot string, cfg *config.Config) (int, error) {
+	dirs, err := filepath.Glob(filepath.Join(cfg.SlotsDir(projectRoot), slot.TempPrefix+"*"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		rel := dir
+		if r, err := filepath.Rel(projectRoot, dir); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+		if c.DryRun {
+			ctx.Printf("Would remove temporary directory %s\n", rel)
+		} else {
+			if err := os.RemoveAll(dir); err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %w", rel, err)
+			}
+			ctx.Printf("Removed temporary directory %s\n", rel)
+		}
+		removed++
+	}
+	return removed, nil
+}
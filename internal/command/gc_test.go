@@ -0,0 +1,131 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestGcCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "live")
+
+	// A slot deleted by hand leaves its worktree registered in the bare repository
+	if err := (&CreateCmd{SlotName: "gone"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatal(err)
+	}
+	registration, err := git.WorktreeGitDir(filepath.Join(projectRoot, "slots", "gone", "repo1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(projectRoot, "slots", "gone")); err != nil {
+		t.Fatal(err)
+	}
+	tempDir := filepath.Join(projectRoot, "slots", ".tmp-create-123")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock file written by a process that has exited
+	proc := execCommand("true")
+	if err := proc.Run(); err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(projectRoot, ".devslot.lock")
+	testutil.CreateFile(t, lockPath, fmt.Sprintf("PID: %d\nTime: 2024-01-01T00:00:00Z\n", proc.Process.Pid))
+
+	bareRepo := filepath.Join(projectRoot, "repos", "repo1.git")
+	liveWorktree := filepath.Join(projectRoot, "slots", "live", "repo1")
+
+	// --dry-run only reports
+	var buf bytes.Buffer
+	if err := (&GcCmd{DryRun: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("gc --dry-run failed: %v", err)
+	}
+	for _, want := range []string{
+		"repo1: Removing worktrees/" + filepath.Base(registration),
+		"Would remove temporary directory slots/.tmp-create-123",
+		fmt.Sprintf("Would clear stale lock file .devslot.lock (PID %d is not running)", proc.Process.Pid),
+		"3 item(s) would be cleaned.",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in dry-run output, got:\n%s", want, buf.String())
+		}
+	}
+	if !testutil.DirExists(t, registration) || !testutil.DirExists(t, tempDir) {
+		t.Fatal("--dry-run removed something")
+	}
+
+	if holder := lock.ReadHolder(lockPath); holder == nil || holder.PID != proc.Process.Pid {
+		t.Fatalf("--dry-run changed the lock file, holder = %+v", holder)
+	}
+
+	buf.Reset()
+	if err := (&GcCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "3 item(s) cleaned.") {
+		t.Errorf("expected cleanup summary, got:\n%s", buf.String())
+	}
+	if testutil.DirExists(t, registration) {
+		t.Error("expected stale worktree registration to be pruned")
+	}
+	if testutil.DirExists(t, tempDir) {
+		t.Error("expected temporary directory to be removed")
+	}
+	if holder := lock.ReadHolder(lockPath); holder != nil {
+		t.Errorf("expected the stale lock holder to be cleared, got PID %d", holder.PID)
+	}
+
+	// The live slot is untouched
+	if output, err := execCommand("git", "-C", liveWorktree, "status").CombinedOutput(); err != nil {
+		t.Errorf("live worktree broken after gc: %v\n%s", err, output)
+	}
+	if worktrees := gitOutput(t, bareRepo, "worktree", "list", "--porcelain"); !strings.Contains(worktrees, "worktree "+liveWorktree) {
+		t.Errorf("live worktree registration was removed:\n%s", worktrees)
+	}
+
+	buf.Reset()
+	if err := (&GcCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("second gc failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Nothing to clean.") {
+		t.Errorf("expected nothing left to clean, got:\n%s", buf.String())
+	}
+}
+
+func TestGcCmd_SkipsMovedProject(t *testing.T) {
+	parent := testutil.TempDir(t)
+	oldRoot := filepath.Join(parent, "work")
+	if err := os.MkdirAll(oldRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	restore := testutil.Chdir(t, oldRoot)
+	setupProjectWithSlot(t, oldRoot, "feature")
+	restore()
+
+	newRoot := filepath.Join(parent, "src")
+	if err := os.Rename(oldRoot, newRoot); err != nil {
+		t.Fatal(err)
+	}
+	defer testutil.Chdir(t, newRoot)()
+
+	var buf bytes.Buffer
+	if err := (&GcCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Warning: skipping repo1") {
+		t.Errorf("expected moved repository to be skipped, got:\n%s", buf.String())
+	}
+	if !testutil.DirExists(t, filepath.Join(newRoot, "repos", "repo1.git", "worktrees", "repo1")) {
+		t.Error("registration of a moved worktree must not be pruned")
+	}
+}
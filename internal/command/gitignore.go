@@ -0,0 +1,85 @@
+package command
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+// gitignoreEntries returns the .gitignore entries a project needs: its repos, slots
+// and archives directories when they are inside the project, the lock file, and the
+// shared cache when shared_links are configured
+func gitignoreEntries(projectRoot string, cfg *config.Config) []string {
+	var entries []string
+	for _, dir := range []string{cfg.ReposDir(projectRoot), cfg.SlotsDir(projectRoot), cfg.ArchivesDir(projectRoot)} {
+		rel, err := filepath.Rel(projectRoot, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		entries = append(entries, "/"+filepath.ToSlash(rel)+"/")
+	}
+	entries = append(entries, "/.devslot.lock")
+	if len(cfg.SharedLinks) > 0 {
+		entries = append(entries, "/"+path.Dir(slot.SharedCacheDir)+"/")
+	}
+	return entries
+}
+
+// missingGitignoreEntries returns the entries that no line of a .gitignore covers.
+// Leading and trailing slashes are not compared, so /repos/ is covered by repos/,
+// and simple patterns such as *.lock are matched.
+func missingGitignoreEntries(content string, entries []string) []string {
+	var patterns []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, strings.Trim(line, "/"))
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		name := strings.Trim(entry, "/")
+		covered := false
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, name); matched {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// createOrAppendToFile appends content to a .gitignore, creating it if needed,
+// unless it already covers all of entries. It reports whether the file changed.
+func createOrAppendToFile(path, content string, entries []string) (bool, error) {
+	existingContent := ""
+	if data, err := os.ReadFile(path); err == nil {
+		existingContent = string(data)
+	}
+
+	if len(missingGitignoreEntries(existingContent, entries)) == 0 {
+		return false, nil // Already configured
+	}
+
+	// Append to existing content or create new
+	finalContent := content
+	if existingContent != "" {
+		finalContent = existingContent
+		if !strings.HasSuffix(existingContent, "\n") {
+			finalContent += "\n"
+		}
+		finalContent += "\n" + content
+	}
+
+	return true, os.WriteFile(path, []byte(finalContent), 0644)
+}
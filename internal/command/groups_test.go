@@ -0,0 +1,101 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// setupGroupedProject writes a devslot.yaml with web (frontend), api (core) and
+// lib (core, frontend), all cloned from local bare repositories
+func setupGroupedProject(t *testing.T, projectRoot string) {
+	t.Helper()
+
+	sources := testutil.TempDir(t)
+	var yaml strings.Builder
+	yaml.WriteString("version: 1\nrepositories:\n")
+	for _, repo := range []struct{ name, groups string }{
+		{"web", "[frontend]"},
+		{"api", "[core]"},
+		{"lib", "[core, frontend]"},
+	} {
+		source := filepath.Join(sources, repo.name+".git")
+		testutil.InitBareRepo(t, source)
+		yaml.WriteString("  - name: " + repo.name + "\n    url: " + source + "\n    groups: " + repo.groups + "\n")
+	}
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yaml.String())
+}
+
+func TestGroups_InitAndCreate(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupGroupedProject(t, projectRoot)
+
+	// init --group only clones the group
+	if err := (&InitCmd{Group: []string{"core"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	for repo, want := range map[string]bool{"web": false, "api": true, "lib": true} {
+		if got := testutil.DirExists(t, filepath.Join(projectRoot, "repos", repo+".git")); got != want {
+			t.Errorf("repos/%s.git exists = %v, want %v", repo, got, want)
+		}
+	}
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+
+	// create --group only adds worktrees for the group
+	if err := (&CreateCmd{SlotName: "ui", Group: []string{"frontend"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	slotPath := filepath.Join(projectRoot, "slots", "ui")
+	assertWorktrees := func() {
+		t.Helper()
+		for repo, want := range map[string]bool{"web": true, "api": false, "lib": true} {
+			if got := testutil.DirExists(t, filepath.Join(slotPath, repo)); got != want {
+				t.Errorf("worktree %s exists = %v, want %v", repo, got, want)
+			}
+		}
+	}
+	assertWorktrees()
+
+	// reload and status keep to the recorded groups
+	if err := (&ReloadCmd{SlotName: "ui"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	assertWorktrees()
+
+	var buf bytes.Buffer
+	if err := (&StatusCmd{SlotName: "ui"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("StatusCmd.Run() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "api") || strings.Contains(buf.String(), "missing") {
+		t.Errorf("status should only list the slot's groups, got:\n%s", buf.String())
+	}
+}
+
+func TestGroups_Unknown(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupGroupedProject(t, projectRoot)
+
+	for name, cmd := range map[string]interface{ Run(*Context) error }{
+		"init":   &InitCmd{Group: []string{"backend"}},
+		"create": &CreateCmd{SlotName: "x", Group: []string{"backend"}},
+	} {
+		err := cmd.Run(testContext(&bytes.Buffer{}))
+		if got := errors.ExitCode(err); got != errors.ExitUsage {
+			t.Errorf("%s: ExitCode() = %d, want %d (err = %v)", name, got, errors.ExitUsage, err)
+		}
+		if err == nil || !strings.Contains(err.Error(), "Known groups: core, frontend") {
+			t.Errorf("%s: expected known groups in error, got %v", name, err)
+		}
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "x")) {
+		t.Error("slot should not be created for an unknown group")
+	}
+}
@@ -0,0 +1,304 @@
+package command
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type HooksCmd struct {
+	List HooksListCmd `cmd:"" help:"List the hook types, their files and when they run"`
+	Run  HooksRunCmd  `cmd:"" help:"Run a hook manually with the environment of the real lifecycle"`
+	Lint HooksLintCmd `cmd:"" help:"Check the hook files for mistakes that make them fail"`
+}
+
+type HooksListCmd struct {
+	JSON bool `name:"json" help:"Print the hooks as JSON"`
+}
+
+func (c *HooksListCmd) Help() string {
+	return `Lists every hook type in lifecycle order with when it runs, whether its
+file exists in hooks/ and is executable, the file's size and modification
+time, and how many inline commands devslot.yaml defines for it.
+
+A hook is active when its file is executable or it has inline commands.`
+}
+
+// hookListEntry is the state of one hook type
+type hookListEntry struct {
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	File        string     `json:"file"`
+	Exists      bool       `json:"exists"`
+	Executable  bool       `json:"executable"`
+	Bytes       int64      `json:"bytes"`
+	Modified    *time.Time `json:"modified,omitempty"`
+	Inline      int        `json:"inline"`
+}
+
+func (c *HooksListCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	runner := hook.NewRunner(projectRoot)
+	entries := make([]hookListEntry, len(hook.Types))
+	for i, hookType := range hook.Types {
+		entry := hookListEntry{
+			Type:        string(hookType),
+			Description: hook.Descriptions[hookType],
+			File:        runner.Path(hookType),
+			Inline:      len(cfg.InlineHooks(string(hookType))),
+		}
+		if info, err := os.Stat(entry.File); err == nil {
+			modified := info.ModTime()
+			entry.Exists = true
+			entry.Executable = hook.Executable(info)
+			entry.Bytes = info.Size()
+			entry.Modified = &modified
+		}
+		entries[i] = entry
+	}
+
+	if c.JSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode hooks: %w", err)
+		}
+		ctx.Resultln(string(data))
+		return nil
+	}
+
+	ctx.Printf("%-13s  %-4s  %-10s  %9s  %-16s  %-6s  %s\n", "HOOK", "FILE", "EXECUTABLE", "SIZE", "MODIFIED", "INLINE", "RUNS")
+	for _, entry := range entries {
+		file, executable, size, modified := "no", "-", "-", "-"
+		if entry.Exists {
+			file, executable = "yes", "no"
+			if entry.Executable {
+				executable = "yes"
+			}
+			size = formatSize(entry.Bytes)
+			modified = entry.Modified.Local().Format("2006-01-02 15:04")
+		}
+		ctx.Printf("%-13s  %-4s  %-10s  %9s  %-16s  %-6d  %s\n", entry.Type, file, executable, size, modified, entry.Inline, entry.Description)
+	}
+	return nil
+}
+
+type HooksRunCmd struct {
+	Type     string `arg:"" help:"Hook type (post-init, post-create, post-reload, post-checkout, pre-destroy, post-destroy)"`
+	SlotName string `arg:"" optional:"" help:"Name of the slot (required for every hook type except post-init)"`
+	DryRun   bool   `help:"Print the hook path, working directory and environment without executing"`
+}
+
+func (c *HooksRunCmd) Help() string {
+	return `Runs a hook against an existing slot without going through the lifecycle,
+e.g. to iterate on a post-create hook without recreating the slot.
+
+The hook file and the inline commands from devslot.yaml receive exactly the
+environment the lifecycle would pass. The command exits with the hook's exit
+code, so it can be used in CI checks.
+
+post-init takes no slot name; every other hook type requires one, and hooks
+that run inside the slot directory require the slot to exist.`
+}
+
+func (c *HooksRunCmd) Run(ctx *Context) error {
+	hookType, err := hook.ParseType(c.Type)
+	if err != nil {
+		return err
+	}
+	// Valid slot names have no path separators, so DEVSLOT_SLOT_DIR stays inside the slots directory
+	if c.SlotName != "" {
+		if err := slot.ValidateName(c.SlotName); err != nil {
+			return err
+		}
+	}
+
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	runner := hook.NewRunner(projectRoot)
+	runner.ReposDir = cfg.ReposDir(projectRoot)
+	runner.SlotsDir = cfg.SlotsDir(projectRoot)
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+
+	var env map[string]string
+	if hookType == hook.PostInit {
+		if c.SlotName != "" {
+			return errors.InvalidUsage(fmt.Sprintf("hook %s does not take a slot name", hookType),
+				fmt.Sprintf("Run 'devslot hooks run %s' without a slot", hookType))
+		}
+		// Replay an init that found every cloned repository already present
+		var skipped []string
+		for _, repo := range cfg.Repositories {
+			if git.IsValidRepository(filepath.Join(cfg.ReposDir(projectRoot), repo.BareRepoName())) {
+				skipped = append(skipped, repo.Name)
+			}
+		}
+		env = postInitEnv(cfg, nil, skipped)
+	} else {
+		if c.SlotName == "" {
+			return errors.InvalidUsage(fmt.Sprintf("hook %s requires a slot name", hookType),
+				fmt.Sprintf("Run 'devslot hooks run %s <slot>'", hookType))
+		}
+		if runner.WorkDirs[hookType] == hook.WorkDirSlot {
+			if _, err := os.Stat(filepath.Join(cfg.SlotsDir(projectRoot), c.SlotName)); err != nil {
+				return errors.SlotNotFound(c.SlotName)
+			}
+		}
+		env, err = mgr.HookEnv(hookType, c.SlotName, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	inline := cfg.InlineHooks(string(hookType))
+
+	if c.DryRun {
+		c.printPlan(ctx, runner, hookType, inline, env)
+		return nil
+	}
+
+	ctx.LogInfo("running hook manually", "hook", hookType, "slot", c.SlotName)
+	if err := runner.RunAll(hookType, c.SlotName, inline, env); err != nil {
+		// Exit with the hook's own exit code rather than the one of failed lifecycle hooks
+		var exitErr *exec.ExitError
+		if stderrors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return fmt.Errorf("%s hook failed: %w", hookType, errors.ExitStatus(exitErr.ExitCode()))
+		}
+		return fmt.Errorf("%s hook failed: %w", hookType, err)
+	}
+
+	return nil
+}
+
+// printPlan prints what running the hook would execute
+func (c *HooksRunCmd) printPlan(ctx *Context, runner *hook.Runner, hookType hook.Type, inline []string, env map[string]string) {
+	hookPath := runner.Path(hookType)
+	if info, err := os.Stat(hookPath); err != nil {
+		ctx.Printf("Hook file: %s (not found)\n", hookPath)
+	} else if !hook.Executable(info) {
+		ctx.Printf("Hook file: %s (not executable)\n", hookPath)
+	} else {
+		ctx.Printf("Hook file: %s\n", hookPath)
+	}
+
+	if len(inline) > 0 {
+		ctx.Println("Inline commands:")
+		for _, command := range inline {
+			ctx.Printf("  %s\n", command)
+		}
+	}
+
+	ctx.Printf("Working directory: %s\n", runner.Dir(hookType, c.SlotName))
+
+	ctx.Println("Environment:")
+	vars := runner.Env(hookType, c.SlotName, env)
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		ctx.Printf("  %s=%s\n", k, vars[k])
+	}
+}
+
+type HooksLintCmd struct {
+	JSON bool `name:"json" help:"Print findings as JSON"`
+}
+
+func (c *HooksLintCmd) Help() string {
+	return `Checks the files in hooks/ the way 'devslot doctor' does, without the
+other doctor checks.
+
+Scripts without a #! line, interpreters that are not installed and Windows
+(CRLF) line endings are errors, since the hook cannot run. Files that are not
+executable and references to DEVSLOT_ variables that devslot never sets are
+warnings; 'devslot doctor --fix' makes hook files executable.
+
+With --json, the findings are printed in the format of 'devslot doctor --json'.
+The command fails with exit code 6 when errors were found.`
+}
+
+func (c *HooksLintCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	runner := hook.NewRunner(projectRoot)
+	findings := []DoctorFinding{}
+	linted := 0
+	for _, hookType := range hook.Types {
+		if _, err := os.Stat(runner.Path(hookType)); err != nil {
+			continue
+		}
+		linted++
+		for _, finding := range lintHook(runner, string(hookType), false) {
+			finding.Check = "hooks"
+			findings = append(findings, finding)
+		}
+	}
+
+	hasErrors := false
+	reported := []DoctorFinding{}
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			hasErrors = true
+		}
+		if finding.Severity != SeverityOK {
+			reported = append(reported, finding)
+		}
+	}
+
+	if c.JSON {
+		data, err := json.MarshalIndent(reported, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+		ctx.Resultln(string(data))
+	} else {
+		if linted == 0 {
+			ctx.Println("No hook files found.")
+		}
+		for _, finding := range findings {
+			printFinding(ctx, finding)
+		}
+	}
+
+	if hasErrors {
+		return errors.HookLintIssues()
+	}
+	return nil
+}
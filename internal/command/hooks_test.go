@@ -0,0 +1,201 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestHooksRunCmd_Run(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "hook-slot")
+
+	outFile := filepath.Join(projectRoot, "hook-output.txt")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"),
+		"#!/bin/sh\necho \"$DEVSLOT_SLOT_NAME $DEVSLOT_BRANCH_NAME\" > \""+outFile+"\"\nexit 3\n")
+
+	// Dry run prints the plan without executing
+	var buf bytes.Buffer
+	cmd := &HooksRunCmd{Type: "post-create", SlotName: "hook-slot", DryRun: true}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("HooksRunCmd.Run() dry run error = %v", err)
+	}
+	output := buf.String()
+	for _, expected := range []string{
+		"Hook file: " + filepath.Join(projectRoot, "hooks", "post-create"),
+		"Working directory: " + filepath.Join(projectRoot, "slots", "hook-slot"),
+		"DEVSLOT_SLOT_NAME=hook-slot",
+		"DEVSLOT_BRANCH_NAME=",
+		"DEVSLOT_REPOSITORIES=repo1",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("dry run output missing %q, got:\n%s", expected, output)
+		}
+	}
+	if testutil.FileExists(t, outFile) {
+		t.Error("dry run must not execute the hook")
+	}
+
+	// A real run passes the lifecycle environment and mirrors the exit code
+	cmd.DryRun = false
+	err := cmd.Run(testContext(&bytes.Buffer{}))
+	if err == nil {
+		t.Fatal("expected hook failure to be reported")
+	}
+	if code := errors.ExitCode(err); code != 3 {
+		t.Errorf("exit code = %d, want the hook's exit code 3 (error %v)", code, err)
+	}
+
+	got := strings.TrimSpace(testutil.ReadFile(t, outFile))
+	if !strings.HasPrefix(got, "hook-slot ") || strings.HasSuffix(got, " ") {
+		t.Errorf("unexpected hook environment: %q", got)
+	}
+}
+
+func TestHooksRunCmd_Validation(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	tests := []struct {
+		name        string
+		cmd         *HooksRunCmd
+		errContains string
+	}{
+		{name: "unknown type", cmd: &HooksRunCmd{Type: "post-merge"}, errContains: "unknown hook type"},
+		{name: "missing slot name", cmd: &HooksRunCmd{Type: "post-reload"}, errContains: "requires a slot name"},
+		{name: "slot not found", cmd: &HooksRunCmd{Type: "post-reload", SlotName: "nope"}, errContains: "not found"},
+		{name: "post-init with slot", cmd: &HooksRunCmd{Type: "post-init", SlotName: "nope"}, errContains: "does not take a slot name"},
+		{name: "slot outside the slots directory", cmd: &HooksRunCmd{Type: "post-create", SlotName: "../outside"}, errContains: "invalid slot name"},
+		{name: "slot outside the slots directory for a hook run in the project", cmd: &HooksRunCmd{Type: "post-destroy", SlotName: ".."}, errContains: "invalid slot name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Run(testContext(&bytes.Buffer{}))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestHooksLintCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "lint-slot")
+
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), "#!/bin/sh\r\necho \"$DEVSLOT_SLOT\"\r\n")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "hooks", "post-reload"), "#!/bin/sh\ntrue\n")
+
+	var buf bytes.Buffer
+	err := (&HooksLintCmd{JSON: true}).Run(testContext(&buf))
+	if code := errors.ExitCode(err); code != errors.ExitDoctorIssues {
+		t.Errorf("ExitCode() = %d, want %d (err = %v)", code, errors.ExitDoctorIssues, err)
+	}
+	var findings []DoctorFinding
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	want := []DoctorFinding{
+		{Check: "hooks", Severity: SeverityError, Target: "post-create", Message: "Hook post-create has Windows (CRLF) line endings"},
+		{Check: "hooks", Severity: SeverityWarning, Target: "post-create", Message: "Hook post-create uses DEVSLOT_SLOT, which devslot does not set"},
+		{Check: "hooks", Severity: SeverityWarning, Target: "post-reload", Message: "Hook post-reload exists but is not executable (run 'devslot doctor --fix')", Fixable: true},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", findings, want)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, findings[i], want[i])
+		}
+	}
+
+	// doctor --fix makes the hook executable
+	buf.Reset()
+	_ = (&DoctorCmd{Fix: true}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "Made hook post-reload executable") {
+		t.Errorf("expected doctor --fix to make the hook executable, got:\n%s", buf.String())
+	}
+	info, err := os.Stat(filepath.Join(projectRoot, "hooks", "post-reload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("post-reload mode = %v, want it executable", info.Mode())
+	}
+
+	// Only warnings are left once the script is fixed
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), "#!/bin/sh\necho \"$DEVSLOT_SLOT_NAME\"\n")
+	buf.Reset()
+	if err := (&HooksLintCmd{}).Run(testContext(&buf)); err != nil {
+		t.Errorf("HooksLintCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Hook post-create exists and is executable") {
+		t.Errorf("expected the fixed hook to pass, got:\n%s", buf.String())
+	}
+}
+
+func TestHooksListCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateProjectStructure(t, projectRoot)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\nhooks:\n  post-reload:\n    - direnv allow\n")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-create"), "#!/bin/sh\ntrue\n")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "hooks", "pre-destroy"), "#!/bin/sh\n")
+
+	var buf bytes.Buffer
+	if err := (&HooksListCmd{JSON: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("HooksListCmd.Run() error = %v", err)
+	}
+	var entries []hookListEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(entries) != len(hook.Types) {
+		t.Fatalf("got %d hooks, want one per hook type:\n%s", len(entries), buf.String())
+	}
+	byType := map[string]hookListEntry{}
+	for i, entry := range entries {
+		if entry.Type != string(hook.Types[i]) {
+			t.Errorf("entry %d is %s, want hooks in lifecycle order", i, entry.Type)
+		}
+		if entry.Description == "" {
+			t.Errorf("hook %s has no description", entry.Type)
+		}
+		byType[entry.Type] = entry
+	}
+	if e := byType["post-create"]; !e.Exists || !e.Executable || e.Bytes != 15 || e.Modified == nil {
+		t.Errorf("post-create = %+v, want an executable file of 15 bytes", e)
+	}
+	if e := byType["pre-destroy"]; !e.Exists || e.Executable {
+		t.Errorf("pre-destroy = %+v, want a file that is not executable", e)
+	}
+	if e := byType["post-reload"]; e.Exists || e.Inline != 1 || e.Modified != nil {
+		t.Errorf("post-reload = %+v, want one inline command and no file", e)
+	}
+
+	buf.Reset()
+	if err := (&HooksListCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("HooksListCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"HOOK           FILE  EXECUTABLE",
+		"post-create    yes   yes",
+		"pre-destroy    yes   no",
+		"post-init      no    -",
+		"after 'devslot checkout' switches the branch of a slot",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+)
+
+type ImportCmd struct {
+	Dir  string `arg:"" type:"path" help:"Directory containing the git clones to import"`
+	Move bool   `help:"Also clone each imported repository as a bare repository into repos/"`
+}
+
+func (c *ImportCmd) Help() string {
+	return `Adds the git clones found in a directory to devslot.yaml.
+
+Every direct subdirectory of DIR that is a git repository is listed in
+devslot.yaml under the name of its directory, with the URL of its origin
+remote. Repositories that devslot.yaml already lists are skipped, and so are
+clones without an origin, with a warning. When the current directory is not
+in a devslot project, devslot.yaml is created in it first, like
+'devslot boilerplate --template minimal' does.
+
+By default only devslot.yaml is changed; run 'devslot init' afterwards to
+clone the repositories. With --move, each imported clone is also cloned as a
+bare repository into repos/ from its local path, which avoids downloading it
+again; origin is then set to the clone's origin URL. The clones themselves
+are left in place.`
+}
+
+// importedRepository is a clone found by import
+type importedRepository struct {
+	config.Repository
+	path string // Directory of the clone
+}
+
+func (c *ImportCmd) Run(ctx *Context) error {
+	if info, err := os.Stat(c.Dir); err != nil || !info.IsDir() {
+		return errors.InvalidUsage(fmt.Sprintf("%s is not a directory", c.Dir),
+			"Pass the directory that contains your git clones")
+	}
+
+	projectRoot, err := c.projectRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	found, noOrigin, err := c.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Skip repositories that are already listed, under their name or their URL
+	var imports []importedRepository
+	listed := 0
+	for _, repo := range found {
+		i := slices.IndexFunc(cfg.Repositories, func(r config.Repository) bool {
+			return r.Name == repo.Name || git.SameURL(r.URL, repo.URL)
+		})
+		if i < 0 {
+			imports = append(imports, repo)
+			continue
+		}
+		listed++
+		if existing := cfg.Repositories[i]; existing.Name == repo.Name && !git.SameURL(existing.URL, repo.URL) {
+			ctx.Eprintf("Warning: skipping %s: %s lists it with URL %s\n", repo.path, filepath.Base(ctx.ConfigFile(projectRoot)), git.RedactURL(existing.URL))
+		} else {
+			ctx.Printf("Repository %s is already listed\n", existing.Name)
+		}
+	}
+
+	if len(imports) > 0 {
+		repos := make([]config.Repository, 0, len(imports))
+		for _, repo := range imports {
+			repos = append(repos, repo.Repository)
+		}
+		if err := addRepositories(ctx, ctx.ConfigFile(projectRoot), repos); err != nil {
+			return err
+		}
+	}
+
+	cloned := 0
+	if c.Move {
+		reposDir := cfg.ReposDir(projectRoot)
+		for _, repo := range imports {
+			ok, err := cloneImported(ctx, reposDir, repo)
+			if err != nil {
+				return err
+			}
+			if ok {
+				cloned++
+			}
+		}
+	}
+
+	ctx.Println("\nImport summary:")
+	ctx.Printf("  %d added, %d already listed, %d skipped without origin\n", len(imports), listed, noOrigin)
+	if c.Move {
+		ctx.Printf("  %d cloned into %s\n", cloned, cfg.ReposDir(projectRoot))
+	} else if len(imports) > 0 {
+		ctx.Println("Run 'devslot init' to clone the added repositories.")
+	}
+	ctx.LogInfo("import summary", "added", len(imports), "listed", listed, "no_origin", noOrigin, "cloned", cloned)
+	return nil
+}
+
+// projectRoot returns the project to import into, creating devslot.yaml in the
+// working directory when it is not in a project
+func (c *ImportCmd) projectRoot(ctx *Context) (string, error) {
+	if root, err := ctx.FindProjectRoot(); err == nil {
+		return root, nil
+	} else if ctx.ConfigPath != "" {
+		return "", err
+	}
+
+	root, err := ctx.WorkingDir()
+	if err != nil {
+		return "", err
+	}
+	minimal, err := builtinTemplate("minimal")
+	if err != nil {
+		return "", err
+	}
+	if err := minimal.copyFile(ctx, root, config.FileName); err != nil {
+		return "", err
+	}
+	// The lookup above cached its miss; the project created here must be found
+	ctx.finder = nil
+	return root, nil
+}
+
+// scan finds the git clones directly in the directory, sorted by name. It
+// returns the clones with an origin and the number of those without one.
+func (c *ImportCmd) scan(ctx *Context) ([]importedRepository, int, error) {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", c.Dir, err)
+	}
+
+	var found []importedRepository
+	noOrigin := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(c.Dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			ctx.LogDebug("not a git clone", "path", path)
+			continue
+		}
+
+		url, err := git.GetRemoteURL(path, "origin")
+		if err != nil {
+			ctx.Eprintf("Warning: skipping %s: it has no origin remote\n", path)
+			noOrigin++
+			continue
+		}
+		found = append(found, importedRepository{Repository: config.Repository{Name: entry.Name(), URL: url}, path: path})
+	}
+	return found, noOrigin, nil
+}
+
+// cloneImported clones an imported clone as the bare repository of its
+// repository, keeping its origin URL. It reports whether it cloned.
+func cloneImported(ctx *Context, reposDir string, repo importedRepository) (bool, error) {
+	bareRepoPath := filepath.Join(reposDir, repo.BareRepoName())
+	if git.IsValidRepository(bareRepoPath) {
+		ctx.Printf("Repository %s is already cloned\n", repo.Name)
+		return false, nil
+	}
+
+	ctx.Printf("Cloning %s from %s...\n", repo.Name, repo.path)
+	if err := git.CloneBareWithOptions(repo.path, bareRepoPath, git.CloneOptions{Output: ctx.Err}); err != nil {
+		return false, errors.CloneFailed(repo.Name, err)
+	}
+	if err := git.SetRemoteURL(bareRepoPath, "origin", repo.URL); err != nil {
+		return false, fmt.Errorf("failed to set origin of %s: %w", repo.Name, err)
+	}
+	ctx.LogInfo("repository cloned from local clone", "name", repo.Name, "path", repo.path)
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// cloneInto clones a new repository with an origin into dir/name
+func cloneInto(t *testing.T, dir, name string) string {
+	t.Helper()
+	origin := filepath.Join(testutil.TempDir(t), name+".git")
+	testutil.InitBareRepo(t, origin)
+	clonePath := filepath.Join(dir, name)
+	if output, err := exec.Command("git", "clone", "--quiet", origin, clonePath).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v: %s", err, output)
+	}
+	return origin
+}
+
+func TestImportCmd_Run(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	clones := testutil.TempDir(t)
+	apiURL := cloneInto(t, clones, "api")
+	webURL := cloneInto(t, clones, "web")
+	local := filepath.Join(clones, "scratch")
+	testutil.CreateFile(t, filepath.Join(local, "README"), "")
+	if err := git.Init(local); err != nil {
+		t.Fatal(err)
+	}
+	testutil.CreateFile(t, filepath.Join(clones, "notes", "todo.txt"), "")
+
+	var out, errOut bytes.Buffer
+	ctx := testContext(&out)
+	ctx.Err = &errOut
+	if err := (&ImportCmd{Dir: clones}).Run(ctx); err != nil {
+		t.Fatalf("ImportCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Warning: skipping "+local+": it has no origin remote") {
+		t.Errorf("expected a warning about the clone without origin, got:\n%s", errOut.String())
+	}
+	if !strings.Contains(out.String(), "2 added, 0 already listed, 1 skipped without origin") {
+		t.Errorf("expected an import summary, got:\n%s", out.String())
+	}
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		t.Fatalf("devslot.yaml was not created: %v", err)
+	}
+	want := map[string]string{"api": apiURL, "web": webURL}
+	if len(cfg.Repositories) != len(want) {
+		t.Fatalf("repositories = %+v, want api and web", cfg.Repositories)
+	}
+	for _, repo := range cfg.Repositories {
+		if want[repo.Name] != repo.URL {
+			t.Errorf("repository %s has URL %q, want %q", repo.Name, repo.URL, want[repo.Name])
+		}
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "api.git")) {
+		t.Error("import without --move must not clone")
+	}
+
+	// A second import finds everything listed and clones with --move
+	out.Reset()
+	if err := (&ImportCmd{Dir: clones, Move: true}).Run(testContext(&out)); err != nil {
+		t.Fatalf("ImportCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "0 added, 2 already listed") {
+		t.Errorf("expected the repositories to be reported as listed, got:\n%s", out.String())
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "api.git")) {
+		t.Error("--move only clones the repositories it adds")
+	}
+}
+
+func TestImportCmd_Move(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	clones := testutil.TempDir(t)
+	apiURL := cloneInto(t, clones, "api")
+
+	var out bytes.Buffer
+	if err := (&ImportCmd{Dir: clones, Move: true}).Run(testContext(&out)); err != nil {
+		t.Fatalf("ImportCmd.Run() error = %v", err)
+	}
+	bareRepoPath := filepath.Join(projectRoot, "repos", "api.git")
+	if !git.IsValidRepository(bareRepoPath) {
+		t.Fatal("expected api to be cloned into repos/")
+	}
+	if url, err := git.GetRemoteURL(bareRepoPath, "origin"); err != nil || url != apiURL {
+		t.Errorf("origin of the bare repository = %q, %v; want %q", url, err, apiURL)
+	}
+	if !testutil.DirExists(t, filepath.Join(clones, "api")) {
+		t.Error("the clone must be left in place")
+	}
+	if !strings.Contains(out.String(), "1 cloned into") {
+		t.Errorf("expected the clone in the summary, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type InfoCmd struct {
+	Drift bool `help:"List worktrees whose branch differs from the branch recorded at slot creation"`
+}
+
+func (c *InfoCmd) Help() string {
+	return `Shows information about the current devslot project.
+
+With --drift, lists every worktree across all slots whose checked-out
+branch differs from the branch recorded when the slot was created.`
+}
+
+func (c *InfoCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a devslot project: %w", err)
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	slots, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list slots: %w", err)
+	}
+
+	if c.Drift {
+		return c.printDrift(ctx, mgr, cfg, slots)
+	}
+
+	ctx.Printf("Project root: %s\n", projectRoot)
+	ctx.Printf("Repositories: %d\n", len(cfg.Repositories))
+	ctx.Printf("Slots: %d\n", len(slots))
+
+	return nil
+}
+
+func (c *InfoCmd) printDrift(ctx *Context, mgr *slot.Manager, cfg *config.Config, slots []string) error {
+	found := 0
+	for _, slotName := range slots {
+		statuses, err := mgr.Status(slotName, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to inspect slot %s: %w", slotName, err)
+		}
+
+		for _, status := range statuses {
+			if !status.Drifted() {
+				continue
+			}
+			if found == 0 {
+				ctx.Println("Drifted worktrees:")
+			}
+			found++
+			ctx.Printf("  %s/%s\n", slotName, formatRepoStatus(status))
+		}
+	}
+
+	if found == 0 {
+		ctx.Println("No drifted worktrees found.")
+		return nil
+	}
+
+	ctx.Println("\nRun 'devslot reload --update <slot>' to record intentional branch switches")
+	return nil
+}
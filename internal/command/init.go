@@ -1,9 +1,11 @@
 package command
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,42 +14,100 @@ import (
 	"github.com/yammerjp/devslot/internal/git"
 	"github.com/yammerjp/devslot/internal/hook"
 	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/metrics"
+	"github.com/yammerjp/devslot/internal/parallel"
+	"github.com/yammerjp/devslot/internal/version"
 )
 
 type InitCmd struct {
-	AllowDelete bool `help:"Delete repositories no longer listed in devslot.yaml"`
+	AllowDelete bool     `help:"Delete repositories no longer listed in devslot.yaml"`
+	Force       bool     `help:"With --allow-delete, delete repositories even if slots still have worktrees from them"`
+	FromBundles string   `help:"Clone repositories from git bundles in this directory instead of their URLs" placeholder:"DIR"`
+	UpdateURLs  bool     `name:"update-urls" help:"Point origin of existing repositories at the URL in devslot.yaml when they differ"`
+	Fetch       bool     `help:"Fetch updates for repositories that already exist"`
+	Retries     int      `help:"Retry failed clones up to N times with exponential backoff" default:"0" placeholder:"N"`
+	Filter      string   `help:"Partial clone filter (e.g. blob:none) for repositories without clone_filter in devslot.yaml" placeholder:"SPEC"`
+	NoHardlinks bool     `name:"no-hardlinks" help:"Copy objects of local repositories instead of hardlinking them"`
+	NoProgress  bool     `name:"no-progress" help:"Don't report the progress of clones"`
+	Group       []string `help:"Only clone repositories in these groups (repeatable)" placeholder:"GROUP"`
+	DryRun      bool     `name:"dry-run" help:"Show the repositories that would be cloned, fetched or deleted without changing anything"`
+	Timings     bool     `help:"Print how long the clone or fetch of each repository took"`
 }
 
+// cloneRetryBaseDelay is the wait before the first clone retry; it doubles with every attempt
+var cloneRetryBaseDelay = time.Second
+
+// cloneRetryMaxDelay caps the wait between clone retries
+const cloneRetryMaxDelay = 30 * time.Second
+
+// initFetchConcurrency limits how many repositories 'devslot init --fetch' fetches at once
+const initFetchConcurrency = 4
+
 func (c *InitCmd) Help() string {
 	return `Clones repositories defined in devslot.yaml as bare repositories into repos/.
 
 This command:
-  - Only clones missing repositories (skips existing ones)
+  - Only clones missing repositories (skips existing ones, or fetches
+    them from origin in parallel with --fetch)
+  - Warns when the origin URL of an existing repository differs from
+    devslot.yaml (use --update-urls to rewrite it)
   - Does not affect existing slots or worktrees
   - Preserves unlisted repositories unless --allow-delete is used
+    (repositories with worktrees in existing slots are kept unless --force)
   - Runs post-init hook if it exists
 
+With --group, only repositories tagged with one of the groups in devslot.yaml
+are cloned (and fetched with --fetch).
+
+Partial clones are made with --filter SPEC or the per-repository
+clone_filter setting, which takes precedence over the flag. Repositories with
+single_branch: true are cloned with only their branch setting (or the remote's
+default branch), and later fetches only update that branch.
+
+While a repository is cloned, git's progress is shown on stderr, prefixed with
+the name of the repository. When stderr is not a terminal, a "Still cloning"
+line is printed every 30 seconds instead. --no-progress turns both off.
+
+Local repositories (paths and file:// URLs) share objects with the source
+through hardlinks when possible; use --no-hardlinks to copy them instead.
+
+With --from-bundles DIR, each repository is cloned from DIR/<name>.bundle
+(or the 'bundle' path set in devslot.yaml) instead of its URL. The URL is
+still recorded as origin so the repository can be fetched directly later.
+
+With --dry-run, the repositories that would be cloned, fetched (with --fetch)
+and deleted (with --allow-delete) are listed; nothing is changed and the
+post-init hook does not run.
+
+With --timings, how long the clone or fetch of each repository took is
+printed on stderr at the end, slowest first. The table is also printed
+without it when init took more than 30 seconds, unless --quiet is given.
+
 Safe to run multiple times.`
 }
 
 func (c *InitCmd) Run(ctx *Context) error {
-	// Find project root
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+	if c.Retries < 0 {
+		return errors.InvalidUsage(fmt.Sprintf("--retries must not be negative, got %d", c.Retries),
+			"Use --retries=0 to clone each repository once")
 	}
 
-	ctx.LogDebug("looking for project root", "currentDir", currentDir)
-	projectRoot, err := config.FindProjectRoot(currentDir)
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
 	if err != nil {
 		return err // config.FindProjectRoot already returns a user-friendly error
 	}
 	ctx.LogDebug("found project root", "projectRoot", projectRoot)
 
+	// Fail before cloning or creating worktrees when git is missing
+	if err := ctx.RequireGit(projectRoot); err != nil {
+		return err
+	}
+
 	// Acquire lock
 	l := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
 	if err := l.Acquire(); err != nil {
-		return err
+		return errors.LockFailed(err)
 	}
 	defer func() {
 		if err := l.Release(); err != nil {
@@ -56,15 +116,34 @@ func (c *InitCmd) Run(ctx *Context) error {
 	}()
 
 	// Load configuration
-	cfg, err := config.Load(projectRoot)
+	cfg, err := ctx.LoadConfig(projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	if c.DryRun {
+		return c.dryRun(ctx, projectRoot, cfg)
+	}
+
+	if _, err := c.Initialize(ctx, projectRoot, cfg); err != nil {
+		return err
+	}
+	ctx.Println("\nInitialization complete!")
+	ctx.Println("You can now create a slot with 'devslot create <slot-name>'")
+	ctx.LogInfo("initialization completed")
+
+	return nil
+}
+
+// Initialize clones missing repositories, fetches existing ones with --fetch,
+// removes unlisted ones with --allow-delete and runs the post-init hook. The
+// caller holds the project lock. It prints and returns the summary; the summary
+// is also returned with an error once cloning has started.
+func (c *InitCmd) Initialize(ctx *Context, projectRoot string, cfg *config.Config) (*InitSummary, error) {
 	// Create repos directory if it doesn't exist
-	reposDir := filepath.Join(projectRoot, "repos")
+	reposDir := cfg.ReposDir(projectRoot)
 	if err := os.MkdirAll(reposDir, 0755); err != nil {
-		return fmt.Errorf("failed to create repos directory: %w", err)
+		return nil, fmt.Errorf("failed to create repos directory: %w", err)
 	}
 
 	// Test mode sleep for concurrent lock testing
@@ -74,74 +153,550 @@ func (c *InitCmd) Run(ctx *Context) error {
 		}
 	}
 
+	// The previous manifest tells which devslot invocation created existing repositories
+	previousManifest, err := loadInitManifest(reposDir)
+	if err != nil {
+		ctx.LogWarn("failed to read init manifest", "error", err)
+	}
+
+	bundleDir, err := resolveBundleDir(c.FromBundles)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := c.plan(ctx, projectRoot, reposDir, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the slowest repositories once init is done, even when it failed
+	timings := ctx.newTimings()
+	defer ctx.printTimings(timings, c.Timings)
+
 	// Clone each repository as bare
-	for _, repo := range cfg.Repositories {
+	summary := &InitSummary{}
+	for _, repo := range plan.repos {
 		bareRepoPath := filepath.Join(reposDir, repo.BareRepoName())
 
 		// Check if repository already exists
-		if git.IsValidRepository(bareRepoPath) {
+		if plan.existing[repo.Name] {
 			ctx.Printf("Repository %s already exists, skipping...\n", repo.Name)
 			ctx.LogInfo("skipping existing repository", "name", repo.Name)
+			if err := c.checkRemoteURL(ctx, repo, bareRepoPath); err != nil {
+				return nil, err
+			}
+			// Upgrade clones made before the fetch refspec was configured
+			if err := git.EnsureFetchConfig(bareRepoPath, repo.RemoteName()); err != nil {
+				ctx.Eprintf("Warning: %s: %v\n", repo.Name, err)
+			}
+			summary.Skipped = append(summary.Skipped, repo.Name)
 			continue
 		}
 
-		ctx.Printf("Cloning %s from %s...\n", repo.Name, repo.URL)
-		ctx.LogInfo("cloning repository", "name", repo.Name, "url", repo.URL)
-		if err := git.CloneBare(repo.URL, bareRepoPath); err != nil {
-			return errors.CloneFailed(repo.Name, err)
+		stop := timings.Start("clone " + repo.Name)
+		err := c.cloneRepository(ctx, repo, bareRepoPath, bundleDir)
+		stop()
+		if err != nil {
+			if repo.Optional {
+				ctx.Warn("Could not clone optional repository %s: %v", repo.Name, err)
+				ctx.LogWarn("failed to clone optional repository", "name", repo.Name, "error", err)
+				summary.Failed = append(summary.Failed, repo.Name)
+				continue
+			}
+			summary.Failed = append(summary.Failed, repo.Name)
+			summary.print(ctx, c.Fetch)
+			return summary, errors.WithNote(err, previousManifest.provenance())
 		}
 		ctx.Printf("Successfully cloned %s\n", repo.Name)
+		summary.Cloned = append(summary.Cloned, repo.Name)
+	}
+
+	// Fetch repositories that already existed. The post-init hook still gets them
+	// as skipped, although the summary counts them as fetched.
+	existing := slices.Clone(summary.Skipped)
+	if c.Fetch {
+		if err := c.fetchExisting(ctx, timings, reposDir, plan.repos, summary); err != nil {
+			summary.print(ctx, c.Fetch)
+			return summary, err
+		}
+	}
+
+	// Record which devslot invocation initialized the repositories
+	repoNames := make([]string, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		repoNames[i] = repo.Name
+	}
+	manifest := &initManifest{
+		InitializedAt:  time.Now(),
+		DevslotVersion: version.Version,
+		Args:           invocationArgs(),
+		Repositories:   repoNames,
+	}
+	if err := saveInitManifest(reposDir, manifest); err != nil {
+		ctx.LogWarn("failed to write init manifest", "error", err)
 	}
 
 	// Handle --allow-delete flag
+	var deleteErr error
 	if c.AllowDelete {
-		// Get list of existing repositories
-		entries, err := os.ReadDir(reposDir)
+		deleteErr = c.removeUnlisted(ctx, reposDir, cfg, plan.unlisted)
+	}
+
+	// Run post-init hook
+	hookRunner := hook.NewRunner(projectRoot)
+	hookRunner.ReposDir = cfg.ReposDir(projectRoot)
+	hookRunner.SlotsDir = cfg.SlotsDir(projectRoot)
+	hookRunner.Stdout = ctx.Out
+	hookRunner.Stderr = ctx.Err
+	ctx.LogDebug("running post-init hook")
+
+	if err := hookRunner.RunAll(hook.PostInit, "", cfg.InlineHooks(string(hook.PostInit)), postInitEnv(cfg, summary.Cloned, existing)); err != nil {
+		ctx.LogWarn("post-init hook failed", "error", err)
+		return summary, fmt.Errorf("post-init hook failed: %w", err)
+	}
+
+	summary.print(ctx, c.Fetch)
+	return summary, deleteErr
+}
+
+// cloneRepository clones a single repository as bare, from its bundle when a bundle directory is given
+func (c *InitCmd) cloneRepository(ctx *Context, repo config.Repository, bareRepoPath, bundleDir string) error {
+	if bundleDir != "" {
+		bundlePath, err := findBundle(repo, bundleDir)
 		if err != nil {
-			return fmt.Errorf("failed to read repos directory: %w", err)
+			return err
+		}
+		ctx.Printf("Cloning %s from bundle %s...\n", repo.Name, bundlePath)
+		ctx.LogInfo("cloning repository from bundle", "name", repo.Name, "bundle", bundlePath, "url", git.RedactURL(repo.URL))
+		if err := git.CloneBareFromBundle(bundlePath, repo.URL, bareRepoPath, repo.RemoteName()); err != nil {
+			return errors.BundleFailed(repo.Name, err)
 		}
+		return nil
+	}
 
-		// Build a map of configured repositories
-		configuredRepos := make(map[string]bool)
-		for _, repo := range cfg.Repositories {
-			configuredRepos[repo.BareRepoName()] = true
+	filter := repo.CloneFilter
+	if filter == "" {
+		filter = c.Filter
+	}
+
+	if filter != "" {
+		ctx.Printf("Cloning %s from %s (filter: %s)...\n", repo.Name, repo.URL, filter)
+	} else {
+		ctx.Printf("Cloning %s from %s...\n", repo.Name, repo.URL)
+	}
+	ctx.LogInfo("cloning repository", "name", repo.Name, "url", git.RedactURL(repo.URL), "filter", filter, "no_hardlinks", c.NoHardlinks, "single_branch", repo.SingleBranch)
+	opts := git.CloneOptions{
+		Filter:       filter,
+		NoHardlinks:  c.NoHardlinks,
+		Remote:       repo.RemoteName(),
+		SingleBranch: repo.SingleBranch,
+		Branch:       repo.Branch,
+	}
+	stopProgress := c.reportProgress(ctx, repo.Name, &opts)
+	defer stopProgress()
+	clone := func() error { return git.CloneBareWithOptions(repo.URL, bareRepoPath, opts) }
+	if err := cloneWithRetry(ctx, repo.Name, bareRepoPath, c.Retries, clone, time.Sleep); err != nil {
+		return errors.CloneFailed(repo.Name, err)
+	}
+	return nil
+}
+
+// reportProgress sets up how a clone reports its progress: git's output goes to
+// stderr prefixed with the repository name, with git's progress meter when stderr
+// is a terminal and periodic heartbeats otherwise. It returns a function that
+// stops the reporting.
+func (c *InitCmd) reportProgress(ctx *Context, name string, opts *git.CloneOptions) func() {
+	output := newPrefixWriter(ctx.Err, name+": ")
+	opts.Output = output
+	if c.NoProgress || ctx.Verbosity == VerbosityQuiet {
+		return output.Flush
+	}
+	if isTerminal(ctx.Err) {
+		opts.Progress = true
+		return output.Flush
+	}
+
+	stopHeartbeat := startHeartbeat(ctx, name, cloneHeartbeatInterval)
+	return func() {
+		stopHeartbeat()
+		output.Flush()
+	}
+}
+
+// cloneWithRetry runs clone until it succeeds or the retries are exhausted, waiting with
+// exponential backoff and removing the partially created destination between attempts
+func cloneWithRetry(ctx *Context, name, destPath string, retries int, clone func() error, sleep func(time.Duration)) error {
+	// Never remove a destination that existed before cloning started
+	_, statErr := os.Stat(destPath)
+	preexisting := statErr == nil
+
+	delay := cloneRetryBaseDelay
+	attempts := max(retries, 0) + 1
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = clone(); err == nil {
+			return nil
 		}
+		if !preexisting {
+			if rmErr := os.RemoveAll(destPath); rmErr != nil {
+				ctx.LogWarn("failed to clean up partial clone", "name", name, "error", rmErr)
+			}
+		}
+		if attempt == attempts {
+			break
+		}
+
+		ctx.Printf("Cloning %s failed (attempt %d of %d), retrying in %s...\n", name, attempt, attempts, delay)
+		ctx.LogWarn("clone failed, retrying", "name", name, "attempt", attempt, "delay", delay, "error", err)
+		sleep(delay)
+		delay = min(delay*2, cloneRetryMaxDelay)
+	}
 
-		// Remove repositories not in configuration
-		for _, entry := range entries {
-			if entry.IsDir() && !configuredRepos[entry.Name()] {
-				repoPath := filepath.Join(reposDir, entry.Name())
-				ctx.Printf("Removing unlisted repository: %s\n", entry.Name())
-				ctx.LogInfo("removing unlisted repository", "name", entry.Name())
-				if err := os.RemoveAll(repoPath); err != nil {
-					ctx.LogWarn("failed to remove repository", "name", entry.Name(), "error", err)
-				}
+	if attempts > 1 {
+		return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+	}
+	return err
+}
+
+// removeUnlisted deletes repositories in repos/ that are not listed in devslot.yaml.
+// Repositories that still have worktrees in slots are kept unless --force is given.
+// All failures are collected and returned together.
+func (c *InitCmd) removeUnlisted(ctx *Context, reposDir string, cfg *config.Config, unlisted []unlistedRepository) error {
+	_, namespaces := configuredRepoNames(cfg)
+
+	// Remove repositories not in configuration
+	var errs []error
+	for _, repo := range unlisted {
+		name := repo.name
+		repoPath := filepath.Join(reposDir, name)
+		repoName := strings.TrimSuffix(name, ".git")
+
+		if len(repo.slots) > 0 {
+			if !c.Force {
+				ctx.Printf("Keeping unlisted repository %s: used by slots %s\n", name, strings.Join(repo.slots, ", "))
+				errs = append(errs, errors.RepositoryInUse(repoName, repo.slots))
+				continue
 			}
+			ctx.Eprintf("Warning: worktrees of %s in slots %s will be broken\n", name, strings.Join(repo.slots, ", "))
+		}
+
+		ctx.Printf("Removing unlisted repository: %s\n", name)
+		ctx.LogInfo("removing unlisted repository", "name", name)
+		if err := os.RemoveAll(repoPath); err != nil {
+			ctx.LogWarn("failed to remove repository", "name", name, "error", err)
+			errs = append(errs, fmt.Errorf("failed to remove repository %s: %w", name, err))
+			continue
+		}
+
+		// Remove namespace directories left empty
+		if namespace := filepath.Dir(name); namespace != "." && !namespaces[namespace] {
+			_ = os.Remove(filepath.Join(reposDir, namespace))
 		}
 	}
 
-	// Run post-init hook
-	hookRunner := hook.NewRunner(projectRoot)
-	ctx.LogDebug("running post-init hook")
+	return stderrors.Join(errs...)
+}
 
-	// Build repository names list
-	repoNames := make([]string, len(cfg.Repositories))
-	for i, repo := range cfg.Repositories {
-		repoNames[i] = repo.Name
+// initPlan lists what init does, worked out before anything is changed
+type initPlan struct {
+	repos    []config.Repository  // Repositories of the selected groups
+	existing map[string]bool      // Names of the repositories that are already cloned
+	unlisted []unlistedRepository // With --allow-delete, repositories that devslot.yaml does not list
+	postInit bool                 // Whether a post-init hook exists
+}
+
+// unlistedRepository is a repository in repos/ that devslot.yaml does not list
+type unlistedRepository struct {
+	name  string   // Path relative to repos/ (e.g. old.git or platform/api.git)
+	slots []string // Slots that still have worktrees from it
+}
+
+// plan works out which repositories init clones, skips and deletes
+func (c *InitCmd) plan(ctx *Context, projectRoot, reposDir string, cfg *config.Config) (*initPlan, error) {
+	repos, err := cfg.ReposInGroups(c.Group...)
+	if err != nil {
+		return nil, err
 	}
 
-	hookEnv := map[string]string{
-		"DEVSLOT_REPOSITORIES": strings.Join(repoNames, " "),
+	plan := &initPlan{repos: repos, existing: map[string]bool{}}
+	for _, repo := range repos {
+		if git.IsValidRepository(filepath.Join(reposDir, repo.BareRepoName())) {
+			plan.existing[repo.Name] = true
+		}
 	}
 
-	if err := hookRunner.Run(hook.PostInit, "", hookEnv); err != nil {
-		ctx.LogWarn("post-init hook failed", "error", err)
-		return fmt.Errorf("post-init hook failed: %w", err)
+	if c.AllowDelete {
+		configuredRepos, namespaces := configuredRepoNames(cfg)
+		unlisted, err := unlistedRepositories(reposDir, configuredRepos, namespaces)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read repos directory: %w", err)
+		}
+		for _, name := range unlisted {
+			worktrees, err := git.ListWorktrees(filepath.Join(reposDir, name))
+			if err != nil {
+				ctx.LogDebug("failed to list worktrees", "name", name, "error", err)
+			}
+			plan.unlisted = append(plan.unlisted, unlistedRepository{
+				name:  name,
+				slots: slotsUsingWorktrees(cfg.SlotsDir(projectRoot), worktrees),
+			})
+		}
 	}
 
-	ctx.Println("\nInitialization complete!")
-	ctx.Println("You can now create a slot with 'devslot create <slot-name>'")
-	ctx.LogInfo("initialization completed")
+	plan.postInit = hook.NewRunner(projectRoot).Exists(hook.PostInit) || len(cfg.InlineHooks(string(hook.PostInit))) > 0
+	return plan, nil
+}
+
+// dryRun lists what init would clone, fetch and delete
+func (c *InitCmd) dryRun(ctx *Context, projectRoot string, cfg *config.Config) error {
+	bundleDir, err := resolveBundleDir(c.FromBundles)
+	if err != nil {
+		return err
+	}
+	plan, err := c.plan(ctx, projectRoot, cfg.ReposDir(projectRoot), cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range plan.repos {
+		switch {
+		case plan.existing[repo.Name] && c.Fetch:
+			ctx.Printf("Would fetch %s\n", repo.Name)
+		case plan.existing[repo.Name]:
+			ctx.Printf("Repository %s already exists\n", repo.Name)
+		case bundleDir != "":
+			bundlePath, err := findBundle(repo, bundleDir)
+			if err != nil {
+				return err
+			}
+			ctx.Printf("Would clone %s from bundle %s\n", repo.Name, bundlePath)
+		default:
+			ctx.Printf("Would clone %s from %s\n", repo.Name, git.RedactURL(repo.URL))
+		}
+	}
+
+	for _, repo := range plan.unlisted {
+		switch {
+		case len(repo.slots) == 0:
+			ctx.Printf("Would remove unlisted repository %s\n", repo.name)
+		case c.Force:
+			ctx.Printf("Would remove unlisted repository %s, breaking worktrees in slots %s\n", repo.name, strings.Join(repo.slots, ", "))
+		default:
+			ctx.Printf("Would keep unlisted repository %s: used by slots %s\n", repo.name, strings.Join(repo.slots, ", "))
+		}
+	}
+
+	if plan.postInit {
+		printHooks(ctx, []hook.Type{hook.PostInit})
+	} else {
+		printHooks(ctx, nil)
+	}
+	ctx.Println(dryRunNotice)
+	return nil
+}
+
+// configuredRepoNames returns the bare repository names of the configured repositories
+// (e.g. platform/api.git) and the namespaces they live in (e.g. platform)
+func configuredRepoNames(cfg *config.Config) (configuredRepos, namespaces map[string]bool) {
+	configuredRepos = make(map[string]bool)
+	namespaces = make(map[string]bool)
+	for _, repo := range cfg.Repositories {
+		configuredRepos[repo.BareRepoName()] = true
+		if namespace, _, ok := strings.Cut(repo.Name, "/"); ok {
+			namespaces[namespace] = true
+		}
+	}
+	return configuredRepos, namespaces
+}
+
+// unlistedRepositories returns the paths, relative to reposDir, of repositories not in configuredRepos.
+// Directories holding namespaced repositories (e.g. platform/ for platform/api.git) are searched one level deep.
+func unlistedRepositories(reposDir string, configuredRepos, namespaces map[string]bool) ([]string, error) {
+	entries, err := os.ReadDir(reposDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var unlisted []string
+	for _, entry := range entries {
+		if !entry.IsDir() || configuredRepos[entry.Name()] {
+			continue
+		}
+
+		path := filepath.Join(reposDir, entry.Name())
+		if !namespaces[entry.Name()] && (strings.HasSuffix(entry.Name(), ".git") || git.IsValidRepository(path) || !hasBareRepoChildren(path)) {
+			unlisted = append(unlisted, entry.Name())
+			continue
+		}
+
+		children, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			name := entry.Name() + "/" + child.Name()
+			if child.IsDir() && !configuredRepos[name] {
+				unlisted = append(unlisted, name)
+			}
+		}
+	}
+	return unlisted, nil
+}
+
+// hasBareRepoChildren reports whether a directory contains *.git directories, i.e. is a namespace
+func hasBareRepoChildren(path string) bool {
+	children, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	for _, child := range children {
+		if child.IsDir() && strings.HasSuffix(child.Name(), ".git") {
+			return true
+		}
+	}
+	return false
+}
+
+// slotsUsingWorktrees returns the sorted names of slots containing any of the worktree paths
+func slotsUsingWorktrees(slotsDir string, worktrees []string) []string {
+	slotsDirs := []string{slotsDir}
+	if resolved, err := filepath.EvalSymlinks(slotsDirs[0]); err == nil && resolved != slotsDirs[0] {
+		slotsDirs = append(slotsDirs, resolved)
+	}
 
+	var slots []string
+	for _, worktree := range worktrees {
+		for _, slotsDir := range slotsDirs {
+			rel, err := filepath.Rel(slotsDir, worktree)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			slotName := strings.Split(filepath.ToSlash(rel), "/")[0]
+			if !slices.Contains(slots, slotName) {
+				slots = append(slots, slotName)
+			}
+			break
+		}
+	}
+
+	slices.Sort(slots)
+	return slots
+}
+
+// checkRemoteURL compares the remote of an existing repository with the configured URL,
+// warning about a mismatch or rewriting the remote when --update-urls is given
+func (c *InitCmd) checkRemoteURL(ctx *Context, repo config.Repository, bareRepoPath string) error {
+	remote := repo.RemoteName()
+	originURL, err := git.GetRemoteURL(bareRepoPath, remote)
+	if err != nil || git.SameURL(originURL, repo.URL) {
+		// Repositories without the remote have nothing to compare
+		return nil
+	}
+
+	if !c.UpdateURLs {
+		ctx.Eprintf("Warning: %s of %s is %s but devslot.yaml has %s (run 'devslot init --update-urls' to update it)\n", remote, repo.Name, originURL, repo.URL)
+		ctx.LogWarn("origin URL differs from configuration", "name", repo.Name, "remote", remote, "origin", originURL, "configured", repo.URL)
+		return nil
+	}
+
+	if err := git.SetRemoteURL(bareRepoPath, remote, repo.URL); err != nil {
+		return fmt.Errorf("failed to update %s URL of %s: %w", remote, repo.Name, err)
+	}
+	ctx.Printf("Updated %s of %s from %s to %s\n", remote, repo.Name, originURL, repo.URL)
+	ctx.LogInfo("updated origin URL", "name", repo.Name, "remote", remote, "from", originURL, "to", repo.URL)
 	return nil
 }
+
+// fetchExisting fetches the skipped repositories of the summary from their remote in parallel.
+// Results are reported in devslot.yaml order; repositories without the remote stay skipped.
+// A failure doesn't stop the other fetches, and the returned error names every failed repository.
+func (c *InitCmd) fetchExisting(ctx *Context, timings *metrics.Recorder, reposDir string, repos []config.Repository, summary *InitSummary) error {
+	remotes := make(map[string]string, len(repos))
+	for _, repo := range repos {
+		remotes[repo.Name] = repo.RemoteName()
+	}
+
+	names := summary.Skipped
+	type fetchResult struct {
+		updated  bool
+		noRemote bool
+	}
+
+	results := make([]fetchResult, len(names))
+	tasks := make([]parallel.Task, len(names))
+	for i, name := range names {
+		bareRepoPath := filepath.Join(reposDir, name+".git")
+		remote := remotes[name]
+		tasks[i] = parallel.Task{Name: name, Run: func() error {
+			defer timings.Start("fetch " + name)()
+			if !git.HasRemote(bareRepoPath, remote) {
+				results[i].noRemote = true
+				return nil
+			}
+			var err error
+			results[i].updated, err = git.FetchUpdated(bareRepoPath, remote)
+			return err
+		}}
+	}
+	errs, fetchErr := parallel.Run(initFetchConcurrency, tasks)
+
+	// Existing repositories are counted as fetched (or failed) instead of skipped
+	summary.Skipped = nil
+
+	for i, name := range names {
+		result := results[i]
+		switch {
+		case result.noRemote:
+			ctx.LogInfo("repository has no remote to fetch from", "name", name, "remote", remotes[name])
+			summary.Skipped = append(summary.Skipped, name)
+		case errs[i] != nil:
+			ctx.Printf("Failed to fetch %s: %v\n", name, errs[i])
+			ctx.LogWarn("failed to fetch repository", "name", name, "error", errs[i])
+			summary.Failed = append(summary.Failed, name)
+		case result.updated:
+			ctx.Printf("Fetched %s (new refs)\n", name)
+			summary.Fetched = append(summary.Fetched, name)
+		default:
+			ctx.Printf("Fetched %s (up to date)\n", name)
+			summary.Fetched = append(summary.Fetched, name)
+		}
+	}
+
+	if fetchErr != nil {
+		return errors.FetchFailed(fetchErr)
+	}
+	return nil
+}
+
+// InitSummary lists what happened to each repository during init
+type InitSummary struct {
+	Cloned  []string // Repositories cloned by this run
+	Fetched []string // Existing repositories fetched with --fetch
+	Skipped []string // Existing repositories that were left as they were
+	Failed  []string // Repositories that could not be cloned or fetched
+}
+
+// print prints the summary line; the fetched count is shown when --fetch was given
+func (s *InitSummary) print(ctx *Context, fetch bool) {
+	if fetch {
+		ctx.Printf("\n%d cloned, %d fetched, %d skipped, %d failed\n", len(s.Cloned), len(s.Fetched), len(s.Skipped), len(s.Failed))
+	} else {
+		ctx.Printf("\n%d cloned, %d skipped, %d failed\n", len(s.Cloned), len(s.Skipped), len(s.Failed))
+	}
+	ctx.LogInfo("init summary", "cloned", len(s.Cloned), "fetched", len(s.Fetched), "skipped", len(s.Skipped), "failed", len(s.Failed))
+}
+
+// postInitEnv builds the repository environment variables passed to the post-init hook.
+// DEVSLOT_CLONED_REPOSITORIES and DEVSLOT_SKIPPED_REPOSITORIES split the repositories
+// by whether this invocation cloned them or found them already present.
+func postInitEnv(cfg *config.Config, cloned, skipped []string) map[string]string {
+	repos := make([]hook.Repository, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		repos[i] = hook.Repository{Name: repo.Name, URL: repo.URL}
+	}
+
+	env := hook.RepositoriesEnv(repos)
+	env["DEVSLOT_CLONED_REPOSITORIES"] = strings.Join(cloned, " ")
+	env["DEVSLOT_SKIPPED_REPOSITORIES"] = strings.Join(skipped, " ")
+	return env
+}
@@ -2,13 +2,18 @@ package command
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-	"syscall"
 	"testing"
+	"time"
 
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
 	"github.com/yammerjp/devslot/internal/testutil"
 )
 
@@ -18,11 +23,6 @@ func execCommand(name string, arg ...string) *exec.Cmd {
 }
 
 func TestInitCmd_Run(t *testing.T) {
-	// Skip network-dependent tests in CI
-	if os.Getenv("CI") == "true" {
-		t.Skip("Skipping network-dependent test in CI")
-	}
-
 	tests := []struct {
 		name         string
 		allowDelete  bool
@@ -57,30 +57,7 @@ repositories: []
 			setupFunc: func(t *testing.T, projectRoot string) {
 				// Create a local git repository
 				localRepoPath := filepath.Join(projectRoot, "local-repo")
-				if err := os.MkdirAll(localRepoPath, 0755); err != nil {
-					t.Fatalf("failed to create local repo directory: %v", err)
-				}
-
-				// Initialize as a git repository
-				cmd := execCommand("git", "init")
-				cmd.Dir = localRepoPath
-				if output, err := cmd.CombinedOutput(); err != nil {
-					t.Fatalf("failed to init local repo: %v\nOutput: %s", err, output)
-				}
-
-				// Create a commit
-				testutil.CreateFile(t, filepath.Join(localRepoPath, "README.md"), "# Test Repo")
-				cmd = execCommand("git", "add", ".")
-				cmd.Dir = localRepoPath
-				if output, err := cmd.CombinedOutput(); err != nil {
-					t.Fatalf("failed to add files: %v\nOutput: %s", err, output)
-				}
-
-				cmd = execCommand("git", "commit", "-m", "Initial commit")
-				cmd.Dir = localRepoPath
-				if output, err := cmd.CombinedOutput(); err != nil {
-					t.Fatalf("failed to commit: %v\nOutput: %s", err, output)
-				}
+				testutil.NewRepoFixture(t).Push(localRepoPath)
 
 				yamlContent := `version: 1
 repositories:
@@ -201,7 +178,7 @@ repositories:
 			cmd := &InitCmd{
 				AllowDelete: tt.allowDelete,
 			}
-			ctx := &Context{Writer: &buf, Logger: nil}
+			ctx := testContext(&buf)
 
 			err := cmd.Run(ctx)
 			if (err != nil) != tt.wantErr {
@@ -221,6 +198,346 @@ repositories:
 	}
 }
 
+func TestInitCmd_ClonedAndSkippedRepositories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	// new-repo is cloned from a local bare repository, existing-repo is already present
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+
+	yamlContent := `version: 1
+repositories:
+  - name: new-repo
+    url: ` + sourceRepo + `
+  - name: existing-repo
+    url: https://example.com/existing-repo.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	existingRepoPath := filepath.Join(projectRoot, "repos", "existing-repo.git")
+	if output, err := execCommand("git", "init", "--bare", existingRepoPath).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create bare repo: %v\nOutput: %s", err, output)
+	}
+
+	hookScript := `#!/bin/bash
+echo "$DEVSLOT_CLONED_REPOSITORIES" > "$DEVSLOT_ROOT/post-init-cloned"
+echo "$DEVSLOT_SKIPPED_REPOSITORIES" > "$DEVSLOT_ROOT/post-init-skipped"
+echo "$DEVSLOT_REPOSITORIES" > "$DEVSLOT_ROOT/post-init-repos"
+`
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-init"), hookScript)
+
+	var buf bytes.Buffer
+	if err := (&InitCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+
+	markers := map[string]string{
+		"post-init-cloned":  "new-repo",
+		"post-init-skipped": "existing-repo",
+		"post-init-repos":   "new-repo existing-repo",
+	}
+	for file, want := range markers {
+		got := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, file)))
+		if got != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+
+	if !strings.Contains(buf.String(), "1 cloned, 1 skipped, 0 failed") {
+		t.Errorf("expected summary line, got:\n%s", buf.String())
+	}
+}
+
+func TestInitCmd_UpdateURLs(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	yamlContent := `version: 1
+repositories:
+  - name: moved-repo
+    url: https://github.com/example/moved-repo
+  - name: same-repo
+    url: https://github.com/example/same-repo
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	remotes := map[string]string{
+		"moved-repo": "https://gitlab.example.com/example/moved-repo.git",
+		"same-repo":  "https://github.com/example/same-repo.git",
+	}
+	for name, url := range remotes {
+		repoPath := filepath.Join(projectRoot, "repos", name+".git")
+		if output, err := execCommand("git", "init", "--bare", repoPath).CombinedOutput(); err != nil {
+			t.Fatalf("failed to create bare repo: %v\nOutput: %s", err, output)
+		}
+		if output, err := execCommand("git", "-C", repoPath, "remote", "add", "origin", url).CombinedOutput(); err != nil {
+			t.Fatalf("failed to add remote: %v\nOutput: %s", err, output)
+		}
+	}
+
+	originOf := func(name string) string {
+		output, err := execCommand("git", "-C", filepath.Join(projectRoot, "repos", name+".git"), "remote", "get-url", "origin").Output()
+		if err != nil {
+			t.Fatalf("failed to get origin: %v", err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	// Without --update-urls only a warning is printed
+	var buf, errBuf bytes.Buffer
+	if err := (&InitCmd{}).Run(&Context{Out: &buf, Err: &errBuf}); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(errBuf.String(), "Warning: origin of moved-repo is https://gitlab.example.com/example/moved-repo.git") {
+		t.Errorf("expected URL mismatch warning on Err, got:\n%s", errBuf.String())
+	}
+	if strings.Contains(buf.String(), "Warning") {
+		t.Errorf("expected no warning on Out, got:\n%s", buf.String())
+	}
+	if strings.Contains(errBuf.String(), "origin of same-repo") {
+		t.Errorf("trailing .git should not be reported as a mismatch, got:\n%s", errBuf.String())
+	}
+	if got := originOf("moved-repo"); got != remotes["moved-repo"] {
+		t.Errorf("origin changed without --update-urls: %s", got)
+	}
+
+	buf.Reset()
+	_ = (&DoctorCmd{}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "Repository moved-repo origin is") {
+		t.Errorf("expected doctor to report URL mismatch, got:\n%s", buf.String())
+	}
+
+	// With --update-urls origin is rewritten
+	buf.Reset()
+	if err := (&InitCmd{UpdateURLs: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if got := originOf("moved-repo"); got != "https://github.com/example/moved-repo" {
+		t.Errorf("origin = %s, want the configured URL", got)
+	}
+	if got := originOf("same-repo"); got != remotes["same-repo"] {
+		t.Errorf("matching origin should be left alone, got %s", got)
+	}
+}
+
+func TestInitCmd_Fetch(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+
+	yamlContent := `version: 1
+repositories:
+  - name: fetched-repo
+    url: ` + sourceRepo + `
+  - name: local-only
+    url: https://example.com/local-only.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	// fetched-repo was cloned earlier; local-only has no origin remote
+	if output, err := execCommand("git", "clone", "--bare", sourceRepo, filepath.Join(projectRoot, "repos", "fetched-repo.git")).CombinedOutput(); err != nil {
+		t.Fatalf("failed to clone: %v\nOutput: %s", err, output)
+	}
+	if output, err := execCommand("git", "init", "--bare", filepath.Join(projectRoot, "repos", "local-only.git")).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create bare repo: %v\nOutput: %s", err, output)
+	}
+
+	// Push a new commit to the source
+	workDir := filepath.Join(testutil.TempDir(t), "work")
+	if output, err := execCommand("git", "clone", sourceRepo, workDir).CombinedOutput(); err != nil {
+		t.Fatalf("failed to clone source: %v\nOutput: %s", err, output)
+	}
+	for _, args := range [][]string{
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "New commit"},
+		{"push", "origin", "HEAD"},
+	} {
+		if output, err := execCommand("git", append([]string{"-C", workDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (&InitCmd{Fetch: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "Fetched fetched-repo (new refs)") {
+		t.Errorf("expected fetched-repo to receive new refs, got:\n%s", output)
+	}
+	if !strings.Contains(output, "0 cloned, 1 fetched, 1 skipped, 0 failed") {
+		t.Errorf("expected summary line, got:\n%s", output)
+	}
+
+	// A second fetch has nothing new
+	buf.Reset()
+	if err := (&InitCmd{Fetch: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Fetched fetched-repo (up to date)") {
+		t.Errorf("expected fetched-repo to be up to date, got:\n%s", buf.String())
+	}
+}
+
+func TestInitCmd_PartialClone(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	// Partial clones need a transport that supports filters, so use a file:// URL
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+	if output, err := execCommand("git", "-C", sourceRepo, "config", "uploadpack.allowFilter", "true").CombinedOutput(); err != nil {
+		t.Fatalf("failed to configure source: %v\nOutput: %s", err, output)
+	}
+
+	yamlContent := `version: 1
+repositories:
+  - name: partial-repo
+    url: file://` + sourceRepo + `
+    clone_filter: blob:none
+  - name: full-repo
+    url: file://` + sourceRepo + `
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	_ = (&DoctorCmd{}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "Repository partial-repo is a partial clone (filter: blob:none)") {
+		t.Errorf("expected doctor to report the partial clone, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "Repository full-repo is a partial clone") {
+		t.Errorf("full clone reported as partial:\n%s", buf.String())
+	}
+
+	// Worktrees can be created from the partial clone
+	if err := (&CreateCmd{SlotName: "partial"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	readme := filepath.Join(projectRoot, "slots", "partial", "partial-repo", "README.md")
+	if got := testutil.ReadFile(t, readme); got != "# Test Repository" {
+		t.Errorf("unexpected README content in partial worktree: %q", got)
+	}
+}
+
+func TestInitCmd_AllowDeleteRepositoryInUse(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "busy-slot")
+
+	// repo1 is no longer listed but busy-slot still has a worktree from it
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+	unusedRepo := filepath.Join(projectRoot, "repos", "unused.git")
+	if output, err := execCommand("git", "init", "--bare", unusedRepo).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create bare repo: %v\nOutput: %s", err, output)
+	}
+
+	var buf bytes.Buffer
+	err := (&InitCmd{AllowDelete: true}).Run(testContext(&buf))
+	if err == nil {
+		t.Fatal("expected error when deleting a repository in use")
+	}
+	if !strings.Contains(err.Error(), "refusing to delete repository repo1") || !strings.Contains(err.Error(), "busy-slot") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "repos", "repo1.git")) {
+		t.Error("repository in use must not be deleted")
+	}
+	if testutil.DirExists(t, unusedRepo) {
+		t.Error("unused repository should still be deleted")
+	}
+
+	// --force deletes it anyway
+	buf.Reset()
+	if err := (&InitCmd{AllowDelete: true, Force: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() with --force error = %v", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "repo1.git")) {
+		t.Error("expected --force to delete the repository")
+	}
+	if !strings.Contains(buf.String(), "will be broken") {
+		t.Errorf("expected a warning about broken worktrees, got:\n%s", buf.String())
+	}
+}
+
+func TestCloneWithRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		failures    int
+		wantErr     bool
+		wantCalls   int
+		wantSleeps  []time.Duration
+		errContains []string
+	}{
+		{name: "success on first attempt", retries: 3, failures: 0, wantCalls: 1},
+		{name: "negative retries clone once", retries: -1, failures: 1, wantErr: true, wantCalls: 1, errContains: []string{"Could not resolve host"}},
+		{name: "no retries by default", retries: 0, failures: 1, wantErr: true, wantCalls: 1, errContains: []string{"Could not resolve host"}},
+		{name: "succeeds after failures", retries: 3, failures: 2, wantCalls: 3, wantSleeps: []time.Duration{time.Second, 2 * time.Second}},
+		{name: "exhausts retries", retries: 2, failures: 5, wantErr: true, wantCalls: 3, wantSleeps: []time.Duration{time.Second, 2 * time.Second},
+			errContains: []string{"after 3 attempts", "Could not resolve host"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destPath := filepath.Join(testutil.TempDir(t), "repo.git")
+
+			calls := 0
+			clone := func() error {
+				calls++
+				// Each attempt must start from a clean destination
+				if _, err := os.Stat(destPath); err == nil {
+					t.Errorf("attempt %d: partial destination was not removed", calls)
+				}
+				if err := os.MkdirAll(destPath, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if calls <= tt.failures {
+					return fmt.Errorf("exit status 128: fatal: Could not resolve host: example.com")
+				}
+				return nil
+			}
+
+			var sleeps []time.Duration
+			sleep := func(d time.Duration) { sleeps = append(sleeps, d) }
+
+			err := cloneWithRetry(testContext(&bytes.Buffer{}), "repo", destPath, tt.retries, clone, sleep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cloneWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("clone called %d times, want %d", calls, tt.wantCalls)
+			}
+			if !slices.Equal(sleeps, tt.wantSleeps) {
+				t.Errorf("sleeps = %v, want %v", sleeps, tt.wantSleeps)
+			}
+		})
+	}
+}
+
+func TestCloneWithRetry_KeepsPreexistingDestination(t *testing.T) {
+	destPath := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(destPath, "keep.txt"), "data")
+
+	clone := func() error { return fmt.Errorf("destination path already exists") }
+	if err := cloneWithRetry(testContext(&bytes.Buffer{}), "repo", destPath, 1, clone, func(time.Duration) {}); err == nil {
+		t.Fatal("expected error")
+	}
+	if !testutil.FileExists(t, filepath.Join(destPath, "keep.txt")) {
+		t.Error("pre-existing destination must not be removed")
+	}
+}
+
 func TestInitCmd_ConcurrentLock(t *testing.T) {
 	// Skip in CI to avoid timing issues
 	if os.Getenv("CI") == "true" {
@@ -238,34 +555,138 @@ repositories: []
 	// Change to project directory
 	defer testutil.Chdir(t, projectRoot)()
 
-	// Create lock manually to simulate concurrent access
-	lockPath := filepath.Join(projectRoot, ".devslot.lock")
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		t.Fatalf("failed to create lock file: %v", err)
-	}
-	defer lockFile.Close()
-
-	// Acquire lock on the file
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+	// Hold the lock to simulate concurrent access
+	held := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := held.Acquire(); err != nil {
 		t.Fatalf("failed to acquire lock: %v", err)
 	}
-	defer func() {
-		if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN); err != nil {
-			t.Logf("Warning: failed to unlock file: %v", err)
-		}
-	}()
+	defer func() { _ = held.Release() }()
 
 	// Try to run init command while lock is held
 	var buf bytes.Buffer
 	cmd := &InitCmd{}
-	ctx := &Context{Writer: &buf, Logger: nil}
+	ctx := testContext(&buf)
 
-	err = cmd.Run(ctx)
+	err := cmd.Run(ctx)
 	if err == nil {
 		t.Error("expected error due to lock contention, got nil")
 	}
 	if !strings.Contains(err.Error(), "another devslot process") && !strings.Contains(err.Error(), "lock is already held") {
 		t.Errorf("expected lock error, got: %v", err)
 	}
+	if code := errors.ExitCode(err); code != errors.ExitLockHeld {
+		t.Errorf("ExitCode() = %d, want %d (err = %v)", code, errors.ExitLockHeld, err)
+	}
+}
+
+func TestInitCmd_DryRun(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "busy-slot")
+
+	// repo1 is replaced by new-repo, and unused.git is left over
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories:\n  - name: new-repo\n    url: "+sourceRepo+"\n")
+	unusedRepo := filepath.Join(projectRoot, "repos", "unused.git")
+	testutil.InitBareRepo(t, unusedRepo)
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-init"), "#!/bin/sh\ntouch \"$DEVSLOT_ROOT/post-init-ran\"\n")
+
+	var buf bytes.Buffer
+	if err := (&InitCmd{AllowDelete: true, DryRun: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"Would clone new-repo from " + sourceRepo,
+		"Would keep unlisted repository repo1.git: used by slots busy-slot",
+		"Would remove unlisted repository unused.git",
+		"Would run post-init hook",
+		dryRunNotice,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "new-repo.git")) {
+		t.Error("dry run must not clone")
+	}
+	if !testutil.DirExists(t, unusedRepo) {
+		t.Error("dry run must not delete repositories")
+	}
+	if testutil.FileExists(t, filepath.Join(projectRoot, "post-init-ran")) {
+		t.Error("dry run must not run hooks")
+	}
+}
+
+func TestInitCmd_GitNotFound(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Cleanup(func() { git.SetBinary("") })
+
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"),
+		"version: 1\ngit_binary: devslot-missing-git\nrepositories:\n  - name: app\n    url: "+sourceRepo+"\n")
+
+	err := (&InitCmd{}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "git not found (devslot-missing-git)") {
+		t.Fatalf("InitCmd.Run() error = %v, want git not found", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "app.git")) {
+		t.Error("repository was cloned without git")
+	}
+
+	// DEVSLOT_GIT takes precedence over git_binary
+	t.Setenv("DEVSLOT_GIT", "git")
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() with DEVSLOT_GIT error = %v", err)
+	}
+	t.Setenv("DEVSLOT_GIT", filepath.Join(projectRoot, "missing", "git"))
+	err = (&CreateCmd{SlotName: "slot1"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "git not found") {
+		t.Fatalf("CreateCmd.Run() error = %v, want git not found", err)
+	}
+}
+
+func TestInitCmd_FetchHookEnvironment(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"),
+		"version: 1\nrepositories:\n  - name: app\n    url: "+sourceRepo+"\n")
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "hooks", "post-init"),
+		"#!/bin/sh\necho \"$DEVSLOT_CLONED_REPOSITORIES|$DEVSLOT_SKIPPED_REPOSITORIES\" > \"$DEVSLOT_ROOT/post-init-env\"\n")
+	var buf bytes.Buffer
+	if err := (&InitCmd{Fetch: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() with --fetch error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Fetched app") {
+		t.Errorf("expected app to be fetched, got:\n%s", buf.String())
+	}
+
+	// Fetched repositories already existed, so the hook gets them as skipped
+	if got := strings.TrimSpace(testutil.ReadFile(t, filepath.Join(projectRoot, "post-init-env"))); got != "|app" {
+		t.Errorf("post-init got cloned|skipped = %q, want %q", got, "|app")
+	}
+}
+
+func TestInitCmd_NegativeRetries(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	err := (&InitCmd{Retries: -1}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "--retries must not be negative") {
+		t.Fatalf("InitCmd.Run() error = %v, want a usage error", err)
+	}
+	if code := errors.ExitCode(err); code != errors.ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, errors.ExitUsage)
+	}
 }
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"os"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+// redacted replaces secret values in recorded command lines
+const redacted = "REDACTED"
+
+// secretFlagWords mark flags whose values must never be recorded
+var secretFlagWords = []string{"token", "password", "secret"}
+
+// invocationArgs returns the current command-line arguments with secrets removed,
+// suitable for recording in slot metadata and the init manifest
+func invocationArgs() []string {
+	return sanitizeArgs(os.Args[1:])
+}
+
+// sanitizeArgs strips credentials from URLs and values of secret-bearing flags
+func sanitizeArgs(args []string) []string {
+	sanitized := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		if redactNext {
+			sanitized = append(sanitized, redacted)
+			redactNext = false
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			name, value, hasValue := strings.Cut(arg, "=")
+			if isSecretFlag(name) {
+				if hasValue {
+					sanitized = append(sanitized, name+"="+redacted)
+				} else {
+					sanitized = append(sanitized, name)
+					redactNext = true
+				}
+				continue
+			}
+			if hasValue {
+				sanitized = append(sanitized, name+"="+git.RedactURL(value))
+				continue
+			}
+		}
+
+		sanitized = append(sanitized, git.RedactURL(arg))
+	}
+
+	return sanitized
+}
+
+// isSecretFlag reports whether a flag name suggests its value is a secret
+func isSecretFlag(name string) bool {
+	name = strings.ToLower(strings.TrimLeft(name, "-"))
+	for _, word := range secretFlagWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
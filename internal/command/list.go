@@ -1,34 +1,126 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
 	"github.com/yammerjp/devslot/internal/slot"
 )
 
-type ListCmd struct{}
+type ListCmd struct {
+	Porcelain bool   `help:"Print one slot name per line"`
+	Archived  bool   `help:"List archived slots instead"`
+	JSON      bool   `name:"json" help:"Print slots with their descriptions as JSON"`
+	Sort      string `enum:"name,created,modified" default:"name" help:"Order of the slots: name, created or modified (newest first)"`
+	Filter    string `help:"Only list slots whose name contains this text or matches this glob pattern"`
+}
+
+// listEntry is a slot as printed by list --json
+type listEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// listedSlot is a slot with the information list shows and sorts by
+type listedSlot struct {
+	name        string
+	description string
+	template    string
+	created     time.Time
+	modified    time.Time
+}
+
+// maxListDescription is the number of characters of a description shown by list
+const maxListDescription = 60
+
+func (c *ListCmd) Help() string {
+	return `Lists all existing slots.
+
+With the global --verbose flag, also shows the template a slot was created
+from and the branch checked out in each worktree. Worktrees that are no longer on the branch recorded at slot
+creation are marked with ≠.
+
+Slot descriptions (see 'devslot describe') are shown after the name, cut to
+their first line; --verbose and --json show them in full.
+
+With --porcelain or the global --quiet flag, only the slot names are printed,
+one per line, and nothing is printed when there are no slots.
+
+Slots are sorted by name. --sort=created and --sort=modified list the newest
+first, by the creation time recorded in the slot and by when the slot or its
+metadata last changed; slots without metadata use the time of their
+directory. --filter keeps the slots whose name contains the given text or, when
+it has *, ? or [, matches it as a glob pattern. Both apply to --json as well.
+
+With --archived, the archives made by 'devslot archive' are listed instead,
+newest first for each slot.`
+}
 
 func (c *ListCmd) Run(ctx *Context) error {
 	// Find project root
-	currentDir, err := os.Getwd()
+	projectRoot, err := ctx.FindProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return fmt.Errorf("not in a devslot project: %w", err)
 	}
 
-	projectRoot, err := config.FindProjectRoot(currentDir)
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
 	if err != nil {
-		return fmt.Errorf("not in a devslot project: %w", err)
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// List slots
 	mgr := slot.NewManager(projectRoot)
-	slots, err := mgr.List()
+	mgr.Err = ctx.Err
+	mgr.SetDirs(cfg)
+	if c.Archived {
+		if c.JSON {
+			return errors.InvalidUsage("--json cannot be combined with --archived",
+				"Use 'devslot list --archived --porcelain' to list archives in a script")
+		}
+		return c.listArchives(ctx, mgr)
+	}
+
+	names, err := mgr.List()
 	if err != nil {
 		return fmt.Errorf("failed to list slots: %w", err)
 	}
+	slots, err := c.listedSlots(ctx, cfg.SlotsDir(projectRoot), names)
+	if err != nil {
+		return err
+	}
 
+	if c.Porcelain || ctx.Verbosity == VerbosityQuiet && !c.JSON {
+		for _, listed := range slots {
+			ctx.Resultln(listed.name)
+		}
+		return nil
+	}
+
+	if c.JSON {
+		entries := make([]listEntry, 0, len(slots))
+		for _, listed := range slots {
+			entries = append(entries, listEntry{Name: listed.name, Description: listed.description})
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode slots: %w", err)
+		}
+		ctx.Resultln(string(data))
+		return nil
+	}
+
+	if len(slots) == 0 && len(names) > 0 {
+		ctx.Printf("No slots match %q.\n", c.Filter)
+		return nil
+	}
 	if len(slots) == 0 {
 		ctx.Println("No slots found.")
 		ctx.Println("Create a new slot with 'devslot create <slot-name>'")
@@ -38,9 +130,127 @@ func (c *ListCmd) Run(ctx *Context) error {
 
 	ctx.Println("Available slots:")
 	ctx.LogInfo("listing slots", "count", len(slots))
-	for _, slotName := range slots {
-		ctx.Printf("  - %s\n", slotName)
+	for _, listed := range slots {
+		slotName, description := listed.name, listed.description
+		if !ctx.Verbose {
+			if description != "" {
+				ctx.Printf("  - %s: %s\n", slotName, shortDescription(description))
+			} else {
+				ctx.Printf("  - %s\n", slotName)
+			}
+			continue
+		}
+
+		if template := listed.template; template != "" {
+			ctx.Printf("  - %s (template %s)\n", slotName, template)
+		} else {
+			ctx.Printf("  - %s\n", slotName)
+		}
+		for _, line := range strings.Split(description, "\n") {
+			if line != "" {
+				ctx.Printf("    %s\n", line)
+			}
+		}
+
+		statuses, err := mgr.Status(slotName, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to inspect slot %s: %w", slotName, err)
+		}
+		for _, status := range statuses {
+			ctx.Printf("      %s\n", formatRepoStatus(status))
+		}
+	}
+
+	return nil
+}
+
+// listedSlots reads the metadata of the named slots and returns the ones matching
+// --filter in --sort order
+func (c *ListCmd) listedSlots(ctx *Context, slotsDir string, names []string) ([]listedSlot, error) {
+	glob := strings.ContainsAny(c.Filter, "*?[")
+	if glob {
+		if _, err := path.Match(c.Filter, ""); err != nil {
+			return nil, errors.InvalidUsage(fmt.Sprintf("invalid --filter pattern %q", c.Filter),
+				"Use a glob pattern such as 'feature-*', or plain text to match part of a name")
+		}
 	}
 
+	var slots []listedSlot
+	for _, name := range names {
+		if glob {
+			if matched, _ := path.Match(c.Filter, name); !matched {
+				continue
+			}
+		} else if !strings.Contains(name, c.Filter) {
+			continue
+		}
+
+		slotPath := filepath.Join(slotsDir, name)
+		listed := listedSlot{name: name}
+		if info, err := os.Stat(slotPath); err == nil {
+			listed.created, listed.modified = info.ModTime(), info.ModTime()
+		}
+		if info, err := os.Stat(filepath.Join(slotPath, slot.MetadataFileName)); err == nil && info.ModTime().After(listed.modified) {
+			listed.modified = info.ModTime()
+		}
+		meta, err := slot.LoadMetadata(slotPath)
+		if err != nil {
+			ctx.LogWarn("failed to read slot metadata", "slot", name, "error", err)
+		} else if meta != nil {
+			listed.description = meta.Description
+			listed.template = meta.Template
+			if !meta.CreatedAt.IsZero() {
+				listed.created = meta.CreatedAt
+			}
+		}
+		slots = append(slots, listed)
+	}
+
+	// Names are already sorted, so slots with the same time stay in name order
+	switch c.Sort {
+	case "created":
+		slices.SortStableFunc(slots, func(a, b listedSlot) int { return b.created.Compare(a.created) })
+	case "modified":
+		slices.SortStableFunc(slots, func(a, b listedSlot) int { return b.modified.Compare(a.modified) })
+	}
+	return slots, nil
+}
+
+// shortDescription cuts a description to its first line and at most maxListDescription characters
+func shortDescription(description string) string {
+	line, rest, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	line = strings.TrimSpace(line)
+	if runes := []rune(line); len(runes) > maxListDescription {
+		return string(runes[:maxListDescription-3]) + "..."
+	}
+	if rest != "" {
+		return line + " ..."
+	}
+	return line
+}
+
+// listArchives prints the archived slots of the project
+func (c *ListCmd) listArchives(ctx *Context, mgr *slot.Manager) error {
+	archives, err := mgr.ListArchives()
+	if err != nil {
+		return fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	if c.Porcelain {
+		for _, archive := range archives {
+			ctx.Resultln(archive.Slot + "\t" + filepath.Base(archive.Path))
+		}
+		return nil
+	}
+
+	if len(archives) == 0 {
+		ctx.Println("No archived slots found.")
+		return nil
+	}
+
+	ctx.Println("Archived slots:")
+	for _, archive := range archives {
+		ctx.Printf("  - %s (archived %s, %s)\n", archive.Slot, archive.ArchivedAt.Format("2006-01-02 15:04:05"), filepath.Base(archive.Path))
+	}
 	return nil
 }
@@ -2,11 +2,16 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/yammerjp/devslot/internal/slot"
 	"github.com/yammerjp/devslot/internal/testutil"
 )
 
@@ -84,7 +89,7 @@ func TestListCmd_Run(t *testing.T) {
 
 			var buf bytes.Buffer
 			cmd := &ListCmd{}
-			ctx := &Context{Writer: &buf, Logger: nil}
+			ctx := testContext(&buf)
 
 			err := cmd.Run(ctx)
 			if (err != nil) != tt.wantErr {
@@ -109,7 +114,7 @@ func TestListCmd_NotInProject(t *testing.T) {
 
 	var buf bytes.Buffer
 	cmd := &ListCmd{}
-	ctx := &Context{Writer: &buf, Logger: nil}
+	ctx := testContext(&buf)
 
 	err := cmd.Run(ctx)
 	if err == nil {
@@ -119,3 +124,218 @@ func TestListCmd_NotInProject(t *testing.T) {
 		t.Errorf("expected 'not in a devslot project' error, got: %v", err)
 	}
 }
+
+func TestListCmd_QuietAndPorcelain(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	defer testutil.Chdir(t, projectRoot)()
+
+	tests := []struct {
+		name      string
+		slots     []string
+		porcelain bool
+		quiet     bool
+		want      string
+	}{
+		{name: "porcelain without slots", porcelain: true, want: ""},
+		{name: "porcelain", slots: []string{"staging", "dev"}, porcelain: true, want: "dev\nstaging\n"},
+		{name: "porcelain ignores quiet", porcelain: true, quiet: true, want: "dev\nstaging\n"},
+		{name: "quiet prints names", quiet: true, want: "dev\nstaging\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range tt.slots {
+				if err := os.MkdirAll(filepath.Join(projectRoot, "slots", name), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var buf bytes.Buffer
+			ctx := testContext(&buf)
+			if tt.quiet {
+				ctx.Verbosity = VerbosityQuiet
+			}
+			if err := (&ListCmd{Porcelain: tt.porcelain}).Run(ctx); err != nil {
+				t.Fatalf("ListCmd.Run() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCmd_CaseCollisions(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	defer testutil.Chdir(t, projectRoot)()
+
+	for _, name := range []string{"Foo", "foo", "bar"} {
+		if err := os.MkdirAll(filepath.Join(projectRoot, "slots", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(projectRoot, "slots")); len(entries) < 3 {
+		t.Skip("the filesystem is case-insensitive")
+	}
+
+	var out, errOut bytes.Buffer
+	ctx := testContext(&out)
+	ctx.Err = &errOut
+	if err := (&ListCmd{Porcelain: true}).Run(ctx); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if got := out.String(); got != "Foo\nbar\nfoo\n" {
+		t.Errorf("output = %q", got)
+	}
+	if !strings.Contains(errOut.String(), "slots Foo, foo differ only in case") {
+		t.Errorf("expected a case collision warning, got %q", errOut.String())
+	}
+}
+
+func TestListCmd_Descriptions(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "plain")
+	if err := (&CreateCmd{SlotName: "ticket-123", Description: "Fix login redirect\nSee the incident notes"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&ListCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{"  - plain\n", "  - ticket-123: Fix login redirect ...\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	ctx := testContext(&buf)
+	ctx.Verbose = true
+	if err := (&ListCmd{}).Run(ctx); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "  - ticket-123\n    Fix login redirect\n    See the incident notes\n") {
+		t.Errorf("expected full description in verbose output, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&ListCmd{JSON: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	var entries []listEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	want := []listEntry{{Name: "plain"}, {Name: "ticket-123", Description: "Fix login redirect\nSee the incident notes"}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("JSON entries = %+v, want %+v", entries, want)
+	}
+}
+
+func TestListCmd_SortAndFilter(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	defer testutil.Chdir(t, projectRoot)()
+
+	// alpha is the newest slot but was changed first; gamma has no metadata and
+	// falls back to the time of its directory
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	slots := []struct {
+		name     string
+		created  time.Time
+		modified time.Time
+	}{
+		{"alpha", base.Add(3 * time.Hour), base.Add(4 * time.Hour)},
+		{"beta", base.Add(1 * time.Hour), base.Add(6 * time.Hour)},
+		{"gamma", time.Time{}, base.Add(2 * time.Hour)},
+	}
+	for _, s := range slots {
+		slotPath := filepath.Join(projectRoot, "slots", s.name)
+		if err := os.MkdirAll(slotPath, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if !s.created.IsZero() {
+			if err := slot.SaveMetadata(slotPath, &slot.Metadata{CreatedAt: s.created}); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(filepath.Join(slotPath, slot.MetadataFileName), s.modified, s.modified); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Chtimes(slotPath, s.modified, s.modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		cmd  ListCmd
+		want []string
+	}{
+		{ListCmd{}, []string{"alpha", "beta", "gamma"}},
+		{ListCmd{Sort: "name"}, []string{"alpha", "beta", "gamma"}},
+		{ListCmd{Sort: "created"}, []string{"alpha", "gamma", "beta"}},
+		{ListCmd{Sort: "modified"}, []string{"beta", "alpha", "gamma"}},
+		{ListCmd{Filter: "a"}, []string{"alpha", "beta", "gamma"}},
+		{ListCmd{Filter: "mm"}, []string{"gamma"}},
+		{ListCmd{Filter: "*a", Sort: "created"}, []string{"alpha", "gamma", "beta"}},
+		{ListCmd{Filter: "[ab]*"}, []string{"alpha", "beta"}},
+		{ListCmd{Filter: "zzz"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd.Sort+" "+tt.cmd.Filter, func(t *testing.T) {
+			// Text and JSON output agree
+			cmd := tt.cmd
+			cmd.Porcelain = true
+			var buf bytes.Buffer
+			if err := cmd.Run(testContext(&buf)); err != nil {
+				t.Fatalf("ListCmd.Run() error = %v", err)
+			}
+			if got := strings.Fields(buf.String()); !slices.Equal(got, tt.want) {
+				t.Errorf("porcelain = %v, want %v", got, tt.want)
+			}
+
+			cmd = tt.cmd
+			cmd.JSON = true
+			buf.Reset()
+			if err := cmd.Run(testContext(&buf)); err != nil {
+				t.Fatalf("ListCmd.Run() error = %v", err)
+			}
+			var entries []listEntry
+			if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("JSON = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	err := (&ListCmd{Filter: "["}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "invalid --filter pattern") {
+		t.Errorf("ListCmd.Run() error = %v, want an invalid pattern error", err)
+	}
+}
+
+func TestShortDescription(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Fix bug", "Fix bug"},
+		{"  Fix bug\n", "Fix bug"},
+		{"Fix bug\nDetails", "Fix bug ..."},
+		{strings.Repeat("x", 80), strings.Repeat("x", 57) + "..."},
+	}
+	for _, tt := range tests {
+		if got := shortDescription(tt.in); got != tt.want {
+			t.Errorf("shortDescription(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
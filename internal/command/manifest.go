@@ -0,0 +1,58 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// initManifestFileName is the manifest written to repos/ by 'devslot init'
+const initManifestFileName = ".devslot-init.json"
+
+// initManifest records which devslot version and command last initialized repos/
+type initManifest struct {
+	InitializedAt  time.Time `json:"initialized_at"`
+	DevslotVersion string    `json:"devslot_version"`
+	Args           []string  `json:"args"`
+	Repositories   []string  `json:"repositories"`
+}
+
+// provenance describes the invocation that wrote the manifest
+func (m *initManifest) provenance() string {
+	if m == nil || m.DevslotVersion == "" {
+		return ""
+	}
+	return fmt.Sprintf("repositories initialized by devslot %s with 'devslot %s'", m.DevslotVersion, strings.Join(m.Args, " "))
+}
+
+// loadInitManifest reads the init manifest, returning nil if it does not exist
+func loadInitManifest(reposDir string) (*initManifest, error) {
+	data, err := os.ReadFile(filepath.Join(reposDir, initManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read init manifest: %w", err)
+	}
+
+	var manifest initManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse init manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// saveInitManifest writes the init manifest
+func saveInitManifest(reposDir string, manifest *initManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode init manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(reposDir, initManifestFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write init manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+type MigrateConfigCmd struct {
+	Output string `short:"o" default:"devslot.v2.yaml" help:"File to write the migrated configuration to, relative to the project root"`
+	Force  bool   `help:"Overwrite the output file if it exists"`
+}
+
+func (c *MigrateConfigCmd) Help() string {
+	return `Converts a version 1 devslot.yaml to version 2.
+
+Version 2 moves default_host and branch_prefix under defaults: (as host and
+branch_prefix), and repos_dir and slots_dir under directories: (as repos and
+slots). Everything else is unchanged.
+
+devslot.yaml itself is left alone: the migrated file is written to --output
+(devslot.v2.yaml by default) and the difference is printed. Comments are kept
+where possible; review the file, then move it over devslot.yaml.
+
+Files included with extends are not migrated; each file may use either version.`
+}
+
+func (c *MigrateConfigCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	configPath := ctx.ConfigFile(projectRoot)
+	configName := filepath.Base(configPath)
+	outputPath := c.Output
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(projectRoot, outputPath)
+	}
+	if filepath.Clean(outputPath) == configPath {
+		return errors.InvalidUsage(fmt.Sprintf("--output must not be %s", configName),
+			"Write the migrated configuration to another file and move it over the original after reviewing it")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", configName, err)
+	}
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Version == config.LatestVersion {
+		ctx.Printf("%s already uses version %d\n", configName, config.LatestVersion)
+		return nil
+	}
+
+	migrated, err := config.Migrate(data)
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", configName, err)
+	}
+
+	if _, err := os.Stat(outputPath); err == nil && !c.Force {
+		return errors.WithSuggestion(fmt.Errorf("%s already exists", outputPath),
+			"refusing to overwrite the output file",
+			"Pass --force to overwrite it, or choose another file with --output")
+	}
+	if err := os.WriteFile(outputPath, migrated, 0644); err != nil {
+		return fmt.Errorf("failed to write migrated configuration: %w", err)
+	}
+	ctx.LogInfo("configuration migrated", "output", outputPath)
+
+	output := outputPath
+	if rel, err := filepath.Rel(projectRoot, outputPath); err == nil {
+		output = rel
+	}
+	diff, err := git.DiffFiles(projectRoot, configName, output)
+	if err != nil {
+		ctx.LogWarn("failed to diff configuration", "error", err)
+	} else {
+		ctx.Resultln(strings.TrimRight(diff, "\n"))
+	}
+
+	ctx.Success("Wrote version %d configuration to %s", config.LatestVersion, output)
+	ctx.Printf("Review it, then replace %s: mv %s %s\n", configName, output, configName)
+	return nil
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestMigrateConfigCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	original := `version: 1
+# shared naming
+branch_prefix: "team/{slot}"
+repositories:
+  - example/app
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), original)
+
+	var buf bytes.Buffer
+	if err := (&DoctorCmd{}).Run(testContext(&buf)); !strings.Contains(buf.String(), "run 'devslot migrate-config'") {
+		t.Errorf("expected doctor to suggest migration (err = %v), got:\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	cmd := &MigrateConfigCmd{Output: "devslot.v2.yaml"}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("MigrateConfigCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{"-branch_prefix: \"team/{slot}\"", "+defaults:", "+  branch_prefix: ", "Wrote version 2 configuration to devslot.v2.yaml"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if got := testutil.ReadFile(t, filepath.Join(projectRoot, "devslot.yaml")); got != original {
+		t.Errorf("devslot.yaml was modified:\n%s", got)
+	}
+	migrated := testutil.ReadFile(t, filepath.Join(projectRoot, "devslot.v2.yaml"))
+	if !strings.Contains(migrated, "version: 2\n# shared naming\ndefaults:") && !strings.Contains(migrated, "defaults:\n  # shared naming\n") {
+		t.Errorf("expected the comment to be kept, got:\n%s", migrated)
+	}
+
+	// The output file is not overwritten without --force
+	if err := cmd.Run(testContext(&bytes.Buffer{})); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error for an existing output file, got %v", err)
+	}
+	cmd.Force = true
+	if err := cmd.Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Errorf("MigrateConfigCmd.Run() with --force error = %v", err)
+	}
+
+	// devslot.yaml itself is never the output
+	if err := (&MigrateConfigCmd{Output: "devslot.yaml"}).Run(testContext(&bytes.Buffer{})); err == nil {
+		t.Error("expected --output devslot.yaml to be rejected")
+	}
+
+	// Nothing to do once migrated
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), migrated)
+	buf.Reset()
+	if err := (&MigrateConfigCmd{Output: "other.yaml"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("MigrateConfigCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "already uses version 2") {
+		t.Errorf("expected already migrated message, got:\n%s", buf.String())
+	}
+	if testutil.FileExists(t, filepath.Join(projectRoot, "other.yaml")) {
+		t.Error("no file should be written for a version 2 configuration")
+	}
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestNamespacedRepositories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	yamlContent := `version: 1
+repositories:
+  - name: platform/api
+    url: https://github.com/platform/api.git
+  - name: billing/api
+    url: https://github.com/billing/api.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+	for _, name := range []string{"platform/api", "billing/api"} {
+		testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", name+".git"))
+	}
+
+	// Worktrees are nested like the bare repositories
+	if err := (&CreateCmd{SlotName: "nested"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	for _, name := range []string{"platform/api", "billing/api"} {
+		if !testutil.FileExists(t, filepath.Join(projectRoot, "slots", "nested", name, "README.md")) {
+			t.Errorf("expected worktree for %s", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (&StatusCmd{SlotName: "nested"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("StatusCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "platform/api:") || !strings.Contains(buf.String(), "billing/api:") {
+		t.Errorf("expected namespaced repositories in status, got:\n%s", buf.String())
+	}
+
+	// Destroy removes the nested worktrees from both bare repositories
+	if err := (&DestroyCmd{Slots: []string{"nested"}}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	for _, name := range []string{"platform/api", "billing/api"} {
+		output, err := exec.Command("git", "-C", filepath.Join(projectRoot, "repos", name+".git"), "worktree", "list", "--porcelain").Output()
+		if err != nil {
+			t.Fatalf("git worktree list failed: %v", err)
+		}
+		if strings.Contains(string(output), "slots") {
+			t.Errorf("worktree of %s still registered after destroy:\n%s", name, output)
+		}
+	}
+
+	// --allow-delete removes unlisted namespaced repositories and their empty namespace
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: platform/api
+    url: https://github.com/platform/api.git
+`)
+	if err := (&InitCmd{AllowDelete: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "repos", "platform", "api.git")) {
+		t.Error("listed namespaced repository must be kept")
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "repos", "billing")) {
+		t.Error("expected unlisted namespaced repository and its namespace to be removed")
+	}
+}
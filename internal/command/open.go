@@ -0,0 +1,122 @@
+package command
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type OpenCmd struct {
+	SlotName string `arg:"" help:"Name of the slot"`
+	Repo     string `arg:"" optional:"" help:"Name of a repository in the slot"`
+}
+
+func (c *OpenCmd) Help() string {
+	return `Opens a slot, or a repository's worktree in a slot, in your editor or shell.
+
+The command is taken from (in order of precedence):
+  1. DEVSLOT_EDITOR environment variable
+  2. open_command in devslot.yaml (e.g. "code -n {path}")
+  3. EDITOR environment variable
+
+{path} is replaced by the directory to open; without it, the directory is
+passed as the last argument. When none of them is set, the directory is
+printed instead.
+
+devslot is replaced by the command where the platform allows it; otherwise
+the command is run and devslot exits with its exit code.`
+}
+
+// openExec runs an open command; it is replaced in tests
+var openExec = execReplace
+
+func (c *OpenCmd) Run(ctx *Context) error {
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Valid slot names have no path separators, so the path stays inside the slots directory
+	if err := slot.ValidateName(c.SlotName); err != nil {
+		return err
+	}
+	target := filepath.Join(cfg.SlotsDir(projectRoot), c.SlotName)
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		return errors.SlotNotFound(c.SlotName)
+	}
+	if c.Repo != "" {
+		target = filepath.Join(target, c.Repo)
+		if info, err := os.Stat(target); err != nil || !info.IsDir() {
+			return errors.WorktreeNotFound(c.SlotName, c.Repo)
+		}
+	}
+
+	template := openCommand(cfg)
+	if template == "" {
+		ctx.LogInfo("no open command configured, printing the path")
+		ctx.Resultln(target)
+		return nil
+	}
+
+	args := renderOpenCommand(template, target)
+	ctx.LogInfo("opening", "path", target, "command", args)
+	return openExec(ctx, args, target)
+}
+
+// openCommand returns the command template used to open a directory
+func openCommand(cfg *config.Config) string {
+	if cmd := strings.TrimSpace(os.Getenv("DEVSLOT_EDITOR")); cmd != "" {
+		return cmd
+	}
+	if cmd := strings.TrimSpace(cfg.OpenCommand); cmd != "" {
+		return cmd
+	}
+	return strings.TrimSpace(os.Getenv("EDITOR"))
+}
+
+// renderOpenCommand splits a command template into arguments and replaces {path}
+// with path, which is appended as the last argument when the template doesn't use it
+func renderOpenCommand(template, path string) []string {
+	args := strings.Fields(template)
+	substituted := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{path}") {
+			args[i] = strings.ReplaceAll(arg, "{path}", path)
+			substituted = true
+		}
+	}
+	if !substituted {
+		args = append(args, path)
+	}
+	return args
+}
+
+// runOpenCommand runs the open command in dir, attached to the terminal, and
+// returns its exit status as an error when it fails
+func runOpenCommand(ctx *Context, args []string, dir string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = ctx.In
+	cmd.Stdout = ctx.Out
+	cmd.Stderr = ctx.Err
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if stderrors.As(err, &exitErr) {
+			return fmt.Errorf("%s failed: %w", args[0], errors.ExitStatus(exitErr.ExitCode()))
+		}
+		return fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+	return nil
+}
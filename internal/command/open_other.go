@@ -0,0 +1,9 @@
+//go:build !unix
+
+package command
+
+// execReplace runs the open command in dir, as the process can't be replaced
+// on this platform; devslot exits with the command's exit code
+func execReplace(ctx *Context, args []string, dir string) error {
+	return runOpenCommand(ctx, args, dir)
+}
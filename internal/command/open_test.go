@@ -0,0 +1,126 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestOpenCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature-x")
+	slotPath := filepath.Join(projectRoot, "slots", "feature-x")
+
+	var gotArgs []string
+	var gotDir string
+	defer func(orig func(*Context, []string, string) error) { openExec = orig }(openExec)
+	openExec = func(ctx *Context, args []string, dir string) error {
+		gotArgs, gotDir = args, dir
+		return nil
+	}
+
+	tests := []struct {
+		name          string
+		devslotEditor string
+		editor        string
+		openCommand   string
+		repo          string
+		wantArgs      []string
+		wantPrinted   string
+	}{
+		{name: "nothing configured prints the path", wantPrinted: slotPath + "\n"},
+		{name: "EDITOR", editor: "vim", wantArgs: []string{"vim", slotPath}},
+		{name: "open_command beats EDITOR", editor: "vim", openCommand: "code -n {path}", wantArgs: []string{"code", "-n", slotPath}},
+		{name: "DEVSLOT_EDITOR beats open_command", devslotEditor: "idea", openCommand: "code -n {path}", wantArgs: []string{"idea", slotPath}},
+		{name: "repository", editor: "vim", repo: "repo1", wantArgs: []string{"vim", filepath.Join(slotPath, "repo1")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEVSLOT_EDITOR", tt.devslotEditor)
+			t.Setenv("EDITOR", tt.editor)
+			yaml := "version: 1\nrepositories:\n  - name: repo1\n    url: https://github.com/example/repo1.git\n"
+			if tt.openCommand != "" {
+				yaml += "open_command: " + tt.openCommand + "\n"
+			}
+			testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yaml)
+			gotArgs, gotDir = nil, ""
+
+			var buf bytes.Buffer
+			if err := (&OpenCmd{SlotName: "feature-x", Repo: tt.repo}).Run(testContext(&buf)); err != nil {
+				t.Fatalf("OpenCmd.Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("command = %q, want %q", gotArgs, tt.wantArgs)
+			}
+			if tt.wantArgs != nil && gotDir != tt.wantArgs[len(tt.wantArgs)-1] {
+				t.Errorf("dir = %q", gotDir)
+			}
+			if buf.String() != tt.wantPrinted {
+				t.Errorf("output = %q, want %q", buf.String(), tt.wantPrinted)
+			}
+		})
+	}
+}
+
+func TestOpenCmd_NotFound(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature-x")
+	t.Setenv("EDITOR", "false")
+
+	err := (&OpenCmd{SlotName: "missing"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "slot missing does not exist") {
+		t.Errorf("expected SlotNotFound, got %v", err)
+	}
+	err = (&OpenCmd{SlotName: "feature-x", Repo: "nope"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "repository nope not found in slot feature-x") {
+		t.Errorf("expected WorktreeNotFound, got %v", err)
+	}
+	for _, name := range []string{"..", "../repos", "../../.."} {
+		err = (&OpenCmd{SlotName: name}).Run(testContext(&bytes.Buffer{}))
+		if err == nil || !strings.Contains(err.Error(), "invalid slot name") {
+			t.Errorf("open %s: expected an invalid slot name error, got %v", name, err)
+		}
+	}
+}
+
+func TestRunOpenCommand_ExitCode(t *testing.T) {
+	dir := testutil.TempDir(t)
+	var buf bytes.Buffer
+
+	if err := runOpenCommand(testContext(&buf), []string{"sh", "-c", "pwd", "--"}, dir); err != nil {
+		t.Fatalf("runOpenCommand() error = %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != dir {
+		t.Errorf("command ran in %q, want %q", got, dir)
+	}
+
+	err := runOpenCommand(testContext(&buf), []string{"sh", "-c", "exit 3"}, dir)
+	if got := errors.ExitCode(err); got != 3 {
+		t.Errorf("ExitCode() = %d, want 3 (err = %v)", got, err)
+	}
+}
+
+func TestRenderOpenCommand(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"vim", []string{"vim", "/p q"}},
+		{"code -n {path}", []string{"code", "-n", "/p q"}},
+		{"tmux new -c {path} -s x", []string{"tmux", "new", "-c", "/p q", "-s", "x"}},
+		{"open --dir={path}", []string{"open", "--dir=/p q"}},
+	}
+	for _, tt := range tests {
+		if got := renderOpenCommand(tt.template, "/p q"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("renderOpenCommand(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestOptionalRepositories(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	if err := os.MkdirAll(filepath.Join(projectRoot, "hooks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	source := filepath.Join(testutil.TempDir(t), "app.git")
+	testutil.InitBareRepo(t, source)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: `+source+`
+  - name: secret
+    url: `+filepath.Join(testutil.TempDir(t), "no-access.git")+`
+    optional: true
+`)
+
+	// A failed clone of an optional repository is only a warning
+	var buf bytes.Buffer
+	if err := (&InitCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"Could not clone optional repository secret", "1 cloned, 0 skipped, 1 failed"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in init output, got:\n%s", want, buf.String())
+		}
+	}
+
+	// create and reload skip it
+	buf.Reset()
+	if err := (&CreateCmd{SlotName: "dev"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Skipping optional repository secret (not cloned)") {
+		t.Errorf("expected skip message, got:\n%s", buf.String())
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "dev", "app")) {
+		t.Error("required repository should have a worktree")
+	}
+	if err := (&ReloadCmd{SlotName: "dev"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+
+	buf.Reset()
+	if err := (&StatusCmd{SlotName: "dev"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("StatusCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "secret: (optional, not present)") {
+		t.Errorf("expected optional repository in status, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&DoctorCmd{}).Run(testContext(&buf)); err != nil {
+		t.Errorf("doctor should pass with an optional repository missing: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Repository secret is optional, not cloned") {
+		t.Errorf("expected optional repository in doctor output, got:\n%s", buf.String())
+	}
+}
+
+func TestCreateCmd_RequiredRepositoryMissing(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: https://github.com/example/app.git
+`)
+
+	err := (&CreateCmd{SlotName: "dev"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "bare repository app does not exist") {
+		t.Errorf("expected required repository to fail create, got %v", err)
+	}
+}
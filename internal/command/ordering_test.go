@@ -0,0 +1,121 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// runRepeatedly runs fn several times and fails unless every run produced the same output
+func runRepeatedly(t *testing.T, fn func() string) string {
+	t.Helper()
+	first := fn()
+	for i := 0; i < 5; i++ {
+		if got := fn(); got != first {
+			t.Fatalf("output changed between runs:\n--- first ---\n%s\n--- run %d ---\n%s", first, i+2, got)
+		}
+	}
+	return first
+}
+
+func TestListCmd_SortedOutput(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+	for _, name := range []string{"staging", "dev", "feature-x", "Zeta"} {
+		if err := os.MkdirAll(filepath.Join(projectRoot, "slots", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := runRepeatedly(t, func() string {
+		var buf bytes.Buffer
+		if err := (&ListCmd{}).Run(testContext(&buf)); err != nil {
+			t.Fatalf("ListCmd.Run() error = %v", err)
+		}
+		return buf.String()
+	})
+
+	assertInOrder(t, output, []string{"- Zeta", "- dev", "- feature-x", "- staging"})
+}
+
+func TestDoctorCmd_StableOutput(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateProjectStructure(t, projectRoot)
+
+	yamlContent := `version: 1
+repositories:
+  - name: zeta
+    url: https://example.com/zeta.git
+  - name: alpha
+    url: https://example.com/alpha.git
+hooks:
+  pre-merge: ["true"]
+  post-build: ["true"]
+  after-all: ["true"]
+  post-create: ["true"]
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+	for _, name := range []string{"b-slot", "a-slot"} {
+		if err := os.MkdirAll(filepath.Join(projectRoot, "slots", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := runRepeatedly(t, func() string {
+		var buf bytes.Buffer
+		_ = (&DoctorCmd{}).Run(testContext(&buf))
+		return buf.String()
+	})
+
+	assertInOrder(t, output, []string{
+		"Checking configuration",
+		"Repository zeta",
+		"Repository alpha",
+		"Slot a-slot",
+		"Slot b-slot",
+		"Inline hook after-all",
+		"Inline hook post-build",
+		"Inline hook pre-merge",
+	})
+}
+
+func TestBoilerplateCmd_StableOutput(t *testing.T) {
+	baseDir := testutil.TempDir(t)
+
+	run := 0
+	output := runRepeatedly(t, func() string {
+		run++
+		var buf bytes.Buffer
+		cmd := &BoilerplateCmd{Dir: filepath.Join(baseDir, "project", string(rune('a'+run)))}
+		if err := cmd.Run(testContext(&buf)); err != nil {
+			t.Fatalf("BoilerplateCmd.Run() error = %v", err)
+		}
+		return buf.String()
+	})
+
+	expected := make([]string, len(hook.Types))
+	for i, hookType := range hook.Types {
+		expected[i] = "Created hook script: hooks/" + string(hookType) + "\n"
+	}
+	assertInOrder(t, output, expected)
+}
+
+// assertInOrder checks that each expected string appears in output after the previous one
+func assertInOrder(t *testing.T, output string, expected []string) {
+	t.Helper()
+	pos := 0
+	for _, want := range expected {
+		idx := strings.Index(output[pos:], want)
+		if idx < 0 {
+			t.Fatalf("expected %q after position %d in output:\n%s", want, pos, output)
+		}
+		pos += idx + len(want)
+	}
+}
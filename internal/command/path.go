@@ -0,0 +1,65 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type PathCmd struct {
+	SlotName string `arg:"" help:"Name of the slot"`
+	Repo     string `arg:"" optional:"" help:"Name of a repository in the slot"`
+}
+
+func (c *PathCmd) Help() string {
+	return `Prints the absolute path of a slot, or of a repository's worktree in a slot.
+
+Only the path is printed, so it is safe to use as cd "$(devslot path <slot>)".
+Exits with an error when the slot or worktree does not exist, or when the
+repository is not in devslot.yaml.`
+}
+
+func (c *PathCmd) Run(ctx *Context) error {
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Valid slot names have no path separators, so the path stays inside the slots directory
+	if err := slot.ValidateName(c.SlotName); err != nil {
+		return err
+	}
+	slotPath := filepath.Join(cfg.SlotsDir(projectRoot), c.SlotName)
+	if info, err := os.Stat(slotPath); err != nil || !info.IsDir() {
+		return errors.SlotNotFound(c.SlotName)
+	}
+
+	if c.Repo == "" {
+		ctx.Resultln(slotPath)
+		return nil
+	}
+
+	var known []string
+	for _, repo := range cfg.Repositories {
+		known = append(known, repo.Name)
+	}
+	if !slices.Contains(known, c.Repo) {
+		return errors.UnknownRepository(c.Repo, known)
+	}
+	worktreePath := filepath.Join(slotPath, c.Repo)
+	if info, err := os.Stat(worktreePath); err != nil || !info.IsDir() {
+		return errors.WorktreeNotFound(c.SlotName, c.Repo)
+	}
+
+	ctx.Resultln(worktreePath)
+	return nil
+}
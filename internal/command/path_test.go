@@ -0,0 +1,124 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestRootCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature")
+	if err := os.MkdirAll(filepath.Join(projectRoot, "slots", "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from inside a worktree to make sure the root is found upward
+	defer testutil.Chdir(t, filepath.Join(root, "slots", "feature", "repo1"))()
+
+	var buf bytes.Buffer
+	cmd := &RootCmd{}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := buf.String(); got != root+"\n" {
+		t.Errorf("expected %q, got %q", root+"\n", got)
+	}
+}
+
+func TestPathCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature")
+	if err := os.MkdirAll(filepath.Join(projectRoot, "slots", "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cmd     PathCmd
+		want    string
+		wantErr string
+	}{
+		{
+			name: "slot",
+			cmd:  PathCmd{SlotName: "feature"},
+			want: filepath.Join(root, "slots", "feature") + "\n",
+		},
+		{
+			name: "repository",
+			cmd:  PathCmd{SlotName: "feature", Repo: "repo1"},
+			want: filepath.Join(root, "slots", "feature", "repo1") + "\n",
+		},
+		{
+			name:    "missing slot",
+			cmd:     PathCmd{SlotName: "missing"},
+			wantErr: "slot missing does not exist",
+		},
+		{
+			name:    "missing worktree",
+			cmd:     PathCmd{SlotName: "empty", Repo: "repo1"},
+			wantErr: "repository repo1 not found in slot empty",
+		},
+		{
+			name:    "repository not in devslot.yaml",
+			cmd:     PathCmd{SlotName: "feature", Repo: "repo2"},
+			wantErr: "unknown repository repo2",
+		},
+		{
+			name:    "slot name leaving the slots directory",
+			cmd:     PathCmd{SlotName: "../repos"},
+			wantErr: "invalid slot name",
+		},
+		{
+			name:    "parent directories",
+			cmd:     PathCmd{SlotName: "../../.."},
+			wantErr: "invalid slot name",
+		},
+		{
+			name:    "repository leaving the slot",
+			cmd:     PathCmd{SlotName: "feature", Repo: "../../repos"},
+			wantErr: "unknown repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.cmd.Run(testContext(&buf))
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if buf.Len() != 0 {
+					t.Errorf("expected no output, got %q", buf.String())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
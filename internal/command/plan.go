@@ -0,0 +1,82 @@
+package command
+
+import (
+	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+// dryRunNotice ends the output of every --dry-run
+const dryRunNotice = "Dry run: nothing was changed."
+
+// printCreatePlan prints what creating a slot would do
+func printCreatePlan(ctx *Context, plan *slot.CreatePlan) {
+	ctx.Printf("Would create slot '%s' in %s\n", plan.Name, plan.Path)
+	if plan.BranchPrefix != "" {
+		ctx.Printf("Branch: %s (from branch prefix %q)\n", plan.Branch, plan.BranchPrefix)
+	} else {
+		ctx.Printf("Branch: %s\n", plan.Branch)
+	}
+	for _, worktree := range plan.Worktrees {
+		switch {
+		case worktree.NewBranch && worktree.Base != "":
+			ctx.Printf("  Would create worktree %s on new branch %s from %s\n", worktree.Repo, worktree.Branch, worktree.Base)
+		case worktree.NewBranch:
+			ctx.Printf("  Would create worktree %s on new branch %s\n", worktree.Repo, worktree.Branch)
+		default:
+			ctx.Printf("  Would create worktree %s on existing branch %s\n", worktree.Repo, worktree.Branch)
+		}
+	}
+	printSkipped(ctx, plan.Skipped)
+	printHooks(ctx, plan.Hooks)
+}
+
+// printDestroyPlan prints what destroying a slot would remove
+func printDestroyPlan(ctx *Context, plan *slot.DestroyPlan) {
+	ctx.Printf("Would destroy slot '%s' and remove %s\n", plan.Name, plan.Path)
+	for _, repoName := range plan.Worktrees {
+		ctx.Printf("  Would remove worktree %s\n", repoName)
+	}
+	for _, dir := range plan.Foreign {
+		ctx.Printf("  Would remove worktree %s of a repository not in devslot.yaml\n", dir)
+	}
+	for _, dir := range plan.Unknown {
+		ctx.Printf("  Would remove directory %s (not a worktree)\n", dir)
+	}
+	printHooks(ctx, plan.Hooks)
+}
+
+// printReloadPlan prints the worktrees a reload would create and remove
+func printReloadPlan(ctx *Context, plan *slot.ReloadPlan) {
+	if len(plan.Missing) == 0 && len(plan.Repair) == 0 && len(plan.Prune) == 0 {
+		ctx.Printf("Slot '%s' has no missing worktrees\n", plan.Name)
+	}
+	for _, worktree := range plan.Missing {
+		ctx.Printf("  Would create worktree %s on branch %s\n", worktree.Repo, worktree.Branch)
+	}
+	for _, worktree := range plan.Repair {
+		ctx.Printf("  Would recreate corrupted worktree %s on branch %s\n", worktree.Repo, worktree.Branch)
+	}
+	for _, repoName := range plan.Prune {
+		ctx.Printf("  Would remove worktree %s (no longer in devslot.yaml)\n", repoName)
+	}
+	printSkipped(ctx, plan.Skipped)
+	printHooks(ctx, plan.Hooks)
+}
+
+// printSkipped prints the optional repositories an operation skips because they are not cloned
+func printSkipped(ctx *Context, skipped []string) {
+	for _, repoName := range skipped {
+		ctx.Printf("  Would skip optional repository %s (not cloned)\n", repoName)
+	}
+}
+
+// printHooks prints the hooks an operation would run; hooks never run in a dry run
+func printHooks(ctx *Context, hooks []hook.Type) {
+	if len(hooks) == 0 {
+		ctx.Println("  No hooks would run")
+		return
+	}
+	for _, hookType := range hooks {
+		ctx.Printf("  Would run %s hook\n", hookType)
+	}
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressMu serializes the lines that concurrent git commands write through
+// prefixWriters, so that their output never interleaves mid-line
+var progressMu sync.Mutex
+
+// prefixWriter writes every line it receives to w with a prefix, such as the
+// name of the repository being cloned. Lines end at '\n' or at '\r', which git
+// uses to redraw progress lines, and are written whole.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+// newPrefixWriter returns a writer prefixing each line written to w
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{w: w, prefix: prefix}
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	for {
+		end := bytes.IndexAny(p.buf, "\r\n")
+		if end < 0 {
+			return len(data), nil
+		}
+		if err := p.writeLine(p.buf[:end+1]); err != nil {
+			return len(data), err
+		}
+		p.buf = p.buf[end+1:]
+	}
+}
+
+// Flush writes a final line that has no line ending
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		_ = p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+// writeLine writes one line including its line ending; a bare line ending gets no prefix
+func (p *prefixWriter) writeLine(line []byte) error {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	if len(line) > 1 {
+		if _, err := io.WriteString(p.w, p.prefix); err != nil {
+			return err
+		}
+	}
+	_, err := p.w.Write(line)
+	return err
+}
+
+// cloneHeartbeatInterval is how often a clone that is still running is reported
+// when stderr is not a terminal and git's progress output would be unreadable
+var cloneHeartbeatInterval = 30 * time.Second
+
+// startHeartbeat reports every interval that a repository is still being cloned,
+// until the returned function is called
+func startHeartbeat(ctx *Context, name string, interval time.Duration) (stop func()) {
+	started := time.Now()
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				progressMu.Lock()
+				ctx.Printf("Still cloning %s (%s elapsed)\n", name, now.Sub(started).Round(time.Second))
+				progressMu.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		wg.Wait()
+	}
+}
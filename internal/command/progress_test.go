@@ -0,0 +1,73 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// terminalBuffer is an output stream that claims to be a terminal
+type terminalBuffer struct {
+	*bytes.Buffer
+}
+
+func (terminalBuffer) IsTerminal() bool { return true }
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newPrefixWriter(&buf, "api: ")
+	for _, chunk := range []string{"Cloning into ", "bare repository 'api.git'...\n", "Receiving objects:  50%\r", "Receiving objects: 100%, done.\r\n", "warning: no newline"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Flush()
+
+	want := "api: Cloning into bare repository 'api.git'...\n" +
+		"api: Receiving objects:  50%\r" +
+		"api: Receiving objects: 100%, done.\r\n" +
+		"api: warning: no newline\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestStartHeartbeat(t *testing.T) {
+	var buf bytes.Buffer
+	stop := startHeartbeat(testContext(&buf), "api", 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "Still cloning api (") || !strings.Contains(output, " elapsed)\n") {
+		t.Errorf("expected heartbeat lines, got %q", output)
+	}
+
+	// Nothing is printed once stopped
+	stopped := buf.Len()
+	time.Sleep(30 * time.Millisecond)
+	if buf.Len() != stopped {
+		t.Errorf("heartbeat continued after stop: %q", buf.String()[stopped:])
+	}
+}
+
+func TestInitCmd_Progress(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	sourceRepo := filepath.Join(testutil.TempDir(t), "source.git")
+	testutil.InitBareRepo(t, sourceRepo)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 2\nrepositories:\n  - name: app\n    url: "+sourceRepo+"\n")
+
+	var buf bytes.Buffer
+	ctx := &Context{Out: &buf, Err: terminalBuffer{&buf}}
+	if err := (&InitCmd{}).Run(ctx); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "app: Cloning into bare repository") {
+		t.Errorf("expected git output prefixed with the repository name, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type PullCmd struct {
+	SlotName string `arg:"" help:"Name of the slot"`
+	Rebase   bool   `help:"Rebase onto the upstream instead of fast-forwarding"`
+	Parallel int    `default:"4" help:"Number of repositories to fetch at once"`
+}
+
+func (c *PullCmd) Help() string {
+	return `Updates every worktree of a slot from the upstream of its branch.
+
+The bare repositories are fetched first (--parallel at a time). Each worktree
+is then fast-forwarded to its upstream; with --rebase, local commits are
+rebased onto the upstream instead, and a rebase that hits conflicts is aborted.
+
+Branches without an upstream, such as the new branches of a fresh slot, are
+skipped without failing the command. Worktrees with uncommitted changes are
+skipped too, but the command fails if any of those or other repositories could
+not be updated.`
+}
+
+func (c *PullCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+	if err := slot.ValidateName(c.SlotName); err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	ctx.Printf("Pulling slot '%s'...\n", c.SlotName)
+	ctx.LogInfo("pulling slot", "slot", c.SlotName, "rebase", c.Rebase, "parallel", c.Parallel)
+
+	results, err := mgr.Pull(c.SlotName, cfg, &slot.PullOptions{Rebase: c.Rebase, Parallel: c.Parallel})
+	if err != nil {
+		return err
+	}
+
+	updated, upToDate, noUpstream := 0, 0, 0
+	for _, result := range results {
+		switch result.State {
+		case slot.PullUpdated:
+			ctx.Success("  %s: updated %s..%s from %s", result.Name, shortCommit(result.From), shortCommit(result.To), result.Upstream)
+			updated++
+		case slot.PullUpToDate:
+			ctx.Info("  %s: up to date with %s", result.Name, result.Upstream)
+			upToDate++
+		case slot.PullMissing:
+			ctx.Warn("  %s: skipped, worktree is missing (run 'devslot reload %s')", result.Name, c.SlotName)
+		case slot.PullNoUpstream:
+			// Nothing to pull from, e.g. a branch of a new slot that was never pushed
+			ctx.Info("  %s: skipped, branch %s has no upstream", result.Name, result.Branch)
+			noUpstream++
+		case slot.PullDirty:
+			ctx.Warn("  %s: skipped, uncommitted changes", result.Name)
+		case slot.PullFailed:
+			ctx.Failure("  %s: %v", result.Name, result.Err)
+			ctx.LogWarn("failed to pull repository", "name", result.Name, "error", result.Err)
+		}
+	}
+
+	notUpdated := len(results) - updated - upToDate - noUpstream
+	summary := fmt.Sprintf("Updated %d, up to date %d, not updated %d", updated, upToDate, notUpdated)
+	if noUpstream > 0 {
+		summary += fmt.Sprintf(", no upstream %d", noUpstream)
+	}
+	ctx.Printf("\n%s\n", summary)
+	ctx.LogInfo("pull completed", "slot", c.SlotName, "updated", updated, "no_upstream", noUpstream, "not_updated", notUpdated)
+
+	if notUpdated > 0 {
+		return errors.PullIncomplete(notUpdated, len(results))
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// pushCommit commits a file to the main branch of origin
+func pushCommit(t *testing.T, origin, file string) {
+	t.Helper()
+	work := filepath.Join(testutil.TempDir(t), "work")
+	gitOutput(t, filepath.Dir(work), "clone", "-q", origin, work)
+	gitOutput(t, work, "config", "user.email", "test@example.com")
+	gitOutput(t, work, "config", "user.name", "Test User")
+	testutil.CreateFile(t, filepath.Join(work, file), file)
+	gitOutput(t, work, "add", file)
+	gitOutput(t, work, "commit", "-q", "-m", "Add "+file)
+	gitOutput(t, work, "push", "-q", "origin", "HEAD:main")
+}
+
+func TestPullCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	origins := testutil.TempDir(t)
+	yamlContent := "version: 1\nrepositories:\n"
+	for _, name := range []string{"app", "lib"} {
+		testutil.InitBareRepo(t, filepath.Join(origins, name+".git"))
+		yamlContent += "  - name: " + name + "\n    url: " + filepath.Join(origins, name+".git") + "\n"
+	}
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if err := (&CreateCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	appWorktree := filepath.Join(projectRoot, "slots", "work", "app")
+	libWorktree := filepath.Join(projectRoot, "slots", "work", "lib")
+	for _, worktree := range []string{appWorktree, libWorktree} {
+		gitOutput(t, worktree, "branch", "--set-upstream-to=origin/main")
+	}
+
+	// Nothing new upstream
+	var buf bytes.Buffer
+	if err := (&PullCmd{SlotName: "work", Parallel: 2}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("PullCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "app: up to date with origin/main") {
+		t.Errorf("expected app to be up to date, got:\n%s", buf.String())
+	}
+
+	// New commits upstream are fast-forwarded; dirty worktrees are skipped
+	pushCommit(t, filepath.Join(origins, "app.git"), "new.txt")
+	pushCommit(t, filepath.Join(origins, "lib.git"), "new.txt")
+	testutil.CreateFile(t, filepath.Join(libWorktree, "wip.txt"), "work in progress")
+
+	buf.Reset()
+	err := (&PullCmd{SlotName: "work", Parallel: 2}).Run(testContext(&buf))
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 repositories not updated") {
+		t.Fatalf("expected pull to report lib as not updated, got %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"app: updated", "from origin/main", "lib: skipped, uncommitted changes", "Updated 1, up to date 0, not updated 1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if !testutil.FileExists(t, filepath.Join(appWorktree, "new.txt")) {
+		t.Error("expected app to contain the pulled commit")
+	}
+	if testutil.FileExists(t, filepath.Join(libWorktree, "new.txt")) {
+		t.Error("dirty lib worktree should not have been updated")
+	}
+
+	// --rebase keeps local commits on top of the upstream
+	testutil.CreateFile(t, filepath.Join(appWorktree, "local.txt"), "local")
+	gitOutput(t, appWorktree, "add", "local.txt")
+	gitOutput(t, appWorktree, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-q", "-m", "Local commit")
+	pushCommit(t, filepath.Join(origins, "app.git"), "newer.txt")
+	gitOutput(t, libWorktree, "branch", "--unset-upstream")
+
+	buf.Reset()
+	if err := (&PullCmd{SlotName: "work", Rebase: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("PullCmd.Run() error = %v, a branch without upstream is only skipped\n%s", err, buf.String())
+	}
+	for _, want := range []string{"lib: skipped, branch test/work has no upstream", "Updated 1, up to date 0, not updated 0, no upstream 1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q, got:\n%s", want, buf.String())
+		}
+	}
+	if !testutil.FileExists(t, filepath.Join(appWorktree, "newer.txt")) || !testutil.FileExists(t, filepath.Join(appWorktree, "local.txt")) {
+		t.Error("expected app to be rebased onto the upstream")
+	}
+	if got := gitOutput(t, appWorktree, "log", "-1", "--format=%s"); got != "Local commit" {
+		t.Errorf("expected the local commit on top, got %q", got)
+	}
+}
+
+func TestPullCmd_FreshSlot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	origin := filepath.Join(testutil.TempDir(t), "app.git")
+	testutil.InitBareRepo(t, origin)
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories:\n  - name: app\n    url: "+origin+"\n")
+	if err := (&InitCmd{}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("InitCmd.Run() error = %v", err)
+	}
+	if err := (&CreateCmd{SlotName: "fresh"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+
+	// The new branch of a fresh slot has no upstream yet, which is not a failure
+	var buf bytes.Buffer
+	if err := (&PullCmd{SlotName: "fresh"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("PullCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"app: skipped, branch test/fresh has no upstream", "not updated 0, no upstream 1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPullCmd_RejectsPathsOutsideSlots(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "existing")
+
+	for _, name := range []string{"..", "../repos", "existing/../../repos"} {
+		err := (&PullCmd{SlotName: name}).Run(testContext(&bytes.Buffer{}))
+		if err == nil || !strings.Contains(err.Error(), "invalid slot name") {
+			t.Errorf("pull %s: expected an invalid slot name error, got %v", name, err)
+		}
+	}
+}
@@ -2,33 +2,60 @@ package command
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
 	"github.com/yammerjp/devslot/internal/lock"
 	"github.com/yammerjp/devslot/internal/slot"
 )
 
 type ReloadCmd struct {
-	SlotName string `arg:"" help:"Name of the slot to reload"`
+	SlotName string `arg:"" optional:"" help:"Name of the slot to reload (the slot of the current directory when omitted)"`
+	Update   bool   `help:"Record the currently checked-out branches as the slot's branches"`
+	Prune    bool   `help:"Remove worktrees of repositories no longer in devslot.yaml"`
+	Repair   bool   `help:"Recreate worktrees that are no longer connected to their repository"`
+	Reseed   bool   `help:"Copy the seed_files of devslot.yaml into the slot again"`
+	DryRun   bool   `name:"dry-run" help:"Show the worktrees that would be created or removed without changing them"`
+	Timings  bool   `help:"Print how long the worktree of each repository took"`
 }
 
 func (c *ReloadCmd) Help() string {
 	return `Ensures all repositories are checked out as worktrees for the slot.
+Without a slot name, the slot containing the current directory is reloaded.
 
 Automatically creates any missing worktrees (useful after adding new
-repositories to devslot.yaml). Runs post-reload hook if it exists.`
+repositories to devslot.yaml). Runs post-reload hook if it exists.
+
+With --update, the branches currently checked out in each worktree are
+recorded as the slot's branches, so intentional branch switches are no
+longer reported as drift by status, list and doctor.
+
+With --prune, worktrees of repositories that were removed from devslot.yaml
+are removed from the slot. Worktrees with uncommitted changes are kept.
+
+A worktree whose repository no longer knows it (for example after its entry
+under repos/ was deleted) makes reload fail. With --repair, such worktrees
+are removed and recreated on the branch recorded for them, or on the default
+branch; uncommitted changes in them are lost.
+
+Worktrees that reload creates get the seed_files of devslot.yaml. With
+--reseed, every seed file is copied into the slot again; copies that were
+changed since they were seeded are kept. Missing shared_links symlinks are
+recreated in every worktree of the slot.
+
+With --timings, how long each worktree that reload created took is printed on
+stderr, slowest first. The table is also printed without it when reloading
+took more than 30 seconds, unless --quiet is given.
+
+With --dry-run, the missing worktrees and their branches (and with --prune,
+the worktrees to remove) are shown; nothing is changed and no hook runs.`
 }
 
 func (c *ReloadCmd) Run(ctx *Context) error {
 	// Find project root
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-
-	projectRoot, err := config.FindProjectRoot(currentDir)
+	projectRoot, err := ctx.FindProjectRoot()
 	if err != nil {
 		return fmt.Errorf("not in a devslot project: %w", err)
 	}
@@ -36,32 +63,100 @@ func (c *ReloadCmd) Run(ctx *Context) error {
 	// Acquire lock
 	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
 	if err := lockFile.Acquire(); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+		return errors.LockFailed(err)
 	}
 	defer func() {
 		if err := lockFile.Release(); err != nil {
-			ctx.Printf("Warning: failed to release lock: %v\n", err)
+			ctx.Eprintf("Warning: failed to release lock: %v\n", err)
 			ctx.LogWarn("failed to release lock", "error", err)
 		}
 	}()
 
 	// Load configuration
-	cfg, err := config.Load(projectRoot)
+	cfg, err := ctx.LoadConfig(projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Reload slot
 	mgr := slot.NewManager(projectRoot)
-	ctx.Printf("Reloading slot '%s'...\n", c.SlotName)
-	ctx.LogInfo("reloading slot", "slot", c.SlotName)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+	if c.SlotName, err = ctx.slotOrCurrent(mgr, c.SlotName, "reload"); err != nil {
+		return err
+	}
+	opts := &slot.ReloadOptions{
+		UpdateBranches: c.Update,
+		Prune:          c.Prune,
+		Repair:         c.Repair,
+		Reseed:         c.Reseed,
+	}
 
-	if err := mgr.Reload(c.SlotName, cfg); err != nil {
-		return fmt.Errorf("failed to reload slot: %w", err)
+	if c.DryRun {
+		plan, err := mgr.PlanReload(c.SlotName, cfg, opts)
+		if err != nil {
+			return fmt.Errorf("failed to reload slot: %w", err)
+		}
+		printReloadPlan(ctx, plan)
+		ctx.Println(dryRunNotice)
+		return nil
 	}
 
-	ctx.Printf("Slot '%s' reloaded successfully!\n", c.SlotName)
-	ctx.LogInfo("slot reloaded", "slot", c.SlotName)
+	ctx.Printf("Reloading slot '%s'...\n", c.SlotName)
+
+	mgr.Timings = ctx.newTimings()
+	result, err := reloadSlot(ctx, mgr, c.SlotName, cfg, opts)
+	if err != nil {
+		return err
+	}
 
+	ctx.Printf("Slot '%s' reloaded successfully: %s\n", c.SlotName, describeReload(result))
+	ctx.printTimings(mgr.Timings, c.Timings)
 	return nil
 }
+
+// reloadSlot reloads a slot and reports the worktrees it created and removed
+func reloadSlot(ctx *Context, mgr *slot.Manager, name string, cfg *config.Config, opts *slot.ReloadOptions) (*slot.ReloadResult, error) {
+	ctx.LogInfo("reloading slot", "slot", name)
+	result, err := mgr.Reload(name, cfg, opts)
+	if result != nil {
+		for _, repo := range result.Created {
+			ctx.Printf("  Created worktree %s/%s\n", name, repo)
+		}
+		for _, repo := range result.Repaired {
+			ctx.Printf("  Recreated corrupted worktree %s/%s\n", name, repo)
+		}
+		for _, repo := range result.Pruned {
+			ctx.Printf("  Removed worktree %s/%s (no longer in devslot.yaml)\n", name, repo)
+		}
+		for _, path := range result.Linked {
+			ctx.Printf("  Linked %s/%s to the shared cache\n", name, path)
+		}
+		for _, path := range result.Seeded {
+			ctx.Printf("  Seeded %s/%s\n", name, path)
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to reload slot: %w", err)
+	}
+	ctx.LogInfo("slot reloaded", "slot", name, "created", len(result.Created), "repaired", len(result.Repaired), "skipped", len(result.Skipped), "pruned", len(result.Pruned))
+	return result, nil
+}
+
+// describeReload summarizes what a reload changed, e.g. "1 worktree created, 2 unchanged"
+func describeReload(result *slot.ReloadResult) string {
+	if len(result.Created) == 0 && len(result.Repaired) == 0 && len(result.Pruned) == 0 {
+		return fmt.Sprintf("up to date (%s)", pluralize(len(result.Skipped), "worktree"))
+	}
+	parts := []string{pluralize(len(result.Created), "worktree") + " created"}
+	if len(result.Repaired) > 0 {
+		parts = append(parts, fmt.Sprintf("%d repaired", len(result.Repaired)))
+	}
+	if len(result.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", len(result.Skipped)))
+	}
+	if len(result.Pruned) > 0 {
+		parts = append(parts, fmt.Sprintf("%d pruned", len(result.Pruned)))
+	}
+	return strings.Join(parts, ", ")
+}
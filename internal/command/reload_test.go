@@ -0,0 +1,296 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestReloadCmd_AddedRepositoryUsesDefaultBranch(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+
+	// repo2 is added after the slot was created; its default branch is develop
+	testutil.NewRepoFixture(t).
+		Branch("develop").Commit("develop.txt", "develop").
+		Default("develop").
+		Push(filepath.Join(projectRoot, "repos", "repo2.git"))
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+  - name: repo2
+    url: https://github.com/example/repo2.git
+`)
+
+	var buf bytes.Buffer
+	if err := (&ReloadCmd{SlotName: "work"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "reloaded successfully: 1 worktree created, 1 unchanged") {
+		t.Errorf("expected a reload summary, got:\n%s", buf.String())
+	}
+
+	// A second reload has nothing to do
+	buf.Reset()
+	if err := (&ReloadCmd{SlotName: "work"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "reloaded successfully: up to date (2 worktrees)") {
+		t.Errorf("expected an up to date summary, got:\n%s", buf.String())
+	}
+
+	worktreePath := filepath.Join(projectRoot, "slots", "work", "repo2")
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != "develop" {
+		t.Errorf("repo2 is on %q, want develop", got)
+	}
+	if !testutil.FileExists(t, filepath.Join(worktreePath, "develop.txt")) {
+		t.Error("expected the commit of develop to be checked out")
+	}
+}
+
+func TestReloadCmd_PruneKeepsDirtyWorktrees(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "slots", "work", "repo1", "wip.txt"), "wip")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	var buf bytes.Buffer
+	ctx := testContext(&buf)
+	if err := (&ReloadCmd{SlotName: "work", Prune: true}).Run(ctx); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo1")) {
+		t.Error("expected worktree with uncommitted changes to be kept")
+	}
+	if !strings.Contains(buf.String(), "Keeping worktree repo1: it has uncommitted changes") {
+		t.Errorf("expected a warning about the kept worktree, got:\n%s", buf.String())
+	}
+}
+
+func TestReloadCmd_DryRun(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+
+	// repo1 is replaced by repo2, whose default branch is develop
+	testutil.NewRepoFixture(t).
+		Branch("develop").Commit("develop.txt", "develop").
+		Default("develop").
+		Push(filepath.Join(projectRoot, "repos", "repo2.git"))
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo2
+    url: https://github.com/example/repo2.git
+`)
+
+	var buf bytes.Buffer
+	if err := (&ReloadCmd{SlotName: "work", Prune: true, DryRun: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{
+		"Would create worktree repo2 on branch develop",
+		"Would remove worktree repo1 (no longer in devslot.yaml)",
+		"No hooks would run",
+		dryRunNotice,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo2")) {
+		t.Error("dry run must not create worktrees")
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo1")) {
+		t.Error("dry run must not remove worktrees")
+	}
+}
+
+func TestReloadCmd_RepairCorruptedWorktree(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+
+	// Deleting the registration in the bare repository disconnects the worktree
+	worktreePath := filepath.Join(projectRoot, "slots", "work", "repo1")
+	branch := gitOutput(t, worktreePath, "branch", "--show-current")
+	gitDir, err := git.WorktreeGitDir(worktreePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(gitDir); err != nil {
+		t.Fatal(err)
+	}
+
+	err = (&ReloadCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "corrupted worktrees in slot work") || !strings.Contains(err.Error(), "--repair") {
+		t.Fatalf("ReloadCmd.Run() error = %v, want a corrupted worktree error suggesting --repair", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&ReloadCmd{SlotName: "work", Repair: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() with --repair error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Recreated corrupted worktree work/repo1") {
+		t.Errorf("expected the repaired worktree to be reported, got:\n%s", buf.String())
+	}
+	if err := git.CheckWorktree(worktreePath); err != nil {
+		t.Fatalf("worktree is still corrupted: %v", err)
+	}
+	if got := gitOutput(t, worktreePath, "branch", "--show-current"); got != branch {
+		t.Errorf("repo1 is on %q, want its previous branch %q", got, branch)
+	}
+}
+
+func TestReloadCmd_Reseed(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+seed_files:
+  seeds/env.local: "{repo}/.env.local"
+  seeds/setup.sh: bin/setup.sh
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+	testutil.CreateFile(t, filepath.Join(projectRoot, "seeds", "env.local"), "PORT=3000\n")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "seeds", "setup.sh"), "#!/bin/sh\n")
+
+	if err := (&CreateCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	slotPath := filepath.Join(projectRoot, "slots", "work")
+	envLocal := filepath.Join(slotPath, "repo1", ".env.local")
+	if got := testutil.ReadFile(t, envLocal); got != "PORT=3000\n" {
+		t.Errorf(".env.local = %q, want the seed file", got)
+	}
+	info, err := os.Stat(filepath.Join(slotPath, "bin", "setup.sh"))
+	if err != nil {
+		t.Fatalf("setup.sh was not seeded: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("setup.sh mode = %v, want the mode of the seed file", info.Mode().Perm())
+	}
+
+	// Reseeding replaces copies that were not changed and keeps the others
+	testutil.CreateFile(t, filepath.Join(projectRoot, "seeds", "env.local"), "PORT=4000\n")
+	testutil.CreateExecutable(t, filepath.Join(projectRoot, "seeds", "setup.sh"), "#!/bin/sh\necho setup\n")
+	testutil.CreateFile(t, envLocal, "PORT=5000\n")
+
+	var buf bytes.Buffer
+	if err := (&ReloadCmd{SlotName: "work", Reseed: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if got := testutil.ReadFile(t, envLocal); got != "PORT=5000\n" {
+		t.Errorf(".env.local = %q, want the changed copy to be kept", got)
+	}
+	if got := testutil.ReadFile(t, filepath.Join(slotPath, "bin", "setup.sh")); got != "#!/bin/sh\necho setup\n" {
+		t.Errorf("setup.sh = %q, want the updated seed file", got)
+	}
+	if !strings.Contains(buf.String(), "Keeping repo1/.env.local") || !strings.Contains(buf.String(), "Seeded work/bin/setup.sh") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	// A missing seed file fails before anything changes
+	if err := os.Remove(filepath.Join(projectRoot, "seeds", "setup.sh")); err != nil {
+		t.Fatal(err)
+	}
+	err = (&CreateCmd{SlotName: "other"}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "seed files not found: seeds/setup.sh") {
+		t.Errorf("CreateCmd.Run() error = %v, want the missing seed file", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "other")) {
+		t.Error("the slot should not be created when a seed file is missing")
+	}
+}
+
+func TestReloadCmd_SharedLinks(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+shared_links:
+  repo1:
+    - node_modules/.cache
+`)
+	testutil.InitBareRepo(t, filepath.Join(projectRoot, "repos", "repo1.git"))
+
+	if err := (&CreateCmd{SlotName: "work"}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("CreateCmd.Run() error = %v", err)
+	}
+	link := filepath.Join(projectRoot, "slots", "work", "repo1", "node_modules", ".cache")
+	cache := filepath.Join(projectRoot, ".devslot", "cache", "repo1", "node_modules", ".cache")
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("%s is not a symlink: %v", link, err)
+	}
+	testutil.CreateFile(t, filepath.Join(link, "entry"), "cached")
+	if !testutil.FileExists(t, filepath.Join(cache, "entry")) {
+		t.Fatal("a file written through the link should land in the shared cache")
+	}
+
+	// Doctor reports a missing link and reload recreates it
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	_ = (&DoctorCmd{}).Run(testContext(&buf))
+	if !strings.Contains(buf.String(), "shared link repo1/node_modules/.cache is missing") {
+		t.Errorf("doctor should report the missing link:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := (&ReloadCmd{SlotName: "work"}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Linked work/repo1/node_modules/.cache") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if !testutil.FileExists(t, filepath.Join(link, "entry")) {
+		t.Error("the recreated link should point at the shared cache")
+	}
+
+	// Destroying the slot keeps the shared cache
+	if err := (&DestroyCmd{Slots: []string{"work"}, Yes: true}).Run(testContext(&bytes.Buffer{})); err != nil {
+		t.Fatalf("DestroyCmd.Run() error = %v", err)
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work")) {
+		t.Error("the slot should be removed")
+	}
+	if !testutil.FileExists(t, filepath.Join(cache, "entry")) {
+		t.Error("destroy should not remove the shared cache")
+	}
+}
+
+func TestReloadCmd_CurrentSlot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature")
+
+	// At the project root a slot name is needed
+	err := (&ReloadCmd{}).Run(testContext(&bytes.Buffer{}))
+	if err == nil || !strings.Contains(err.Error(), "Pass the name of the slot to reload") {
+		t.Errorf("expected a missing slot error at the project root, got %v", err)
+	}
+
+	for _, dir := range []string{"slots/feature", "slots/feature/repo1"} {
+		t.Run(dir, func(t *testing.T) {
+			defer testutil.Chdir(t, filepath.Join(projectRoot, filepath.FromSlash(dir)))()
+			var buf bytes.Buffer
+			if err := (&ReloadCmd{}).Run(testContext(&buf)); err != nil {
+				t.Fatalf("ReloadCmd.Run() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), "Slot 'feature' reloaded successfully") {
+				t.Errorf("expected the current slot to be reloaded, got:\n%s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+type RepoCmd struct {
+	List RepoListCmd `cmd:"" help:"List the configured repositories and the state of their bare clones"`
+}
+
+type RepoListCmd struct {
+	JSON bool `name:"json" help:"Print the repositories as JSON"`
+}
+
+func (c *RepoListCmd) Help() string {
+	return `Lists the repositories configured in devslot.yaml with the state of their
+bare clones in repos/: whether they are cloned, shallow, partial or
+single-branch clones, the URL of origin, their size on disk and how many
+worktrees are registered.
+
+Directories in repos/ that devslot.yaml does not list are shown too; these are
+what 'devslot init --allow-delete' would remove.
+
+Nothing is modified, so the command does not wait for other devslot commands.`
+}
+
+// repoListEntry is the state of one configured repository
+type repoListEntry struct {
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	Cloned       bool   `json:"cloned"`
+	Shallow      bool   `json:"shallow"`
+	Filter       string `json:"filter,omitempty"`
+	SingleBranch string `json:"single_branch,omitempty"`
+	Bytes        int64  `json:"bytes"`
+	Worktrees    int    `json:"worktrees"`
+}
+
+// repoListReport is the result of 'devslot repo list'
+type repoListReport struct {
+	Repositories []repoListEntry `json:"repositories"`
+	Unconfigured []string        `json:"unconfigured"`
+}
+
+func (c *RepoListCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	reposDir := cfg.ReposDir(projectRoot)
+	report := repoListReport{Repositories: []repoListEntry{}, Unconfigured: []string{}}
+	for _, repo := range cfg.Repositories {
+		report.Repositories = append(report.Repositories, inspectRepository(ctx, reposDir, repo))
+	}
+
+	configuredRepos, namespaces := configuredRepoNames(cfg)
+	unconfigured, err := unlistedRepositories(reposDir, configuredRepos, namespaces)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read repos directory: %w", err)
+	}
+	report.Unconfigured = append(report.Unconfigured, unconfigured...)
+
+	if c.JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode repositories: %w", err)
+		}
+		ctx.Resultln(string(data))
+		return nil
+	}
+
+	if len(report.Repositories) == 0 {
+		ctx.Println("No repositories configured.")
+	} else {
+		ctx.Println("Repositories:")
+		for _, entry := range report.Repositories {
+			ctx.Printf("  - %s\n", formatRepoListEntry(entry))
+		}
+	}
+
+	if len(report.Unconfigured) > 0 {
+		ctx.Println()
+		ctx.Printf("Not in %s (removed by 'devslot init --allow-delete'):\n", filepath.Base(ctx.ConfigFile(projectRoot)))
+		for _, name := range report.Unconfigured {
+			ctx.Printf("  - %s\n", name)
+		}
+	}
+	return nil
+}
+
+// inspectRepository reads the state of the bare clone of a configured repository
+func inspectRepository(ctx *Context, reposDir string, repo config.Repository) repoListEntry {
+	entry := repoListEntry{Name: repo.Name, URL: repo.URL}
+	repoPath := filepath.Join(reposDir, repo.BareRepoName())
+	if !git.IsValidRepository(repoPath) {
+		return entry
+	}
+
+	entry.Cloned = true
+	if url, err := git.GetRemoteURL(repoPath, repo.RemoteName()); err == nil {
+		entry.URL = url
+	}
+	entry.Shallow = git.IsShallow(repoPath)
+	entry.Filter = git.PartialCloneFilter(repoPath, repo.RemoteName())
+	entry.SingleBranch = git.SingleBranch(repoPath, repo.RemoteName())
+	entry.Bytes = diskUsage(ctx, repoPath, func(string) string { return "" })[""]
+
+	worktrees, err := git.ListWorktrees(repoPath)
+	if err != nil {
+		ctx.LogWarn("failed to list worktrees", "repo", repo.Name, "error", err)
+	}
+	entry.Worktrees = len(worktrees)
+	return entry
+}
+
+// formatRepoListEntry formats a repository as one line of 'devslot repo list'
+func formatRepoListEntry(entry repoListEntry) string {
+	if !entry.Cloned {
+		return fmt.Sprintf("%s: not cloned (%s)", entry.Name, entry.URL)
+	}
+
+	details := []string{formatSize(entry.Bytes)}
+	if entry.Worktrees == 1 {
+		details = append(details, "1 worktree")
+	} else {
+		details = append(details, fmt.Sprintf("%d worktrees", entry.Worktrees))
+	}
+	if entry.Shallow {
+		details = append(details, "shallow")
+	}
+	if entry.Filter != "" {
+		details = append(details, "filter "+entry.Filter)
+	}
+	if entry.SingleBranch != "" {
+		details = append(details, "single branch "+entry.SingleBranch)
+	}
+	return fmt.Sprintf("%s: %s (%s)", entry.Name, strings.Join(details, ", "), entry.URL)
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestRepoListCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "feature")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+  - name: repo2
+    url: https://github.com/example/repo2.git
+`)
+	if err := os.MkdirAll(filepath.Join(projectRoot, "repos", "old.git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&RepoListCmd{JSON: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("RepoListCmd.Run() error = %v", err)
+	}
+	var report repoListReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(report.Repositories) != 2 {
+		t.Fatalf("expected 2 repositories, got %+v", report.Repositories)
+	}
+	repo1 := report.Repositories[0]
+	if repo1.Name != "repo1" || !repo1.Cloned || repo1.Shallow || repo1.Filter != "" || repo1.Bytes == 0 || repo1.Worktrees != 1 {
+		t.Errorf("unexpected state of repo1: %+v", repo1)
+	}
+	if repo2 := report.Repositories[1]; repo2.Cloned || repo2.URL != "https://github.com/example/repo2.git" {
+		t.Errorf("unexpected state of repo2: %+v", repo2)
+	}
+	if len(report.Unconfigured) != 1 || report.Unconfigured[0] != "old.git" {
+		t.Errorf("Unconfigured = %v, want [old.git]", report.Unconfigured)
+	}
+
+	buf.Reset()
+	if err := (&RepoListCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("RepoListCmd.Run() error = %v", err)
+	}
+	for _, want := range []string{"repo1: ", "1 worktree", "repo2: not cloned (https://github.com/example/repo2.git)", "- old.git"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
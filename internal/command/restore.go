@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type RestoreCmd struct {
+	Archive string `arg:"" help:"Slot name (restores its newest archive) or archive file"`
+}
+
+func (c *RestoreCmd) Help() string {
+	return `Recreates a slot archived with 'devslot archive'.
+
+Given a slot name, the newest archive of that slot is restored; an archive
+file name or path picks a specific one. The worktrees are registered with
+their repositories again ('git worktree repair'), with uncommitted changes,
+untracked files and the index as they were. The archive is removed once the
+slot has been restored.
+
+No hooks are run. A slot with the same name must not exist.`
+}
+
+func (c *RestoreCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a devslot project: %w", err)
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.Eprintf("Warning: failed to release lock: %v\n", err)
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+
+	archivePath, err := mgr.FindArchive(c.Archive)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printf("Restoring %s...\n", archivePath)
+	ctx.LogInfo("restoring slot", "archive", archivePath)
+
+	name, err := mgr.Restore(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to restore slot: %w", err)
+	}
+
+	ctx.Success("Slot '%s' restored!", name)
+	ctx.Printf("You can now work in: %s\n", filepath.Join(cfg.SlotsDir(projectRoot), name))
+	ctx.LogInfo("slot restored", "slot", name, "archive", archivePath)
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package command
+
+type RootCmd struct{}
+
+func (c *RootCmd) Help() string {
+	return `Prints the absolute path of the project root (the directory containing
+devslot.yaml), searching upward from the current directory.
+
+Only the path is printed, so it is safe to use as cd "$(devslot root)".`
+}
+
+func (c *RootCmd) Run(ctx *Context) error {
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	ctx.Resultln(projectRoot)
+	return nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type StatusCmd struct {
+	SlotName string `arg:"" optional:"" help:"Name of the slot to inspect (the slot of the current directory when omitted)"`
+}
+
+func (c *StatusCmd) Help() string {
+	return `Shows the branch checked out in each worktree of a slot.
+
+Worktrees whose branch differs from the branch recorded when the slot was
+created are marked with ≠. Run 'devslot reload --update <slot>' to record
+the current branches if the switch was intentional.
+
+Without a slot name, the slot containing the current directory is shown.`
+}
+
+func (c *StatusCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("not in a devslot project: %w", err)
+	}
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	if c.SlotName, err = ctx.slotOrCurrent(mgr, c.SlotName, "inspect"); err != nil {
+		return err
+	}
+	statuses, err := mgr.Status(c.SlotName, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printf("Slot '%s':\n", c.SlotName)
+	drifted := 0
+	for _, status := range statuses {
+		ctx.Printf("  %s\n", formatRepoStatus(status))
+		if status.Drifted() {
+			drifted++
+			ctx.LogWarn("worktree branch drifted", "slot", c.SlotName, "repository", status.Name, "branch", status.Branch, "recorded", status.RecordedBranch)
+		}
+	}
+
+	if drifted > 0 {
+		ctx.Eprintf("Warning: %d worktree(s) are not on the branch recorded at creation\n", drifted)
+		ctx.Printf("Run 'devslot reload --update %s' if the switch was intentional\n", c.SlotName)
+	}
+
+	return nil
+}
+
+// formatRepoStatus renders a worktree's branch, marking drift from the recorded branch with ≠
+func formatRepoStatus(status slot.RepoStatus) string {
+	if !status.Exists {
+		if status.Optional {
+			return fmt.Sprintf("%s: (optional, not present)", status.Name)
+		}
+		return fmt.Sprintf("%s: (missing)", status.Name)
+	}
+
+	branch := status.Branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+
+	if status.Drifted() {
+		return fmt.Sprintf("%s: %s ≠ %s (recorded)", status.Name, branch, status.RecordedBranch)
+	}
+	return fmt.Sprintf("%s: %s", status.Name, branch)
+}
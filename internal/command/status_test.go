@@ -0,0 +1,111 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// setupProjectWithSlot creates a project with one repository and a slot created from it
+func setupProjectWithSlot(t *testing.T, projectRoot, slotName string) {
+	t.Helper()
+
+	yamlContent := `version: 1
+repositories:
+  - name: repo1
+    url: https://github.com/example/repo1.git
+`
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), yamlContent)
+
+	repoPath := filepath.Join(projectRoot, "repos", "repo1.git")
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.InitBareRepo(t, repoPath)
+
+	var buf bytes.Buffer
+	cmd := &CreateCmd{SlotName: slotName}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("failed to create slot: %v", err)
+	}
+}
+
+func TestStatusCmd_Drift(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "drift-slot")
+
+	// Status right after creation reports no drift
+	var buf bytes.Buffer
+	cmd := &StatusCmd{SlotName: "drift-slot"}
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("StatusCmd.Run() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "≠") {
+		t.Errorf("expected no drift right after creation, got:\n%s", buf.String())
+	}
+
+	// Switch the worktree to another branch
+	worktreePath := filepath.Join(projectRoot, "slots", "drift-slot", "repo1")
+	if output, err := exec.Command("git", "-C", worktreePath, "checkout", "-b", "other-branch").CombinedOutput(); err != nil {
+		t.Fatalf("failed to switch branch: %v\n%s", err, output)
+	}
+
+	buf.Reset()
+	if err := cmd.Run(testContext(&buf)); err != nil {
+		t.Fatalf("StatusCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "repo1: other-branch ≠") {
+		t.Errorf("expected drift marker in status output, got:\n%s", buf.String())
+	}
+
+	// list --verbose shows the marker as well
+	buf.Reset()
+	if err := (&ListCmd{}).Run(&Context{Out: &buf, Err: &buf, Verbose: true}); err != nil {
+		t.Fatalf("ListCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "≠") {
+		t.Errorf("expected drift marker in list --verbose output, got:\n%s", buf.String())
+	}
+
+	// info --drift lists the worktree
+	buf.Reset()
+	if err := (&InfoCmd{Drift: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InfoCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "drift-slot/repo1: other-branch") {
+		t.Errorf("expected drifted worktree in info --drift output, got:\n%s", buf.String())
+	}
+
+	// reload --update records the new branch
+	buf.Reset()
+	if err := (&ReloadCmd{SlotName: "drift-slot", Update: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("ReloadCmd.Run() error = %v", err)
+	}
+
+	buf.Reset()
+	if err := (&InfoCmd{Drift: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("InfoCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No drifted worktrees found") {
+		t.Errorf("expected no drift after reload --update, got:\n%s", buf.String())
+	}
+}
+
+func TestStatusCmd_SlotNotFound(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, projectRoot)
+	defer testutil.Chdir(t, projectRoot)()
+
+	var buf bytes.Buffer
+	cmd := &StatusCmd{SlotName: "missing"}
+	err := cmd.Run(testContext(&buf))
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected slot not found error, got: %v", err)
+	}
+}
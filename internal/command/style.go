@@ -0,0 +1,124 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode selects when output is decorated with color and emoji
+type ColorMode string
+
+const (
+	// ColorAuto styles output only when Out is a terminal and NO_COLOR is not set
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always styles output
+	ColorAlways ColorMode = "always"
+	// ColorNever prints plain [OK]/[FAIL]/[WARN]/[INFO] prefixes
+	ColorNever ColorMode = "never"
+)
+
+const colorReset = "\033[0m"
+
+// marker is the prefix of a status line in styled and plain output
+type marker struct {
+	emoji string
+	plain string
+	color string
+}
+
+var (
+	successMarker = marker{emoji: "✅", plain: "[OK]", color: "\033[32m"}
+	failureMarker = marker{emoji: "❌", plain: "[FAIL]", color: "\033[31m"}
+	warnMarker    = marker{emoji: "⚠️ ", plain: "[WARN]", color: "\033[33m"}
+	infoMarker    = marker{emoji: "ℹ️ ", plain: "[INFO]", color: "\033[36m"}
+)
+
+// Styled reports whether output should use color and emoji
+func (c *Context) Styled() bool {
+	return c.styled(c.Out)
+}
+
+// styled reports whether output written to w should use color and emoji
+func (c *Context) styled(w io.Writer) bool {
+	switch c.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// Success prints a line reporting that something is fine
+func (c *Context) Success(format string, args ...any) {
+	c.printStatus(successMarker, format, args...)
+}
+
+// Failure prints a line reporting a problem to Err, also with --quiet
+func (c *Context) Failure(format string, args ...any) {
+	c.writeStatus(c.Err, failureMarker, format, args...)
+}
+
+// Warn prints a line reporting something that may need attention to Err, also with --quiet
+func (c *Context) Warn(format string, args ...any) {
+	c.writeStatus(c.Err, warnMarker, format, args...)
+}
+
+// Info prints a line with additional information
+func (c *Context) Info(format string, args ...any) {
+	c.printStatus(infoMarker, format, args...)
+}
+
+// printStatus prints an informational status line to Out unless output is quiet
+func (c *Context) printStatus(m marker, format string, args ...any) {
+	if c.Verbosity == VerbosityQuiet {
+		return
+	}
+	c.writeStatus(c.Out, m, format, args...)
+}
+
+// writeStatus writes a status line to w. Leading spaces of format are kept in front
+// of the marker so that findings can be indented under a heading.
+func (c *Context) writeStatus(w io.Writer, m marker, format string, args ...any) {
+	msg := fmt.Sprintf(strings.TrimLeft(format, " "), args...)
+	indent := format[:len(format)-len(strings.TrimLeft(format, " "))]
+	if c.styled(w) {
+		fmt.Fprintf(w, "%s%s %s%s%s\n", indent, m.emoji, m.color, msg, colorReset)
+		return
+	}
+	fmt.Fprintf(w, "%s%s %s\n", indent, m.plain, msg)
+}
+
+// terminal is implemented by streams that know whether they are a terminal, such as fakes in tests
+type terminal interface {
+	IsTerminal() bool
+}
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w any) bool {
+	if t, ok := w.(terminal); ok {
+		return t.IsTerminal()
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// pluralize returns a count with its noun, adding an s unless the count is one
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
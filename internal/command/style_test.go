@@ -0,0 +1,77 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestContext_StatusLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		color   ColorMode
+		noColor string
+		want    string
+	}{
+		{
+			name:  "never",
+			color: ColorNever,
+			want:  "[OK] done\n  [FAIL] broken\n  [WARN] careful\n[INFO] note 1\n",
+		},
+		{
+			name:  "always",
+			color: ColorAlways,
+			want: "✅ \033[32mdone\033[0m\n" +
+				"  ❌ \033[31mbroken\033[0m\n" +
+				"  ⚠️  \033[33mcareful\033[0m\n" +
+				"ℹ️  \033[36mnote 1\033[0m\n",
+		},
+		{
+			name:  "auto with a non-terminal writer",
+			color: ColorAuto,
+			want:  "[OK] done\n  [FAIL] broken\n  [WARN] careful\n[INFO] note 1\n",
+		},
+		{
+			name:    "auto honors NO_COLOR",
+			color:   ColorAuto,
+			noColor: "1",
+			want:    "[OK] done\n  [FAIL] broken\n  [WARN] careful\n[INFO] note 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			var buf bytes.Buffer
+			ctx := testContext(&buf)
+			ctx.Color = tt.color
+
+			ctx.Success("done")
+			ctx.Failure("  broken")
+			ctx.Warn("  careful")
+			ctx.Info("note %d", 1)
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_StatusLinesQuiet(t *testing.T) {
+	var out, errOut bytes.Buffer
+	ctx := &Context{Out: &out, Err: &errOut, Verbosity: VerbosityQuiet}
+
+	ctx.Success("done")
+	ctx.Info("note")
+	ctx.Failure("broken")
+	ctx.Warn("careful")
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output in quiet mode, got %q", out.String())
+	}
+	// Warnings and failures still surface, on stderr
+	if want := "[FAIL] broken\n[WARN] careful\n"; errOut.String() != want {
+		t.Errorf("stderr = %q, want %q", errOut.String(), want)
+	}
+}
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+)
+
+type SyncCmd struct {
+	Fetch      bool `help:"Fetch updates for repositories that already exist"`
+	Prune      bool `help:"Remove worktrees of repositories no longer in devslot.yaml"`
+	NoProgress bool `name:"no-progress" help:"Don't report the progress of clones"`
+}
+
+func (c *SyncCmd) Help() string {
+	return `Brings the whole project in line with devslot.yaml.
+
+This command runs 'devslot init' (cloning missing repositories, and fetching
+existing ones with --fetch), then 'devslot reload' for every slot, creating
+the worktrees of newly added repositories. With --prune, worktrees of
+repositories removed from devslot.yaml are removed from the slots; worktrees
+with uncommitted changes are kept.
+
+The project is locked once for the whole operation. A slot that fails to
+reload does not stop the others; the command fails after reporting them all.`
+}
+
+func (c *SyncCmd) Run(ctx *Context) error {
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	l := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := l.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := l.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	initCmd := &InitCmd{Fetch: c.Fetch, NoProgress: c.NoProgress}
+	summary, err := initCmd.Initialize(ctx, projectRoot, cfg)
+	if err != nil {
+		return err
+	}
+
+	mgr := slot.NewManager(projectRoot)
+	mgr.SetDirs(cfg)
+	mgr.Err = ctx.Err
+	slots, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list slots: %w", err)
+	}
+
+	var created, pruned int
+	var failed []string
+	opts := &slot.ReloadOptions{Prune: c.Prune}
+	for _, name := range slots {
+		ctx.Printf("\nReloading slot '%s'...\n", name)
+		result, err := reloadSlot(ctx, mgr, name, cfg, opts)
+		if result != nil {
+			created += len(result.Created)
+			pruned += len(result.Pruned)
+		}
+		if err != nil {
+			ctx.Failure("%s: %v", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		ctx.Printf("  %s\n", describeReload(result))
+	}
+
+	ctx.Println("\nSync summary:")
+	if c.Fetch {
+		ctx.Printf("  Repositories: %d cloned, %d fetched, %d skipped\n", len(summary.Cloned), len(summary.Fetched), len(summary.Skipped))
+	} else {
+		ctx.Printf("  Repositories: %d cloned, %d skipped\n", len(summary.Cloned), len(summary.Skipped))
+	}
+	ctx.Printf("  Slots: %d reloaded, %d failed\n", len(slots)-len(failed), len(failed))
+	ctx.Printf("  Worktrees: %d created, %d pruned\n", created, pruned)
+	ctx.LogInfo("sync summary", "slots", len(slots), "failed", strings.Join(failed, ","), "created", created, "pruned", pruned)
+
+	if len(failed) > 0 {
+		return errors.ReloadIncomplete(failed, len(slots))
+	}
+	return nil
+}
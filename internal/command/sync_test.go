@@ -0,0 +1,75 @@
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestSyncCmd_ReloadsEverySlot(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+
+	// repo1 is replaced by repo2 in devslot.yaml
+	testutil.NewRepoFixture(t).
+		Commit("README.md", "repo2").
+		Push(filepath.Join(projectRoot, "repos", "repo2.git"))
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: repo2
+    url: https://github.com/example/repo2.git
+`)
+
+	var buf bytes.Buffer
+	if err := (&SyncCmd{Prune: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("SyncCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	output := buf.String()
+
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo2")) {
+		t.Error("expected worktree work/repo2 to be created")
+	}
+	if testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo1")) {
+		t.Error("expected worktree work/repo1 to be pruned")
+	}
+
+	for _, want := range []string{
+		"Created worktree work/repo2",
+		"Removed worktree work/repo1",
+		"Repositories: 0 cloned, 1 skipped",
+		"Slots: 1 reloaded, 0 failed",
+		"Worktrees: 1 created, 1 pruned",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	// A second sync has nothing to do
+	buf.Reset()
+	if err := (&SyncCmd{Prune: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("SyncCmd.Run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Worktrees: 0 created, 0 pruned") {
+		t.Errorf("expected nothing to change, got:\n%s", buf.String())
+	}
+}
+
+func TestSyncCmd_WithoutPruneKeepsWorktrees(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+	setupProjectWithSlot(t, projectRoot, "work")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []\n")
+
+	var buf bytes.Buffer
+	if err := (&SyncCmd{}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("SyncCmd.Run() error = %v\n%s", err, buf.String())
+	}
+	if !testutil.DirExists(t, filepath.Join(projectRoot, "slots", "work", "repo1")) {
+		t.Error("expected worktree work/repo1 to be kept without --prune")
+	}
+}
@@ -0,0 +1,29 @@
+package command
+
+import (
+	"github.com/yammerjp/devslot/internal/metrics"
+)
+
+// newTimings returns a recorder for the per-repository steps of a command, logging
+// each step at debug level as it finishes
+func (c *Context) newTimings() *metrics.Recorder {
+	timings := metrics.New()
+	timings.OnStop = func(step metrics.Step) {
+		c.LogDebug("step finished", "step", step.Label, "duration", step.Duration)
+	}
+	return timings
+}
+
+// printTimings prints the steps, slowest first, on stderr when --timings was given,
+// or when the command took longer than metrics.SlowThreshold unless it is quiet
+func (c *Context) printTimings(timings *metrics.Recorder, requested bool) {
+	steps := timings.Steps()
+	if len(steps) == 0 {
+		return
+	}
+	if !requested && (!timings.Slow() || c.Verbosity == VerbosityQuiet) {
+		return
+	}
+	c.Eprintln()
+	metrics.Render(c.Err, steps, timings.Elapsed())
+}
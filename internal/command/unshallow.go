@@ -0,0 +1,149 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/parallel"
+)
+
+// unshallowConcurrency limits how many repositories 'devslot unshallow' fetches at once
+const unshallowConcurrency = 4
+
+type UnshallowCmd struct {
+	Repos  []string `arg:"" optional:"" name:"repo" help:"Repositories to unshallow (all shallow repositories when omitted)"`
+	DryRun bool     `name:"dry-run" help:"List the shallow repositories without fetching"`
+}
+
+func (c *UnshallowCmd) Help() string {
+	return `Fetches the full history of shallow bare repositories in repos/, for
+example after cloning them with a limited depth.
+
+Without arguments, every repository of devslot.yaml that is a shallow clone is
+unshallowed; repositories that are not cloned or already have their full
+history are skipped. The repositories are fetched in parallel, and a failure
+does not stop the others: the command reports how many repositories were
+unshallowed, skipped and failed, and fails if any did.
+
+With --dry-run, the shallow repositories are listed and nothing is fetched.`
+}
+
+func (c *UnshallowCmd) Run(ctx *Context) error {
+	// Find project root
+	projectRoot, err := ctx.FindProjectRoot()
+	if err != nil {
+		return err // config.FindProjectRoot already returns a user-friendly error
+	}
+
+	// Acquire lock
+	lockFile := lock.New(filepath.Join(projectRoot, ".devslot.lock"))
+	if err := lockFile.Acquire(); err != nil {
+		return errors.LockFailed(err)
+	}
+	defer func() {
+		if err := lockFile.Release(); err != nil {
+			ctx.LogWarn("failed to release lock", "error", err)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := ctx.LoadConfig(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	repos, err := c.selectRepos(cfg)
+	if err != nil {
+		return err
+	}
+
+	reposDir := cfg.ReposDir(projectRoot)
+	var shallow []config.Repository
+	skipped := 0
+	for _, repo := range repos {
+		bareRepoPath := filepath.Join(reposDir, repo.BareRepoName())
+		switch {
+		case !git.IsValidRepository(bareRepoPath):
+			ctx.Printf("Repository %s is not cloned, skipping (run 'devslot init')\n", repo.Name)
+			skipped++
+		case !git.IsShallow(bareRepoPath):
+			ctx.LogInfo("repository is not shallow", "name", repo.Name)
+			skipped++
+		case !git.HasRemote(bareRepoPath, repo.RemoteName()):
+			ctx.Printf("Repository %s has no remote %s, skipping\n", repo.Name, repo.RemoteName())
+			skipped++
+		default:
+			shallow = append(shallow, repo)
+		}
+	}
+
+	if c.DryRun {
+		for _, repo := range shallow {
+			ctx.Printf("Would unshallow %s\n", repo.Name)
+		}
+		if len(shallow) == 0 {
+			ctx.Println("No shallow repositories.")
+		}
+		return nil
+	}
+
+	if len(shallow) > 0 {
+		ctx.Printf("Unshallowing %d repositories...\n", len(shallow))
+	}
+	tasks := make([]parallel.Task, len(shallow))
+	for i, repo := range shallow {
+		bareRepoPath := filepath.Join(reposDir, repo.BareRepoName())
+		tasks[i] = parallel.Task{Name: repo.Name, Run: func() error { return git.Unshallow(bareRepoPath, repo.RemoteName()) }}
+	}
+	results, err := parallel.Run(unshallowConcurrency, tasks)
+
+	// Report in devslot.yaml order once all fetches finished, so one failure doesn't hide the others
+	failed := 0
+	for i, repo := range shallow {
+		if results[i] != nil {
+			ctx.Printf("Failed to unshallow %s: %v\n", repo.Name, results[i])
+			ctx.LogWarn("failed to unshallow repository", "name", repo.Name, "error", results[i])
+			failed++
+			continue
+		}
+		ctx.Printf("Unshallowed %s\n", repo.Name)
+		ctx.LogInfo("unshallowed repository", "name", repo.Name)
+	}
+
+	ctx.Printf("\n%d unshallowed, %d skipped, %d failed\n", len(shallow)-failed, skipped, failed)
+	if err != nil {
+		return errors.UnshallowFailed(err)
+	}
+	return nil
+}
+
+// selectRepos returns the repositories named on the command line in devslot.yaml order,
+// or all repositories when none are named
+func (c *UnshallowCmd) selectRepos(cfg *config.Config) ([]config.Repository, error) {
+	if len(c.Repos) == 0 {
+		return cfg.Repositories, nil
+	}
+
+	var known []string
+	for _, repo := range cfg.Repositories {
+		known = append(known, repo.Name)
+	}
+	for _, name := range c.Repos {
+		if !slices.Contains(known, name) {
+			return nil, errors.UnknownRepository(name, known)
+		}
+	}
+
+	var repos []config.Repository
+	for _, repo := range cfg.Repositories {
+		if slices.Contains(c.Repos, repo.Name) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestUnshallowCmd(t *testing.T) {
+	projectRoot := testutil.TempDir(t)
+	defer testutil.Chdir(t, projectRoot)()
+
+	origin := filepath.Join(testutil.TempDir(t), "origin.git")
+	testutil.NewRepoFixture(t).Commit("a.txt", "a").Commit("b.txt", "b").Push(origin)
+	for _, name := range []string{"app", "lib", "gone"} {
+		if output, err := exec.Command("git", "clone", "--quiet", "--bare", "--depth", "1", "file://"+origin, filepath.Join(projectRoot, "repos", name+".git")).CombinedOutput(); err != nil {
+			t.Fatalf("git clone --depth 1 failed: %v\n%s", err, output)
+		}
+	}
+	// The remote of gone no longer exists, so unshallowing it fails
+	gitOutput(t, filepath.Join(projectRoot, "repos", "gone.git"), "remote", "set-url", "origin", filepath.Join(projectRoot, "missing.git"))
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), `version: 1
+repositories:
+  - name: app
+    url: `+origin+`
+  - name: gone
+    url: `+origin+`
+  - name: lib
+    url: `+origin+`
+  - name: other
+    url: `+origin+`
+`)
+
+	var buf bytes.Buffer
+	if err := (&UnshallowCmd{DryRun: true}).Run(testContext(&buf)); err != nil {
+		t.Fatalf("UnshallowCmd.Run(--dry-run) error = %v", err)
+	}
+	for _, want := range []string{"Would unshallow app", "Would unshallow gone", "Would unshallow lib"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+	if !git.IsShallow(filepath.Join(projectRoot, "repos", "app.git")) {
+		t.Fatal("expected --dry-run to leave app shallow")
+	}
+
+	found := false
+	for _, finding := range (&DoctorCmd{}).Diagnose(testContext(&bytes.Buffer{}), projectRoot) {
+		if finding.Check == "repositories" && strings.HasPrefix(finding.Message, "3 repositories are shallow; run 'devslot unshallow'") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected doctor to suggest unshallowing the 3 shallow repositories")
+	}
+
+	// A failing repository doesn't stop the others
+	buf.Reset()
+	err := (&UnshallowCmd{}).Run(testContext(&buf))
+	if err == nil || !strings.Contains(err.Error(), "gone") {
+		t.Errorf("expected gone to fail, got %v", err)
+	}
+	for _, name := range []string{"app", "lib"} {
+		if git.IsShallow(filepath.Join(projectRoot, "repos", name+".git")) {
+			t.Errorf("expected %s to be unshallowed", name)
+		}
+	}
+	if !strings.Contains(buf.String(), "2 unshallowed, 1 skipped, 1 failed") {
+		t.Errorf("unexpected summary:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&UnshallowCmd{Repos: []string{"unknown"}}).Run(testContext(&buf)); err == nil || !strings.Contains(err.Error(), "unknown repository unknown") {
+		t.Errorf("expected an unknown repository error, got %v", err)
+	}
+}
@@ -1,12 +1,31 @@
 package command
 
-// Version is set by the main package
-var Version = "dev"
+import (
+	"encoding/json"
+	"fmt"
 
-type VersionCmd struct{}
+	"github.com/yammerjp/devslot/internal/version"
+)
+
+type VersionCmd struct {
+	JSON bool `name:"json" help:"Print build information as JSON"`
+}
 
 func (c *VersionCmd) Run(ctx *Context) error {
-	ctx.Printf("devslot version %s\n", Version)
-	ctx.LogInfo("version requested", "version", Version)
+	info := version.Get()
+	ctx.LogInfo("version requested", "version", info.Version, "commit", info.Commit, "date", info.Date)
+
+	if c.JSON {
+		data, err := json.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("failed to encode version: %w", err)
+		}
+		ctx.Println(string(data))
+		return nil
+	}
+
+	ctx.Printf("devslot version %s\n", info.Version)
+	ctx.Printf("commit: %s\n", info.Commit)
+	ctx.Printf("built: %s\n", info.Date)
 	return nil
 }
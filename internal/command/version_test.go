@@ -2,13 +2,16 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
+
+	"github.com/yammerjp/devslot/internal/version"
 )
 
 func TestVersionCmd_Run(t *testing.T) {
 	var buf bytes.Buffer
 	cmd := &VersionCmd{}
-	ctx := &Context{Writer: &buf}
+	ctx := testContext(&buf)
 
 	if err := cmd.Run(ctx); err != nil {
 		t.Fatalf("VersionCmd.Run() error = %v", err)
@@ -19,3 +22,36 @@ func TestVersionCmd_Run(t *testing.T) {
 		t.Errorf("VersionCmd.Run() output = %v, want to contain 'devslot version'", output)
 	}
 }
+
+func TestVersionCmd_Output(t *testing.T) {
+	defer func(v, c, d string) {
+		version.Version, version.Commit, version.Date = v, c, d
+	}(version.Version, version.Commit, version.Date)
+	version.Version, version.Commit, version.Date = "1.2.3", "abc1234", "2024-01-02T03:04:05Z"
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&VersionCmd{}).Run(testContext(&buf)); err != nil {
+			t.Fatalf("VersionCmd.Run() error = %v", err)
+		}
+		want := "devslot version 1.2.3\ncommit: abc1234\nbuilt: 2024-01-02T03:04:05Z\n"
+		if got := buf.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&VersionCmd{JSON: true}).Run(testContext(&buf)); err != nil {
+			t.Fatalf("VersionCmd.Run() error = %v", err)
+		}
+		var info version.Info
+		if err := json.Unmarshal(buf.Bytes(), &info); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+		}
+		want := version.Info{Version: "1.2.3", Commit: "abc1234", Date: "2024-01-02T03:04:05Z"}
+		if info != want {
+			t.Errorf("info = %+v, want %+v", info, want)
+		}
+	})
+}
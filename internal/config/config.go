@@ -1,23 +1,148 @@
 package config
 
 import (
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
 
-	"github.com/goccy/go-yaml"
 	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
 )
 
 // Config represents the devslot.yaml configuration
 type Config struct {
-	Version      int          `yaml:"version"`
-	Repositories []Repository `yaml:"repositories"`
+	Version int `yaml:"version"`
+	// DefaultHost is the host that org/repo shorthand URLs expand to (github.com by default)
+	DefaultHost string `yaml:"default_host"`
+	// BranchPrefix is the branch name template for new slots (e.g. "feature/{user}/{slot}")
+	BranchPrefix string `yaml:"branch_prefix,omitempty"`
+	// EnvrcTemplate is a text/template rendered into slots/<name>/.envrc, relative to the project root
+	EnvrcTemplate string `yaml:"envrc_template,omitempty"`
+	// OpenCommand is the command devslot open runs, with {path} replaced by the directory to open
+	OpenCommand string `yaml:"open_command,omitempty"`
+	// Extends is another configuration file whose repositories are included before the local ones
+	Extends string `yaml:"extends,omitempty"`
+	// ReposPath and SlotsPath relocate the repos/ and slots/ directories (absolute or relative to
+	// the project root). Load resolves them to absolute paths; use ReposDir and SlotsDir to read them.
+	ReposPath    string              `yaml:"repos_dir,omitempty"`
+	SlotsPath    string              `yaml:"slots_dir,omitempty"`
+	Repositories []Repository        `yaml:"repositories"`
+	Hooks        map[string][]string `yaml:"hooks"`
+	// SlotTemplates are standard slot shapes that 'devslot create --template' applies, by name
+	SlotTemplates map[string]SlotTemplate `yaml:"slot_templates,omitempty"`
+	// GitEnv holds environment variables, such as HTTPS_PROXY or GIT_SSH_COMMAND, that every
+	// git command devslot runs for the project gets, taking precedence over the shell's
+	GitEnv map[string]string `yaml:"git_env,omitempty"`
+	// SeedFiles maps files, relative to the project root, to where new slots get a copy of them,
+	// relative to the slot directory. {repo} in a destination repeats the entry for every
+	// repository of the slot and may also appear in the source.
+	SeedFiles map[string]string `yaml:"seed_files,omitempty"`
+	// MinGitVersion is the oldest git version doctor accepts without a warning, e.g. "2.30";
+	// empty means git.DefaultMinVersion
+	MinGitVersion string `yaml:"min_git_version,omitempty"`
+	// GitBinary is the git program devslot runs, for environments that ship their own git:
+	// a name looked up on PATH or a path, relative to the project root. DEVSLOT_GIT overrides it.
+	GitBinary string `yaml:"git_binary,omitempty"`
+	// StrictHooks makes a failing post-destroy hook fail the command; by default the
+	// slot is already gone and only a warning is shown
+	StrictHooks bool `yaml:"strict_hooks,omitempty"`
+	// SharedLinks lists paths, relative to the worktree, that the worktrees of a repository
+	// share through symlinks into the project's shared cache instead of each slot keeping its own
+	SharedLinks map[string][]string `yaml:"shared_links,omitempty"`
 }
 
+// SeedRepoPlaceholder is replaced with a repository name in seed_files paths
+const SeedRepoPlaceholder = "{repo}"
+
+// SlotTemplate describes a standard slot: which repositories it has, the
+// branches they are on and what the slot is for
+type SlotTemplate struct {
+	Description string `yaml:"description,omitempty"`
+	// Repositories limits the slot to these repositories (all when empty)
+	Repositories []string `yaml:"repositories,omitempty"`
+	// Branches overrides the branch of individual repositories
+	Branches map[string]BranchOverride `yaml:"branches,omitempty"`
+}
+
+// BranchOverride sets the branch of one repository in a slot template
+type BranchOverride struct {
+	// Branch is checked out instead of the slot branch, and created when it doesn't exist
+	Branch string `yaml:"branch,omitempty"`
+	// Base is where a new branch starts instead of the default branch
+	Base string `yaml:"base,omitempty"`
+}
+
+// MaxExtendsDepth is the longest chain of configuration files that extends may form
+const MaxExtendsDepth = 8
+
+// DefaultShorthandHost is used for org/repo shorthand URLs when default_host is not set
+const DefaultShorthandHost = "github.com"
+
+// shorthandPattern matches org/repo repository shorthand
+var shorthandPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
 // Repository represents a single repository in the configuration
 type Repository struct {
 	Name string `yaml:"name"`
 	URL  string `yaml:"url"`
+	// Bundle is the git bundle used by --from-bundles, relative to the bundle directory
+	Bundle string `yaml:"bundle,omitempty"`
+	// CloneFilter is a partial clone filter spec (e.g. blob:none) used when cloning
+	CloneFilter string `yaml:"clone_filter,omitempty"`
+	// SingleBranch clones and fetches only one branch: Branch, or the remote's default branch
+	SingleBranch bool `yaml:"single_branch,omitempty"`
+	// Branch is the branch a single_branch repository is cloned with
+	Branch string `yaml:"branch,omitempty"`
+	// Optional repositories may be missing: create and reload skip them when they are not cloned,
+	// and init only warns when cloning them fails
+	Optional bool `yaml:"optional,omitempty"`
+	// Groups tag the repository so that commands can work on a subset with --group
+	Groups []string `yaml:"groups,omitempty"`
+	// Remote is the name of the remote devslot clones as and fetches from (origin by default)
+	Remote string `yaml:"remote,omitempty"`
+	// Source is the configuration file the repository is defined in, relative to the project root
+	Source string `yaml:"-"`
+}
+
+// UnmarshalYAML accepts a repository either as a mapping or as a plain URL string,
+// in which case the name is derived from the URL (e.g. "org/api" is named "api")
+func (r *Repository) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var url string
+	if err := unmarshal(&url); err == nil {
+		name, _ := git.ParseRepoURL(url)
+		*r = Repository{Name: strings.TrimSuffix(name, ".git"), URL: url}
+		return nil
+	}
+
+	type plain Repository
+	return unmarshal((*plain)(r))
+}
+
+// ExpandURL expands org/repo shorthand to an HTTPS URL on defaultHost.
+// defaultHost may include a scheme (e.g. http://git.internal); other URLs are returned unchanged.
+func ExpandURL(url, defaultHost string) string {
+	if !shorthandPattern.MatchString(url) {
+		return url
+	}
+	org, repo, _ := strings.Cut(url, "/")
+	if org == "." || org == ".." || repo == "." || repo == ".." {
+		return url
+	}
+
+	if defaultHost == "" {
+		defaultHost = DefaultShorthandHost
+	}
+	if !strings.Contains(defaultHost, "://") {
+		defaultHost = "https://" + defaultHost
+	}
+	return fmt.Sprintf("%s/%s/%s.git", strings.TrimRight(defaultHost, "/"), org, strings.TrimSuffix(repo, ".git"))
 }
 
 // BareRepoName returns the name for the bare repository directory (with .git suffix)
@@ -25,44 +150,564 @@ func (r Repository) BareRepoName() string {
 	return r.Name + ".git"
 }
 
-// Load reads and parses the devslot.yaml configuration file
+// RemoteName returns the remote devslot uses for the repository, origin unless configured
+func (r Repository) RemoteName() string {
+	if r.Remote == "" {
+		return git.DefaultRemote
+	}
+	return r.Remote
+}
+
+// BundlePath returns the path of the repository's git bundle within bundleDir.
+// Without an explicit bundle setting, <name>.bundle is used.
+func (r Repository) BundlePath(bundleDir string) string {
+	if r.Bundle == "" {
+		return filepath.Join(bundleDir, r.Name+".bundle")
+	}
+	if filepath.IsAbs(r.Bundle) {
+		return r.Bundle
+	}
+	return filepath.Join(bundleDir, r.Bundle)
+}
+
+// Groups returns the names of all repository groups, sorted
+func (c *Config) Groups() []string {
+	var groups []string
+	for _, repo := range c.Repositories {
+		for _, group := range repo.Groups {
+			if !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// ReposInGroups returns the repositories that belong to any of the groups, in
+// configuration order. Without groups, all repositories are returned.
+func (c *Config) ReposInGroups(groups ...string) ([]Repository, error) {
+	if len(groups) == 0 {
+		return c.Repositories, nil
+	}
+
+	known := c.Groups()
+	for _, group := range groups {
+		if !slices.Contains(known, group) {
+			return nil, errors.UnknownGroup(group, known)
+		}
+	}
+	return c.inGroups(groups), nil
+}
+
+// WithGroups returns a copy of the configuration that only contains the repositories
+// of the groups. Groups no longer in the configuration are ignored; without groups,
+// the configuration itself is returned.
+func (c *Config) WithGroups(groups []string) *Config {
+	if len(groups) == 0 {
+		return c
+	}
+	scoped := *c
+	scoped.Repositories = c.inGroups(groups)
+	return &scoped
+}
+
+// inGroups returns the repositories that belong to any of the groups
+func (c *Config) inGroups(groups []string) []Repository {
+	repos := []Repository{}
+	for _, repo := range c.Repositories {
+		if slices.ContainsFunc(repo.Groups, func(group string) bool { return slices.Contains(groups, group) }) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// WithRepositories returns a copy of the configuration that only contains the named
+// repositories. Names no longer in the configuration are ignored; without names,
+// the configuration itself is returned.
+func (c *Config) WithRepositories(names []string) *Config {
+	if len(names) == 0 {
+		return c
+	}
+	scoped := *c
+	scoped.Repositories = slices.DeleteFunc(slices.Clone(c.Repositories), func(repo Repository) bool {
+		return !slices.Contains(names, repo.Name)
+	})
+	return &scoped
+}
+
+// Template returns the slot template of the given name
+func (c *Config) Template(name string) (SlotTemplate, error) {
+	template, ok := c.SlotTemplates[name]
+	if !ok {
+		return SlotTemplate{}, errors.UnknownTemplate(name, c.TemplateNames())
+	}
+	return template, nil
+}
+
+// TemplateNames returns the names of the slot templates, sorted
+func (c *Config) TemplateNames() []string {
+	names := make([]string, 0, len(c.SlotTemplates))
+	for name := range c.SlotTemplates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ReposDir returns the directory holding the bare repositories, repos/ under rootPath by default
+func (c *Config) ReposDir(rootPath string) string {
+	if c == nil || c.ReposPath == "" {
+		return filepath.Join(rootPath, "repos")
+	}
+	return c.ReposPath
+}
+
+// SlotsDir returns the directory holding the slots, slots/ under rootPath by default
+func (c *Config) SlotsDir(rootPath string) string {
+	if c == nil || c.SlotsPath == "" {
+		return filepath.Join(rootPath, "slots")
+	}
+	return c.SlotsPath
+}
+
+// ArchivesDir returns the directory holding archived slots, archives/ under rootPath
+func (c *Config) ArchivesDir(rootPath string) string {
+	return filepath.Join(rootPath, "archives")
+}
+
+// MinGit returns the oldest git version doctor accepts without a warning,
+// git.DefaultMinVersion unless min_git_version is set
+func (c *Config) MinGit() (git.VersionNumber, error) {
+	if c == nil || c.MinGitVersion == "" {
+		return git.DefaultMinVersion, nil
+	}
+	v, err := git.ParseVersion(c.MinGitVersion)
+	if err != nil {
+		return git.VersionNumber{}, errors.InvalidMinGitVersion(c.MinGitVersion)
+	}
+	return v, nil
+}
+
+// InlineHooks returns the inline hook commands configured for a hook type
+func (c *Config) InlineHooks(hookType string) []string {
+	return c.Hooks[hookType]
+}
+
+// FileName is the name of the configuration file that marks a project root
+const FileName = "devslot.yaml"
+
+// AltFileName is accepted as the configuration file when FileName does not exist
+const AltFileName = "devslot.yml"
+
+// FindFile returns the configuration file in dir, preferring devslot.yaml over devslot.yml,
+// or an empty string when there is none. ambiguous reports that both exist.
+func FindFile(dir string) (path string, ambiguous bool) {
+	for _, name := range []string{FileName, AltFileName} {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err != nil || info.IsDir() {
+			continue
+		}
+		if path != "" {
+			return path, true
+		}
+		path = candidate
+	}
+	return path, false
+}
+
+// Load reads and parses the configuration file of the project (devslot.yaml or
+// devslot.yml), including the repositories of the files it extends
 func Load(rootPath string) (*Config, error) {
-	configPath := filepath.Join(rootPath, "devslot.yaml")
+	configPath, _ := FindFile(rootPath)
+	if configPath == "" {
+		configPath = filepath.Join(rootPath, FileName)
+	}
+	return LoadFile(rootPath, configPath)
+}
+
+// LoadFile is like Load but reads the configuration from configPath instead of devslot.yaml
+func LoadFile(rootPath, configPath string) (*Config, error) {
+	config, err := loadFile(rootPath, configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRepositories(config.Repositories); err != nil {
+		return nil, err
+	}
+	if err := validateTemplates(config); err != nil {
+		return nil, err
+	}
+	if err := validateGitEnv(config.GitEnv); err != nil {
+		return nil, err
+	}
+	if err := validateSeedFiles(config.SeedFiles); err != nil {
+		return nil, err
+	}
+	if _, err := config.MinGit(); err != nil {
+		return nil, err
+	}
+	if err := validateSharedLinks(config); err != nil {
+		return nil, err
+	}
+
+	config.ReposPath = resolveDir(rootPath, config.ReposPath)
+	config.SlotsPath = resolveDir(rootPath, config.SlotsPath)
+	if strings.ContainsAny(config.GitBinary, `/\`) {
+		config.GitBinary = resolveDir(rootPath, config.GitBinary)
+	}
+	if config.ReposDir(rootPath) == config.SlotsDir(rootPath) {
+		return nil, errors.SharedProjectDir(config.ReposDir(rootPath))
+	}
+
+	return config, nil
+}
+
+// resolveDir makes a configured directory absolute, relative to the project root
+func resolveDir(rootPath, dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(rootPath, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// loadFile parses a configuration file and merges in the repositories of the file it extends.
+// chain lists the files that led to this one, to detect cycles.
+func loadFile(rootPath, configPath string, chain []string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, errors.YAMLParseFailed(err)
+	config, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	source := configPath
+	if rel, err := filepath.Rel(rootPath, configPath); err == nil && !strings.HasPrefix(rel, "..") {
+		source = rel
+	}
+
+	// Expand shorthand so every command sees fully-qualified URLs
+	for i := range config.Repositories {
+		config.Repositories[i].URL = ExpandURL(config.Repositories[i].URL, config.DefaultHost)
+		config.Repositories[i].Source = source
+	}
+
+	if config.Extends == "" {
+		return config, nil
+	}
+
+	chain = append(chain, source)
+	if strings.Contains(config.Extends, "://") {
+		return nil, errors.ConfigExtendsFailed(config.Extends, source, fmt.Errorf("only local files can be extended"))
+	}
+	basePath := config.Extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(configPath), basePath)
+	}
+	baseSource := basePath
+	if rel, err := filepath.Rel(rootPath, basePath); err == nil && !strings.HasPrefix(rel, "..") {
+		baseSource = rel
+	}
+	if slices.Contains(chain, baseSource) {
+		return nil, errors.ConfigExtendsCycle(append(chain, baseSource))
+	}
+	if len(chain) >= MaxExtendsDepth {
+		return nil, errors.ConfigExtendsFailed(config.Extends, source, fmt.Errorf("more than %d files extend each other", MaxExtendsDepth))
+	}
+
+	base, err := loadFile(rootPath, basePath, chain)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.ConfigExtendsFailed(config.Extends, source, fmt.Errorf("file not found"))
+		}
+		return nil, err
+	}
+	config.Repositories = mergeRepositories(base.Repositories, config.Repositories)
+	config.SlotTemplates = mergeTemplates(base.SlotTemplates, config.SlotTemplates)
+	config.GitEnv = mergeSettings(base.GitEnv, config.GitEnv)
+	config.SeedFiles = mergeSettings(base.SeedFiles, config.SeedFiles)
+	config.SharedLinks = mergeSettings(base.SharedLinks, config.SharedLinks)
+
+	return config, nil
+}
+
+// mergeRepositories returns the base repositories followed by the local ones.
+// A local repository replaces the base repository of the same name in place.
+func mergeRepositories(base, local []Repository) []Repository {
+	merged := slices.Clone(base)
+	for _, repo := range local {
+		i := slices.IndexFunc(merged, func(r Repository) bool { return r.Name == repo.Name })
+		if i >= 0 {
+			merged[i] = repo
+		} else {
+			merged = append(merged, repo)
+		}
+	}
+	return merged
+}
+
+// mergeTemplates returns the base slot templates with the local ones added.
+// A local template replaces the base template of the same name.
+func mergeTemplates(base, local map[string]SlotTemplate) map[string]SlotTemplate {
+	if len(base) == 0 {
+		return local
 	}
+	merged := maps.Clone(base)
+	maps.Copy(merged, local)
+	return merged
+}
 
-	// Default version to 1 if not specified
-	if config.Version == 0 {
-		config.Version = 1
+// mergeSettings returns the base entries of a map setting, such as git_env, with the
+// local entries added. A local entry replaces the base entry of the same key.
+func mergeSettings[V any](base, local map[string]V) map[string]V {
+	if len(base) == 0 {
+		return local
 	}
+	merged := maps.Clone(base)
+	maps.Copy(merged, local)
+	return merged
+}
 
-	if config.Version != 1 {
-		return nil, errors.UnsupportedVersion(config.Version)
+// validateGitEnv checks that git_env only sets variables with usable names
+func validateGitEnv(env map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if name == "" || strings.ContainsAny(name, "= \t\n") {
+			return errors.InvalidGitEnv(name)
+		}
 	}
+	return nil
+}
 
-	return &config, nil
+// validateSeedFiles checks that seed files are copied from the project into the slot
+func validateSeedFiles(seeds map[string]string) error {
+	for _, source := range slices.Sorted(maps.Keys(seeds)) {
+		dest := seeds[source]
+		switch {
+		case source == "" || dest == "":
+			return errors.InvalidSeedFile(source, "both the source and the destination must be given")
+		case !filepath.IsLocal(strings.ReplaceAll(source, SeedRepoPlaceholder, "repo")):
+			return errors.InvalidSeedFile(source, "the source must be a path inside the project")
+		case !filepath.IsLocal(strings.ReplaceAll(dest, SeedRepoPlaceholder, "repo")):
+			return errors.InvalidSeedFile(source, fmt.Sprintf("the destination %s must be a path inside the slot", dest))
+		case strings.Contains(source, SeedRepoPlaceholder) && !strings.Contains(dest, SeedRepoPlaceholder):
+			return errors.InvalidSeedFile(source, fmt.Sprintf("the destination %s must contain %s when the source does", dest, SeedRepoPlaceholder))
+		}
+	}
+	return nil
+}
+
+// validateSharedLinks checks that shared links belong to configured repositories and
+// stay inside their worktrees
+func validateSharedLinks(config *Config) error {
+	for _, repoName := range slices.Sorted(maps.Keys(config.SharedLinks)) {
+		if !slices.ContainsFunc(config.Repositories, func(r Repository) bool { return r.Name == repoName }) {
+			return errors.InvalidSharedLink(repoName, "", "it is not a configured repository")
+		}
+		for _, path := range config.SharedLinks[repoName] {
+			if !filepath.IsLocal(path) || path == "." || strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")[0] == ".git" {
+				return errors.InvalidSharedLink(repoName, path, "the path must be inside the worktree")
+			}
+		}
+	}
+	return nil
+}
+
+// validateTemplates checks that slot templates only refer to configured repositories
+func validateTemplates(config *Config) error {
+	for _, name := range config.TemplateNames() {
+		template := config.SlotTemplates[name]
+		refs := slices.Clone(template.Repositories)
+		for repoName := range template.Branches {
+			refs = append(refs, repoName)
+		}
+		slices.Sort(refs)
+		for _, repoName := range refs {
+			if !slices.ContainsFunc(config.Repositories, func(r Repository) bool { return r.Name == repoName }) {
+				return errors.TemplateUnknownRepository(name, repoName)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRepositories checks repository names and rejects repositories that would share a bare repository.
+// A name may contain a single slash to nest it under a namespace directory (e.g. platform/api).
+func validateRepositories(repos []Repository) error {
+	seen := make(map[string]string)
+	for _, repo := range repos {
+		if err := validateRepositoryName(repo.Name); err != nil {
+			return err
+		}
+		if err := validateRemoteName(repo); err != nil {
+			return err
+		}
+		if repo.Branch != "" && !repo.SingleBranch {
+			return errors.InvalidRepositoryBranch(repo.Name, "branch is only used with single_branch: true")
+		}
+		if strings.HasPrefix(repo.Branch, "-") || strings.ContainsFunc(repo.Branch, func(r rune) bool {
+			return unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune(`~^:?*[\`, r)
+		}) {
+			return errors.InvalidRepositoryBranch(repo.Name, fmt.Sprintf("%q is not a valid branch name", repo.Branch))
+		}
+
+		if other, ok := seen[repo.BareRepoName()]; ok {
+			return errors.DuplicateRepository(repo.Name, other)
+		}
+		seen[repo.BareRepoName()] = repo.Name
+	}
+	return nil
+}
+
+// validateRepositoryName checks that a repository name is a single path segment or namespace/name,
+// so that its bare repository and worktrees stay inside the repos and slot directories
+func validateRepositoryName(name string) error {
+	if name == "" {
+		return errors.InvalidRepositoryName(name, "the name is empty")
+	}
+	if strings.Contains(name, "\\") {
+		return errors.InvalidRepositoryName(name, "backslashes are not allowed")
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return errors.InvalidRepositoryName(name, "control characters are not allowed")
+	}
+
+	segments := strings.Split(name, "/")
+	if len(segments) > 2 {
+		return errors.InvalidRepositoryName(name, "at most one slash is allowed")
+	}
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.InvalidRepositoryName(name, "each part must be a non-empty directory name")
+		}
+		if strings.HasPrefix(segment, ".") {
+			return errors.InvalidRepositoryName(name, "parts must not start with '.'")
+		}
+	}
+	return nil
+}
+
+// validateRemoteName rejects remote names that git would take for an option or that
+// can't be part of a ref name
+func validateRemoteName(repo Repository) error {
+	if repo.Remote == "" {
+		return nil
+	}
+	if strings.HasPrefix(repo.Remote, "-") || strings.ContainsFunc(repo.Remote, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune(`~^:?*[\`, r)
+	}) {
+		return errors.InvalidRemoteName(repo.Name, repo.Remote)
+	}
+	return nil
+}
+
+// DefaultMaxSearchDepth is the default number of directories FindProjectRoot inspects
+// before giving up. It can be overridden with DEVSLOT_MAX_SEARCH_DEPTH.
+const DefaultMaxSearchDepth = 64
+
+// Finder finds project roots. It remembers the start paths for which no project root
+// was found, so that repeated lookups within a single command don't re-walk the
+// filesystem; each command uses its own Finder. The zero value is ready to use.
+type Finder struct {
+	mu     sync.Mutex
+	misses map[string]bool
 }
 
 // FindProjectRoot searches for the project root containing devslot.yaml
+func (f *Finder) FindProjectRoot(startPath string) (string, error) {
+	startPath = filepath.Clean(startPath)
+
+	f.mu.Lock()
+	missed := f.misses[startPath]
+	f.mu.Unlock()
+	if missed {
+		return "", errors.ConfigNotFound(startPath)
+	}
+
+	root, err := findProjectRoot(startPath, maxSearchDepth())
+	if err != nil {
+		f.mu.Lock()
+		if f.misses == nil {
+			f.misses = map[string]bool{}
+		}
+		f.misses[startPath] = true
+		f.mu.Unlock()
+	}
+	return root, err
+}
+
+// FindProjectRoot searches for the project root containing devslot.yaml. Nothing is cached.
 func FindProjectRoot(startPath string) (string, error) {
+	return findProjectRoot(startPath, maxSearchDepth())
+}
+
+// ProjectRootOf returns the project root of an explicitly given configuration file,
+// which is the directory containing it
+func ProjectRootOf(configPath string) (string, error) {
+	configPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.ConfigFileNotFound(configPath)
+		}
+		return "", errors.ConfigFileUnreadable(configPath, err)
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		return "", errors.ConfigFileUnreadable(configPath, fmt.Errorf("is a directory"))
+	}
+
+	return filepath.Dir(configPath), nil
+}
+
+func findProjectRoot(startPath string, maxDepth int) (string, error) {
+	startPath = filepath.Clean(startPath)
+
+	// The search follows the lexical parents, so it always ends at the filesystem root.
+	// Symlinks can make several of them the same directory, e.g. in loop/self/self
+	// where self links to loop; each resolved directory is inspected and counted once.
+	visited := map[string]bool{}
 	currentPath := startPath
-	for {
-		configPath := filepath.Join(currentPath, "devslot.yaml")
-		if _, err := os.Stat(configPath); err == nil {
-			return currentPath, nil
+	for depth := 0; depth < maxDepth; {
+		resolved, err := filepath.EvalSymlinks(currentPath)
+		if err != nil {
+			resolved = currentPath
+		}
+		if !visited[resolved] {
+			visited[resolved] = true
+			depth++
+			if configPath, _ := FindFile(currentPath); configPath != "" {
+				return currentPath, nil
+			}
 		}
 
 		parent := filepath.Dir(currentPath)
 		if parent == currentPath {
-			return "", errors.ConfigNotFound()
+			break
 		}
 		currentPath = parent
 	}
+
+	return "", errors.ConfigNotFound(startPath)
+}
+
+// maxSearchDepth returns the configured upper bound for the project root search
+func maxSearchDepth() int {
+	if value := os.Getenv("DEVSLOT_MAX_SEARCH_DEPTH"); value != "" {
+		if depth, err := strconv.Atoi(value); err == nil && depth > 0 {
+			return depth
+		}
+	}
+	return DefaultMaxSearchDepth
 }
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/yammerjp/devslot/internal/git"
 	"github.com/yammerjp/devslot/internal/testutil"
 )
 
@@ -140,3 +145,922 @@ func TestRepository(t *testing.T) {
 		t.Errorf("Repository.URL = %v, want https://github.com/test/repo.git", repo.URL)
 	}
 }
+
+func TestRepository_BundlePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		bundle string
+		want   string
+	}{
+		{name: "default", bundle: "", want: "/bundles/app.bundle"},
+		{name: "relative", bundle: "nested/app-v2.bundle", want: "/bundles/nested/app-v2.bundle"},
+		{name: "absolute", bundle: "/mnt/usb/app.bundle", want: "/mnt/usb/app.bundle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := Repository{Name: "app", Bundle: tt.bundle}
+			if got := repo.BundlePath("/bundles"); got != tt.want {
+				t.Errorf("BundlePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_RepositoryNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		repos       []string
+		errContains string
+	}{
+		{name: "namespaced names", repos: []string{"platform/api", "billing/api", "api"}},
+		{name: "duplicate names", repos: []string{"api", "web", "api"}, errContains: "duplicate repository api"},
+		{name: "too many slashes", repos: []string{"org/team/api"}, errContains: "at most one slash"},
+		{name: "parent directory", repos: []string{"../api"}, errContains: "non-empty directory name"},
+		{name: "trailing slash", repos: []string{"platform/"}, errContains: "non-empty directory name"},
+		{name: "empty name", repos: []string{""}, errContains: "the name is empty"},
+		{name: "escaping the repos directory", repos: []string{"../../outside"}, errContains: "at most one slash"},
+		{name: "absolute path", repos: []string{"/api"}, errContains: "non-empty directory name"},
+		{name: "leading dot", repos: []string{".hidden"}, errContains: "must not start with '.'"},
+		{name: "leading dot in namespace", repos: []string{"platform/.git"}, errContains: "must not start with '.'"},
+		{name: "control character", repos: []string{"api\x1b[31m"}, errContains: "control characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			content := "version: 1\nrepositories:\n"
+			for _, name := range tt.repos {
+				content += fmt.Sprintf("  - name: %q\n    url: https://example.com/%s.git\n", name, name)
+			}
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), content)
+
+			cfg, err := Load(tempDir)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if len(cfg.Repositories) != len(tt.repos) {
+					t.Errorf("Load() returned %d repositories, want %d", len(cfg.Repositories), len(tt.repos))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Load() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestLoad_ShorthandURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantNames []string
+		wantURLs  []string
+	}{
+		{
+			name: "shorthand and full URLs",
+			content: `version: 1
+repositories:
+  - name: api
+    url: platform/api
+  - name: web
+    url: https://gitlab.com/platform/web.git
+  - name: local
+    url: ./local/repo
+  - name: ssh
+    url: git@github.com:platform/ssh.git
+`,
+			wantNames: []string{"api", "web", "local", "ssh"},
+			wantURLs: []string{
+				"https://github.com/platform/api.git",
+				"https://gitlab.com/platform/web.git",
+				"./local/repo",
+				"git@github.com:platform/ssh.git",
+			},
+		},
+		{
+			name: "custom default_host and string entries",
+			content: `version: 1
+default_host: ghe.example.com
+repositories:
+  - platform/api
+  - https://github.com/platform/web.git
+  - name: tools
+    url: platform/tools.git
+`,
+			wantNames: []string{"api", "web", "tools"},
+			wantURLs: []string{
+				"https://ghe.example.com/platform/api.git",
+				"https://github.com/platform/web.git",
+				"https://ghe.example.com/platform/tools.git",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), tt.content)
+
+			cfg, err := Load(tempDir)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(cfg.Repositories) != len(tt.wantURLs) {
+				t.Fatalf("Load() returned %d repositories, want %d", len(cfg.Repositories), len(tt.wantURLs))
+			}
+			for i, repo := range cfg.Repositories {
+				if repo.Name != tt.wantNames[i] {
+					t.Errorf("Repositories[%d].Name = %q, want %q", i, repo.Name, tt.wantNames[i])
+				}
+				if repo.URL != tt.wantURLs[i] {
+					t.Errorf("Repositories[%d].URL = %q, want %q", i, repo.URL, tt.wantURLs[i])
+				}
+				if name, _ := git.ParseRepoURL(repo.URL); name == "" {
+					t.Errorf("ParseRepoURL(%q) returned an empty name", repo.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_InlineHooks(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+repositories: []
+hooks:
+  post-create:
+    - direnv allow
+    - make bootstrap
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := cfg.InlineHooks("post-create")
+	if len(got) != 2 || got[0] != "direnv allow" || got[1] != "make bootstrap" {
+		t.Errorf("InlineHooks(post-create) = %v, want [direnv allow make bootstrap]", got)
+	}
+	if got := cfg.InlineHooks("pre-destroy"); len(got) != 0 {
+		t.Errorf("InlineHooks(pre-destroy) = %v, want empty", got)
+	}
+}
+
+func TestLoad_BranchPrefix(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+branch_prefix: "team/{slot}"
+repositories: []
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.BranchPrefix != "team/{slot}" {
+		t.Errorf("BranchPrefix = %q, want %q", cfg.BranchPrefix, "team/{slot}")
+	}
+}
+
+func TestFindProjectRoot_SymlinkedDirectories(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	projectRoot := filepath.Join(tempDir, "project")
+	testutil.CreateFile(t, filepath.Join(projectRoot, "devslot.yaml"), "version: 1\nrepositories: []")
+
+	// A chain of symlinks that all end up inside the project
+	target := filepath.Join(projectRoot, "src", "app")
+	if err := os.MkdirAll(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	link1 := filepath.Join(projectRoot, "link1")
+	link2 := filepath.Join(projectRoot, "link2")
+	if err := os.Symlink(target, link1); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(link1, link2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A symlink pointing back at its parent directory forms a cycle
+	loopDir := filepath.Join(tempDir, "loop")
+	if err := os.MkdirAll(loopDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(loopDir, filepath.Join(loopDir, "self")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("through symlink chain", func(t *testing.T) {
+		got, err := FindProjectRoot(link2)
+		if err != nil {
+			t.Fatalf("FindProjectRoot() error = %v", err)
+		}
+		if got != projectRoot {
+			t.Errorf("FindProjectRoot() = %v, want %v", got, projectRoot)
+		}
+	})
+
+	t.Run("symlink cycle terminates", func(t *testing.T) {
+		start := filepath.Join(loopDir, "self", "self", "self", "self")
+		if _, err := FindProjectRoot(start); err == nil {
+			t.Error("FindProjectRoot() expected error for directory outside a project")
+		}
+	})
+
+	t.Run("symlink to a parent directory", func(t *testing.T) {
+		// src/current links to src, so the first parent is the directory already inspected
+		src := filepath.Join(projectRoot, "src")
+		if err := os.Symlink(src, filepath.Join(src, "current")); err != nil {
+			t.Fatal(err)
+		}
+		got, err := FindProjectRoot(filepath.Join(src, "current"))
+		if err != nil {
+			t.Fatalf("FindProjectRoot() error = %v", err)
+		}
+		if got != projectRoot {
+			t.Errorf("FindProjectRoot() = %v, want %v", got, projectRoot)
+		}
+	})
+
+	t.Run("symlink cycle counts each directory once", func(t *testing.T) {
+		cycleRoot := filepath.Join(tempDir, "cycle-project")
+		testutil.CreateFile(t, filepath.Join(cycleRoot, "devslot.yaml"), "version: 1\nrepositories: []")
+		loop := filepath.Join(cycleRoot, "loop")
+		if err := os.MkdirAll(loop, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(loop, filepath.Join(loop, "self")); err != nil {
+			t.Fatal(err)
+		}
+
+		// Ten lexical parents resolve to loop; only loop and the project root are inspected
+		start := filepath.Join(append([]string{loop}, slices.Repeat([]string{"self"}, 10)...)...)
+		got, err := findProjectRoot(start, 2)
+		if err != nil {
+			t.Fatalf("findProjectRoot() error = %v", err)
+		}
+		if got != cycleRoot {
+			t.Errorf("findProjectRoot() = %v, want %v", got, cycleRoot)
+		}
+	})
+}
+
+func TestFindProjectRoot_MaxDepth(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories: []")
+	nested := filepath.Join(tempDir, "a", "b", "c", "d")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := findProjectRoot(nested, 3); err == nil {
+		t.Error("findProjectRoot() expected error when the root is beyond the max depth")
+	}
+
+	got, err := findProjectRoot(nested, 5)
+	if err != nil {
+		t.Fatalf("findProjectRoot() error = %v", err)
+	}
+	if got != tempDir {
+		t.Errorf("findProjectRoot() = %v, want %v", got, tempDir)
+	}
+
+	t.Setenv("DEVSLOT_MAX_SEARCH_DEPTH", "2")
+	if got := maxSearchDepth(); got != 2 {
+		t.Errorf("maxSearchDepth() = %d, want 2", got)
+	}
+}
+
+func TestFinder_NegativeCache(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+
+	var finder Finder
+	if _, err := finder.FindProjectRoot(tempDir); err == nil {
+		t.Fatal("FindProjectRoot() expected error before devslot.yaml exists")
+	}
+
+	// The negative result is cached for the rest of the command
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories: []")
+	if _, err := finder.FindProjectRoot(tempDir); err == nil {
+		t.Error("FindProjectRoot() expected cached negative result")
+	}
+
+	// Other commands and uncached lookups find the project
+	for name, find := range map[string]func(string) (string, error){
+		"new Finder":      new(Finder).FindProjectRoot,
+		"FindProjectRoot": FindProjectRoot,
+	} {
+		got, err := find(tempDir)
+		if err != nil {
+			t.Fatalf("%s: FindProjectRoot() error = %v", name, err)
+		}
+		if got != tempDir {
+			t.Errorf("%s: FindProjectRoot() = %v, want %v", name, got, tempDir)
+		}
+	}
+}
+
+func TestLoad_Extends(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantRepos []Repository
+		wantErr   string
+	}{
+		{
+			name: "simple include",
+			files: map[string]string{
+				"shared/core.yaml": `version: 1
+repositories:
+  - name: api
+    url: org/api
+  - name: web
+    url: org/web
+`,
+				"devslot.yaml": `version: 1
+extends: shared/core.yaml
+repositories:
+  - name: tools
+    url: org/tools
+`,
+			},
+			wantRepos: []Repository{
+				{Name: "api", URL: "https://github.com/org/api.git", Source: filepath.Join("shared", "core.yaml")},
+				{Name: "web", URL: "https://github.com/org/web.git", Source: filepath.Join("shared", "core.yaml")},
+				{Name: "tools", URL: "https://github.com/org/tools.git", Source: "devslot.yaml"},
+			},
+		},
+		{
+			name: "local entries override included ones",
+			files: map[string]string{
+				"base.yaml": `repositories:
+  - name: api
+    url: org/api
+  - name: web
+    url: org/web
+`,
+				"devslot.yaml": `extends: base.yaml
+repositories:
+  - name: api
+    url: fork/api
+    clone_filter: blob:none
+`,
+			},
+			wantRepos: []Repository{
+				{Name: "api", URL: "https://github.com/fork/api.git", CloneFilter: "blob:none", Source: "devslot.yaml"},
+				{Name: "web", URL: "https://github.com/org/web.git", Source: "base.yaml"},
+			},
+		},
+		{
+			name: "nested includes are relative to the including file",
+			files: map[string]string{
+				"shared/base.yaml": "repositories:\n  - org/api\n",
+				"shared/team.yaml": "extends: base.yaml\nrepositories:\n  - org/web\n",
+				"devslot.yaml":     "extends: shared/team.yaml\nrepositories: []\n",
+			},
+			wantRepos: []Repository{
+				{Name: "api", URL: "https://github.com/org/api.git", Source: filepath.Join("shared", "base.yaml")},
+				{Name: "web", URL: "https://github.com/org/web.git", Source: filepath.Join("shared", "team.yaml")},
+			},
+		},
+		{
+			name:    "missing include file",
+			files:   map[string]string{"devslot.yaml": "extends: nope.yaml\nrepositories: []\n"},
+			wantErr: "cannot extend nope.yaml from devslot.yaml: file not found",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.yaml":       "extends: b.yaml\n",
+				"b.yaml":       "extends: a.yaml\n",
+				"devslot.yaml": "extends: a.yaml\nrepositories: []\n",
+			},
+			wantErr: "cycle: devslot.yaml -> a.yaml -> b.yaml -> a.yaml",
+		},
+		{
+			name:    "URLs are not supported",
+			files:   map[string]string{"devslot.yaml": "extends: https://example.com/devslot.yaml\n"},
+			wantErr: "only local files can be extended",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			for name, content := range tt.files {
+				testutil.CreateFile(t, filepath.Join(tempDir, name), content)
+			}
+
+			cfg, err := Load(tempDir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Repositories, tt.wantRepos) {
+				t.Errorf("Repositories = %+v, want %+v", cfg.Repositories, tt.wantRepos)
+			}
+		})
+	}
+}
+
+func TestLoad_ExtendsDepth(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "extends: c1.yaml\n")
+	for i := 1; i <= MaxExtendsDepth+1; i++ {
+		testutil.CreateFile(t, filepath.Join(tempDir, fmt.Sprintf("c%d.yaml", i)), fmt.Sprintf("extends: c%d.yaml\n", i+1))
+	}
+
+	_, err := Load(tempDir)
+	if err == nil || !strings.Contains(err.Error(), "more than 8 files extend each other") {
+		t.Errorf("Load() error = %v, want depth limit", err)
+	}
+}
+
+func TestConfig_ReposInGroups(t *testing.T) {
+	cfg := &Config{Repositories: []Repository{
+		{Name: "web", Groups: []string{"frontend"}},
+		{Name: "api", Groups: []string{"core"}},
+		{Name: "lib", Groups: []string{"core", "frontend"}},
+		{Name: "docs"},
+	}}
+
+	names := func(repos []Repository) []string {
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		groups  []string
+		want    []string
+		wantErr string
+	}{
+		{groups: nil, want: []string{"web", "api", "lib", "docs"}},
+		{groups: []string{"core"}, want: []string{"api", "lib"}},
+		{groups: []string{"frontend", "core"}, want: []string{"web", "api", "lib"}},
+		{groups: []string{"backend"}, wantErr: "unknown group backend"},
+	}
+	for _, tt := range tests {
+		repos, err := cfg.ReposInGroups(tt.groups...)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "Known groups: core, frontend") {
+				t.Errorf("ReposInGroups(%v) error = %v, want %q", tt.groups, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ReposInGroups(%v) error = %v", tt.groups, err)
+		}
+		if got := names(repos); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReposInGroups(%v) = %v, want %v", tt.groups, got, tt.want)
+		}
+	}
+
+	// WithGroups ignores groups that no longer exist
+	if got := names(cfg.WithGroups([]string{"core", "gone"}).Repositories); !reflect.DeepEqual(got, []string{"api", "lib"}) {
+		t.Errorf("WithGroups() = %v", got)
+	}
+}
+
+func TestLoad_Directories(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	elsewhere := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+repos_dir: cache/repos
+slots_dir: `+elsewhere+`
+repositories: []
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := cfg.ReposDir(tempDir), filepath.Join(tempDir, "cache", "repos"); got != want {
+		t.Errorf("ReposDir() = %q, want %q", got, want)
+	}
+	if got := cfg.SlotsDir(tempDir); got != elsewhere {
+		t.Errorf("SlotsDir() = %q, want %q", got, elsewhere)
+	}
+
+	// Defaults
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories: []\n")
+	cfg, err = Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := cfg.ReposDir(tempDir), filepath.Join(tempDir, "repos"); got != want {
+		t.Errorf("ReposDir() = %q, want %q", got, want)
+	}
+	if got, want := cfg.SlotsDir(tempDir), filepath.Join(tempDir, "slots"); got != want {
+		t.Errorf("SlotsDir() = %q, want %q", got, want)
+	}
+
+	// Both settings must not name the same directory
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nslots_dir: ./repos\nrepositories: []\n")
+	if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), "repos_dir and slots_dir") {
+		t.Errorf("Load() error = %v, want shared directory error", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "migrate", "*.yaml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migration fixtures: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			v1 := testutil.ReadFile(t, file)
+			v2, err := Migrate([]byte(v1))
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+
+			v1Root, v2Root := testutil.TempDir(t), testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(v1Root, "devslot.yaml"), v1)
+			testutil.CreateFile(t, filepath.Join(v2Root, "devslot.yaml"), string(v2))
+			before, err := Load(v1Root)
+			if err != nil {
+				t.Fatalf("Load(v1) error = %v", err)
+			}
+			after, err := Load(v2Root)
+			if err != nil {
+				t.Fatalf("Load(v2) error = %v\n%s", err, v2)
+			}
+			if after.Version != LatestVersion {
+				t.Errorf("migrated Version = %d, want %d", after.Version, LatestVersion)
+			}
+
+			// Directories resolve against the project root, so compare them relative to it
+			for _, cfg := range []struct {
+				cfg  *Config
+				root string
+			}{{before, v1Root}, {after, v2Root}} {
+				cfg.cfg.Version = 0
+				cfg.cfg.ReposPath, _ = filepath.Rel(cfg.root, cfg.cfg.ReposDir(cfg.root))
+				cfg.cfg.SlotsPath, _ = filepath.Rel(cfg.root, cfg.cfg.SlotsDir(cfg.root))
+			}
+			if !reflect.DeepEqual(before, after) {
+				t.Errorf("migrated configuration differs\nv1: %+v\nv2: %+v\n%s", before, after, v2)
+			}
+
+			if _, err := Migrate(v2); err == nil {
+				t.Error("Migrate() of a version 2 file should fail")
+			}
+		})
+	}
+}
+
+func TestMigrate_Layout(t *testing.T) {
+	v2, err := Migrate([]byte(testutil.ReadFile(t, filepath.Join("testdata", "migrate", "full.yaml"))))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# devslot configuration file\nversion: 2\n",
+		"defaults:\n  # Shorthand URLs expand to this host\n  host: github.example.com\n  branch_prefix: \"{user}/{date:20060102}-{slot}\" # team convention\n",
+		"directories:\n  repos: /mnt/cache/repos\n  slots: ../work\n",
+		"  # Everyone works on these\n  - name: api\n",
+		"docker compose down # stop services",
+	} {
+		if !strings.Contains(string(v2), want) {
+			t.Errorf("expected %q in migrated file:\n%s", want, v2)
+		}
+	}
+	if strings.Contains(string(v2), "default_host") || strings.Contains(string(v2), "repos_dir") {
+		t.Errorf("version 1 keys left in migrated file:\n%s", v2)
+	}
+}
+
+func TestLoad_Version2(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 2
+defaults:
+  host: git.example.com
+  branch_prefix: "team/{slot}"
+directories:
+  slots: work
+repositories:
+  - example/app
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Version != 2 || cfg.BranchPrefix != "team/{slot}" || cfg.SlotsDir(tempDir) != filepath.Join(tempDir, "work") {
+		t.Errorf("unexpected configuration: %+v", cfg)
+	}
+	if got, want := cfg.Repositories[0].URL, "https://git.example.com/example/app.git"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+
+	// Version 1 keys are rejected rather than silently ignored
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 2\nbranch_prefix: x\nrepositories: []\n")
+	if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), "defaults.branch_prefix") {
+		t.Errorf("Load() error = %v, want renamed key error", err)
+	}
+
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 3\nrepositories: []\n")
+	if _, err := Load(tempDir); err == nil {
+		t.Error("Load() should reject version 3")
+	}
+}
+
+func TestFindFile_AlternateName(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		wantFile      string
+		wantAmbiguous bool
+	}{
+		{name: "yaml only", files: []string{"devslot.yaml"}, wantFile: "devslot.yaml"},
+		{name: "yml only", files: []string{"devslot.yml"}, wantFile: "devslot.yml"},
+		{name: "both", files: []string{"devslot.yaml", "devslot.yml"}, wantFile: "devslot.yaml", wantAmbiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := testutil.TempDir(t)
+			for _, file := range tt.files {
+				testutil.CreateFile(t, filepath.Join(projectRoot, file), "version: 1\nrepositories:\n  - example/"+strings.ReplaceAll(file, ".", "-")+"\n")
+			}
+			subDir := filepath.Join(projectRoot, "slots", "dev", "app")
+			if err := os.MkdirAll(subDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			path, ambiguous := FindFile(projectRoot)
+			if path != filepath.Join(projectRoot, tt.wantFile) || ambiguous != tt.wantAmbiguous {
+				t.Errorf("FindFile() = %q, %v, want %q, %v", path, ambiguous, tt.wantFile, tt.wantAmbiguous)
+			}
+
+			root, err := FindProjectRoot(subDir)
+			if err != nil {
+				t.Fatalf("FindProjectRoot() error = %v", err)
+			}
+			if root != projectRoot {
+				t.Errorf("FindProjectRoot() = %q, want %q", root, projectRoot)
+			}
+
+			cfg, err := Load(root)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if want := strings.ReplaceAll(tt.wantFile, ".", "-"); cfg.Repositories[0].Name != want {
+				t.Errorf("Load() read repository %q, want %q", cfg.Repositories[0].Name, want)
+			}
+		})
+	}
+}
+
+func TestLoad_SlotTemplates(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "base.yaml"), `version: 1
+repositories:
+  - name: docs
+    url: https://github.com/example/docs.git
+slot_templates:
+  docs:
+    repositories: [docs]
+`)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+extends: base.yaml
+repositories:
+  - name: api
+    url: https://github.com/example/api.git
+  - name: web
+    url: https://github.com/example/web.git
+slot_templates:
+  release:
+    description: Release preparation
+    repositories: [api, web]
+    branches:
+      api:
+        branch: release/2.x
+      web:
+        base: v2.0.0
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.TemplateNames(); !reflect.DeepEqual(got, []string{"docs", "release"}) {
+		t.Errorf("TemplateNames() = %v, want templates of both files", got)
+	}
+	release, err := cfg.Template("release")
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+	want := SlotTemplate{
+		Description:  "Release preparation",
+		Repositories: []string{"api", "web"},
+		Branches: map[string]BranchOverride{
+			"api": {Branch: "release/2.x"},
+			"web": {Base: "v2.0.0"},
+		},
+	}
+	if !reflect.DeepEqual(release, want) {
+		t.Errorf("Template() = %+v, want %+v", release, want)
+	}
+	if _, err := cfg.Template("hotfix"); err == nil || !strings.Contains(err.Error(), "unknown template hotfix") || !strings.Contains(err.Error(), "Known templates: docs, release") {
+		t.Errorf("Template() error = %v, want an unknown template error", err)
+	}
+	if got := cfg.WithRepositories([]string{"web", "gone"}).Repositories; len(got) != 1 || got[0].Name != "web" {
+		t.Errorf("WithRepositories() = %v", got)
+	}
+
+	// Templates referring to repositories that are not configured are rejected
+	for _, template := range []string{"repositories: [api, mobile]", "branches:\n      mobile:\n        branch: main"} {
+		testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+repositories:
+  - name: api
+    url: https://github.com/example/api.git
+slot_templates:
+  release:
+    `+template+`
+`)
+		_, err := Load(tempDir)
+		if err == nil || !strings.Contains(err.Error(), "invalid slot template release") || !strings.Contains(err.Error(), "repository mobile is not in devslot.yaml") {
+			t.Errorf("Load() with %q error = %v, want an unknown repository error", template, err)
+		}
+	}
+}
+
+func TestLoad_GitEnv(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	testutil.CreateFile(t, filepath.Join(tempDir, "base.yaml"), `version: 1
+repositories: []
+git_env:
+  HTTPS_PROXY: http://proxy.corp:8080
+  NO_PROXY: localhost
+`)
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+extends: base.yaml
+repositories: []
+git_env:
+  NO_PROXY: localhost,.corp
+  GIT_SSH_COMMAND: ssh -i ~/.ssh/work
+`)
+
+	cfg, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{
+		"HTTPS_PROXY":     "http://proxy.corp:8080",
+		"NO_PROXY":        "localhost,.corp",
+		"GIT_SSH_COMMAND": "ssh -i ~/.ssh/work",
+	}
+	if !reflect.DeepEqual(cfg.GitEnv, want) {
+		t.Errorf("GitEnv = %v, want %v", cfg.GitEnv, want)
+	}
+
+	testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), `version: 1
+repositories: []
+git_env:
+  "HTTPS_PROXY=": http://proxy.corp:8080
+`)
+	if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), "invalid git_env") {
+		t.Errorf("Load() error = %v, want an invalid git_env error", err)
+	}
+}
+
+func TestLoad_InvalidSeedFiles(t *testing.T) {
+	tests := []struct {
+		seed string
+		want string
+	}{
+		{`../secrets.env: .env`, "the source must be a path inside the project"},
+		{`seeds/env: ../../.env`, "must be a path inside the slot"},
+		{`seeds/{repo}.env: .env`, "must contain {repo} when the source does"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.seed, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories: []\nseed_files:\n  "+tt.seed+"\n")
+			if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidSharedLinks(t *testing.T) {
+	tests := []struct {
+		links string
+		want  string
+	}{
+		{"other: [.cache]", "it is not a configured repository"},
+		{"app: [../cache]", "the path must be inside the worktree"},
+		{"app: [.git/objects]", "the path must be inside the worktree"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.links, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories:\n  - name: app\n    url: https://github.com/example/app.git\nshared_links:\n  "+tt.links+"\n")
+			if _, err := Load(tempDir); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_RepositoryBranch(t *testing.T) {
+	tests := []struct {
+		settings string
+		want     string // Expected error; empty when the configuration is valid
+	}{
+		{"single_branch: true", ""},
+		{"single_branch: true\n    branch: develop", ""},
+		{"branch: develop", "branch is only used with single_branch: true"},
+		{"single_branch: true\n    branch: --all", "is not a valid branch name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.settings, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nrepositories:\n  - name: app\n    url: https://github.com/example/app.git\n    "+tt.settings+"\n")
+			_, err := Load(tempDir)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_GitBinary(t *testing.T) {
+	tempDir := testutil.TempDir(t)
+	absolute := filepath.Join(testutil.TempDir(t), "git", "bin", "git")
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"git2", "git2"},
+		{absolute, absolute},
+		{"tools/git/bin/git", filepath.Join(tempDir, "tools", "git", "bin", "git")},
+	}
+	for _, tt := range tests {
+		testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\ngit_binary: "+tt.value+"\nrepositories: []\n")
+		cfg, err := Load(tempDir)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.GitBinary != tt.want {
+			t.Errorf("git_binary %s: GitBinary = %q, want %q", tt.value, cfg.GitBinary, tt.want)
+		}
+	}
+}
+
+func TestLoad_MinGitVersion(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // Expected error; empty when the configuration is valid
+	}{
+		{"\"2.30\"", ""},
+		{"2.39.2", ""},
+		{"latest", "is not a git version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			tempDir := testutil.TempDir(t)
+			testutil.CreateFile(t, filepath.Join(tempDir, "devslot.yaml"), "version: 1\nmin_git_version: "+tt.value+"\nrepositories: []\n")
+			cfg, err := Load(tempDir)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if min, err := cfg.MinGit(); err != nil || min.Major != 2 {
+					t.Errorf("MinGit() = %v, %v", min, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	var cfg *Config
+	if min, _ := cfg.MinGit(); min != git.DefaultMinVersion {
+		t.Errorf("MinGit() = %v without a configuration, want %v", min, git.DefaultMinVersion)
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+// AddRepositories appends repositories to the repositories list of a single
+// configuration file and returns the new file and the repositories it added.
+// A repository already listed with the same URL is skipped; one listed under
+// the same name with another URL is an error. Repositories included with
+// extends are not considered.
+//
+// When the file has a non-empty block list of repositories, the new entries
+// are appended to it and the rest of the file is kept as written. Otherwise the
+// file is re-encoded, keeping comments as far as the YAML library tracks them.
+func AddRepositories(data []byte, repos []Repository) ([]byte, []Repository, error) {
+	config, err := parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added []Repository
+	for _, repo := range repos {
+		i := slices.IndexFunc(config.Repositories, func(r Repository) bool { return r.Name == repo.Name })
+		if i < 0 {
+			added = append(added, repo)
+			continue
+		}
+		if existing := config.Repositories[i]; !git.SameURL(existing.URL, repo.URL) {
+			return nil, nil, errors.RepositoryExists(repo.Name, existing.URL)
+		}
+	}
+	if err := validateRepositories(append(slices.Clone(config.Repositories), added...)); err != nil {
+		return nil, nil, err
+	}
+	if len(added) == 0 {
+		return data, nil, nil
+	}
+
+	out, err := appendToRepositoryList(data, added)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, added, nil
+}
+
+// appendToRepositoryList appends to a block list of repositories in place, or
+// re-encodes the file when there is none
+func appendToRepositoryList(data []byte, repos []Repository) ([]byte, error) {
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, errors.YAMLParseFailed(err)
+	}
+	path, err := yaml.PathString("$.repositories")
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := path.FilterFile(file)
+	if seq, ok := node.(*ast.SequenceNode); err == nil && ok && !seq.IsFlowStyle && len(seq.Values) > 0 {
+		items, err := yaml.MarshalWithOptions(repositoryItems(repos), yaml.IndentSequence(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode repositories: %w", err)
+		}
+		if err := path.MergeFromReader(file, bytes.NewReader(items)); err != nil {
+			return nil, fmt.Errorf("failed to add repositories: %w", err)
+		}
+		return []byte(strings.TrimRight(file.String(), "\n") + "\n"), nil
+	}
+
+	comments := yaml.CommentMap{}
+	var doc yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(data, &doc, yaml.UseOrderedMap(), yaml.CommentToMap(comments)); err != nil {
+		return nil, errors.YAMLParseFailed(err)
+	}
+	i := slices.IndexFunc(doc, func(item yaml.MapItem) bool { return item.Key == "repositories" })
+	if i < 0 {
+		doc = append(doc, yaml.MapItem{Key: "repositories"})
+		i = len(doc) - 1
+	}
+	existing, _ := doc[i].Value.([]any)
+	doc[i].Value = append(existing, repositoryItems(repos)...)
+
+	out, err := yaml.MarshalWithOptions(doc,
+		yaml.WithComment(comments), yaml.IndentSequence(true), yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return out, nil
+}
+
+// repositoryItems converts repositories to list entries with their name and URL
+func repositoryItems(repos []Repository) []any {
+	items := make([]any, 0, len(repos))
+	for _, repo := range repos {
+		items = append(items, yaml.MapSlice{{Key: "name", Value: repo.Name}, {Key: "url", Value: repo.URL}})
+	}
+	return items
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+func TestAddRepositories(t *testing.T) {
+	api := Repository{Name: "api", URL: "https://github.com/example/api.git"}
+	web := Repository{Name: "web", URL: "git@github.com:example/web.git"}
+
+	tests := []struct {
+		name      string
+		input     string
+		repos     []Repository
+		want      string
+		wantAdded []string
+	}{
+		{
+			name: "block list keeps the file as written",
+			input: `# project
+version: 2
+repositories:
+  # the backend
+  - name: api # main service
+    url: "https://github.com/example/api.git"
+hooks:
+  post_create: ["make setup"]
+`,
+			repos: []Repository{api, web},
+			want: `# project
+version: 2
+repositories:
+  # the backend
+  - name: api # main service
+    url: "https://github.com/example/api.git"
+  - name: web
+    url: git@github.com:example/web.git
+hooks:
+  post_create: ["make setup"]
+`,
+			wantAdded: []string{"web"},
+		},
+		{
+			name:      "empty flow list",
+			input:     "version: 1\nrepositories: []\n",
+			repos:     []Repository{web},
+			want:      "version: 1\nrepositories:\n  - name: web\n    url: git@github.com:example/web.git\n",
+			wantAdded: []string{"web"},
+		},
+		{
+			name:      "no repositories key",
+			input:     "version: 2\n",
+			repos:     []Repository{api},
+			want:      "version: 2\nrepositories:\n  - name: api\n    url: https://github.com/example/api.git\n",
+			wantAdded: []string{"api"},
+		},
+		{
+			name:  "already listed",
+			input: "version: 2\nrepositories:\n  - name: api\n    url: https://github.com/example/api\n",
+			repos: []Repository{api},
+			want:  "version: 2\nrepositories:\n  - name: api\n    url: https://github.com/example/api\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, added, err := AddRepositories([]byte(tt.input), tt.repos)
+			if err != nil {
+				t.Fatalf("AddRepositories() error = %v", err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("AddRepositories() =\n%s\nwant\n%s", out, tt.want)
+			}
+			var names []string
+			for _, repo := range added {
+				names = append(names, repo.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantAdded, ",") {
+				t.Errorf("added = %v, want %v", names, tt.wantAdded)
+			}
+			if _, err := parse(out); err != nil {
+				t.Errorf("result does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddRepositories_Template(t *testing.T) {
+	input := `# devslot configuration file
+version: 2
+repositories:
+  # Add your repositories here
+  # - name: my-app
+`
+	out, _, err := AddRepositories([]byte(input), []Repository{{Name: "api", URL: "https://github.com/example/api.git"}})
+	if err != nil {
+		t.Fatalf("AddRepositories() error = %v", err)
+	}
+	config, err := parse(out)
+	if err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, out)
+	}
+	if len(config.Repositories) != 1 || config.Repositories[0].Name != "api" {
+		t.Errorf("repositories = %+v", config.Repositories)
+	}
+	if !strings.Contains(string(out), "# devslot configuration file") {
+		t.Errorf("expected the header comment to be kept:\n%s", out)
+	}
+}
+
+func TestAddRepositories_Conflict(t *testing.T) {
+	input := "version: 2\nrepositories:\n  - name: api\n    url: https://github.com/example/api.git\n"
+
+	_, _, err := AddRepositories([]byte(input), []Repository{{Name: "api", URL: "https://github.com/other/api.git"}})
+	if errors.ExitCode(err) != errors.ExitUsage || !strings.Contains(err.Error(), "already listed with URL https://github.com/example/api.git") {
+		t.Errorf("AddRepositories() error = %v, want a conflict naming the listed URL", err)
+	}
+
+	_, _, err = AddRepositories([]byte(input), []Repository{{Name: "bad/name/x", URL: "https://github.com/other/x.git"}})
+	if err == nil {
+		t.Error("AddRepositories() accepted an invalid repository name")
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// Migrate rewrites a version 1 configuration file in the latest layout.
+// Key order is kept, and comments are carried over as far as the YAML library
+// tracks them; formatting such as quoting may change.
+func Migrate(data []byte) ([]byte, error) {
+	config, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if config.Version != 1 {
+		return nil, fmt.Errorf("configuration already uses version %d", config.Version)
+	}
+
+	comments := yaml.CommentMap{}
+	var doc yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(data, &doc, yaml.UseOrderedMap(), yaml.CommentToMap(comments)); err != nil {
+		return nil, errors.YAMLParseFailed(err)
+	}
+
+	// Moved keys are gathered into their section where the first of them appeared
+	migrated := yaml.MapSlice{{Key: "version", Value: LatestVersion}}
+	sections := map[string]int{}
+	for _, item := range doc {
+		key, _ := item.Key.(string)
+		if key == "version" {
+			continue
+		}
+		moved, ok := findMovedKey(key)
+		if !ok {
+			migrated = append(migrated, item)
+			continue
+		}
+
+		i, ok := sections[moved.section]
+		if !ok {
+			i = len(migrated)
+			sections[moved.section] = i
+			migrated = append(migrated, yaml.MapItem{Key: moved.section, Value: yaml.MapSlice{}})
+		}
+		migrated[i].Value = append(migrated[i].Value.(yaml.MapSlice), yaml.MapItem{Key: moved.key, Value: item.Value})
+	}
+
+	moveComments(comments)
+	out, err := yaml.MarshalWithOptions(migrated,
+		yaml.WithComment(comments), yaml.IndentSequence(true), yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return out, nil
+}
+
+// findMovedKey looks up a version 1 key that moved in version 2
+func findMovedKey(key string) (movedKey, bool) {
+	for _, moved := range movedKeys {
+		if moved.v1 == key {
+			return moved, true
+		}
+	}
+	return movedKey{}, false
+}
+
+// moveComments re-attaches the comments of moved keys to their version 2 path
+func moveComments(comments yaml.CommentMap) {
+	for path, comment := range comments {
+		for _, moved := range movedKeys {
+			old := "$." + moved.v1
+			if path != old && !strings.HasPrefix(path, old+".") && !strings.HasPrefix(path, old+"[") {
+				continue
+			}
+			delete(comments, path)
+			comments["$."+moved.section+"."+moved.key+strings.TrimPrefix(path, old)] = comment
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"github.com/goccy/go-yaml"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// LatestVersion is the newest devslot.yaml version
+const LatestVersion = 2
+
+// movedKey is a version 1 top-level key that version 2 nests under a section
+type movedKey struct {
+	v1, section, key string
+}
+
+// movedKeys lists, in version 2 order, the keys that moved between version 1 and 2
+var movedKeys = []movedKey{
+	{v1: "default_host", section: "defaults", key: "host"},
+	{v1: "branch_prefix", section: "defaults", key: "branch_prefix"},
+	{v1: "repos_dir", section: "directories", key: "repos"},
+	{v1: "slots_dir", section: "directories", key: "slots"},
+}
+
+// configV2 is the version 2 layout of devslot.yaml. Settings that did not move
+// are read into the embedded Config.
+type configV2 struct {
+	Config   `yaml:",inline"`
+	Defaults struct {
+		Host         string `yaml:"host,omitempty"`
+		BranchPrefix string `yaml:"branch_prefix,omitempty"`
+	} `yaml:"defaults,omitempty"`
+	Directories struct {
+		Repos string `yaml:"repos,omitempty"`
+		Slots string `yaml:"slots,omitempty"`
+	} `yaml:"directories,omitempty"`
+}
+
+// normalize converts the version 2 layout to the Config every command works with
+func (c *configV2) normalize() *Config {
+	config := c.Config
+	config.DefaultHost = c.Defaults.Host
+	config.BranchPrefix = c.Defaults.BranchPrefix
+	config.ReposPath = c.Directories.Repos
+	config.SlotsPath = c.Directories.Slots
+	return &config
+}
+
+// parse decodes a single configuration file of any supported version.
+// A missing version means version 1.
+func parse(data []byte) (*Config, error) {
+	var header struct {
+		Version int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, errors.YAMLParseFailed(err)
+	}
+
+	switch header.Version {
+	case 0, 1:
+		var config Config
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, errors.YAMLParseFailed(err)
+		}
+		config.Version = 1
+		return &config, nil
+	case 2:
+		var keys map[string]any
+		if err := yaml.Unmarshal(data, &keys); err != nil {
+			return nil, errors.YAMLParseFailed(err)
+		}
+		for _, moved := range movedKeys {
+			if _, ok := keys[moved.v1]; ok {
+				return nil, errors.RenamedConfigKey(moved.v1, moved.section+"."+moved.key)
+			}
+		}
+
+		var v2 configV2
+		if err := yaml.Unmarshal(data, &v2); err != nil {
+			return nil, errors.YAMLParseFailed(err)
+		}
+		return v2.normalize(), nil
+	default:
+		return nil, errors.UnsupportedVersion(header.Version)
+	}
+}
@@ -0,0 +1,74 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+func TestE2E_DoctorHealthy(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+	p.CreateSlot("work")
+
+	stdout, stderr, code := p.Run("doctor")
+	if code != 0 || strings.Contains(stderr, "[FAIL]") || strings.Contains(stderr, "[WARN]") {
+		t.Errorf("expected a healthy project, got exit code %d:\n%s%s", code, stdout, stderr)
+	}
+}
+
+func TestE2E_DoctorMissingRepository(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+	if err := os.RemoveAll(p.Path("repos", "repo1.git")); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, code := p.Run("doctor", "--json")
+	if code != errors.ExitDoctorIssues {
+		t.Errorf("exit code = %d, want %d\n%s", code, errors.ExitDoctorIssues, stderr)
+	}
+
+	var findings []struct {
+		Check    string `json:"check"`
+		Severity string `json:"severity"`
+		Target   string `json:"target"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("doctor --json printed invalid JSON: %v\n%s", err, stdout)
+	}
+	found := false
+	for _, finding := range findings {
+		if finding.Check == "repositories" && finding.Severity == "error" && strings.Contains(finding.Target, "repo1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an error about repo1, got %+v", findings)
+	}
+
+	// init repairs it
+	p.MustRun("init")
+	p.MustRun("doctor")
+}
+
+func TestE2E_DoctorFixesMovedProject(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+	p.CreateSlot("work")
+
+	moved := p.Root + "-moved"
+	if err := os.Rename(p.Root, moved); err != nil {
+		t.Fatal(err)
+	}
+	p.Root = moved
+
+	if _, _, code := p.Run("doctor"); code != errors.ExitDoctorIssues {
+		t.Errorf("exit code = %d, want %d for worktrees of a moved project", code, errors.ExitDoctorIssues)
+	}
+	p.MustRun("doctor", "--fix")
+	p.MustRun("doctor")
+	if stdout := p.MustRun("status", "work"); !strings.Contains(stdout, "repo1") {
+		t.Errorf("expected the repaired worktree in status, got:\n%s", stdout)
+	}
+}
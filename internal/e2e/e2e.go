@@ -0,0 +1,174 @@
+// Package e2e runs the devslot binary against throwaway projects.
+//
+// The binary is built once per 'go test' run. Packages using the harness
+// remove it afterwards by calling Main from their TestMain.
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+var (
+	buildOnce sync.Once
+	buildDir  string
+	binary    string
+	buildErr  error
+)
+
+// Main runs the tests of a package and removes the shared binary afterwards
+func Main(m *testing.M) {
+	code := m.Run()
+	if buildDir != "" {
+		_ = os.RemoveAll(buildDir)
+	}
+	os.Exit(code)
+}
+
+// Binary returns the path of a devslot binary built from this source tree,
+// building it on first use
+func Binary(t *testing.T) string {
+	t.Helper()
+
+	buildOnce.Do(func() {
+		buildDir, buildErr = os.MkdirTemp("", "devslot-e2e-*")
+		if buildErr != nil {
+			return
+		}
+		binary = filepath.Join(buildDir, "devslot")
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		output, err := exec.Command("go", "build", "-o", binary, "github.com/yammerjp/devslot/cmd/devslot").CombinedOutput()
+		if err != nil {
+			buildErr = fmt.Errorf("%w\n%s", err, output)
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("failed to build devslot: %v", buildErr)
+	}
+	return binary
+}
+
+// Project is a devslot project in a temporary directory
+type Project struct {
+	// Root is the project root, where commands run
+	Root string
+	// Env is added to the environment of every command, e.g. "DEVSLOT_LOG_LEVEL=debug"
+	Env []string
+
+	t          *testing.T
+	originsDir string
+}
+
+// NewProject creates an empty project directory. Repositories added with
+// AddLocalRepo live next to it, outside the project.
+func NewProject(t *testing.T) *Project {
+	t.Helper()
+
+	base, err := filepath.EvalSymlinks(testutil.TempDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Project{Root: filepath.Join(base, "project"), t: t, originsDir: filepath.Join(base, "origins")}
+	if err := os.MkdirAll(p.Root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Path returns a path inside the project
+func (p *Project) Path(elem ...string) string {
+	return filepath.Join(append([]string{p.Root}, elem...)...)
+}
+
+// Run runs devslot in the project root and returns its output and exit code
+func (p *Project) Run(args ...string) (stdout, stderr string, exitCode int) {
+	p.t.Helper()
+
+	var out, errOut bytes.Buffer
+	cmd := exec.Command(Binary(p.t), args...)
+	cmd.Dir = p.Root
+	cmd.Env = p.environ()
+	// Not /dev/null: it is a character device, which devslot takes for a terminal
+	cmd.Stdin = strings.NewReader("")
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		p.t.Fatalf("failed to run devslot %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String(), errOut.String(), exitCode
+}
+
+// MustRun runs devslot in the project root and fails the test unless it succeeds
+func (p *Project) MustRun(args ...string) string {
+	p.t.Helper()
+
+	stdout, stderr, code := p.Run(args...)
+	if code != 0 {
+		p.t.Fatalf("devslot %s exited with %d\nstdout:\n%s\nstderr:\n%s", strings.Join(args, " "), code, stdout, stderr)
+	}
+	return stdout
+}
+
+// environ isolates commands from the devslot settings of the developer running the tests
+func (p *Project) environ() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "DEVSLOT_") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env,
+		"NO_COLOR=1",
+		"GIT_AUTHOR_NAME=devslot", "GIT_AUTHOR_EMAIL=devslot@example.com",
+		"GIT_COMMITTER_NAME=devslot", "GIT_COMMITTER_EMAIL=devslot@example.com",
+	)
+	return append(env, p.Env...)
+}
+
+// WriteConfig writes devslot.yaml
+func (p *Project) WriteConfig(content string) {
+	p.t.Helper()
+	testutil.CreateFile(p.t, p.Path("devslot.yaml"), content)
+}
+
+// WriteHook writes an executable hook script
+func (p *Project) WriteHook(name, script string) {
+	p.t.Helper()
+	testutil.CreateExecutable(p.t, p.Path("hooks", name), script)
+}
+
+// AddLocalRepo creates a bare repository with one commit on main outside the
+// project and returns its path, for use as a repository URL
+func (p *Project) AddLocalRepo(name string) string {
+	p.t.Helper()
+
+	path := filepath.Join(p.originsDir, name+".git")
+	if err := os.MkdirAll(p.originsDir, 0755); err != nil {
+		p.t.Fatal(err)
+	}
+	testutil.InitBareRepo(p.t, path)
+	return path
+}
+
+// CreateSlot creates a slot and fails the test unless it succeeds
+func (p *Project) CreateSlot(name string) {
+	p.t.Helper()
+	p.MustRun("create", name)
+}
@@ -0,0 +1,11 @@
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/e2e"
+)
+
+func TestMain(m *testing.M) {
+	e2e.Main(m)
+}
@@ -0,0 +1,168 @@
+package e2e_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/e2e"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+// newProjectWithRepos creates a project configured with local repositories and runs init
+func newProjectWithRepos(t *testing.T, names ...string) *e2e.Project {
+	t.Helper()
+
+	p := e2e.NewProject(t)
+	config := "version: 1\nrepositories:\n"
+	for _, name := range names {
+		config += fmt.Sprintf("  - name: %s\n    url: %s\n", name, p.AddLocalRepo(name))
+	}
+	p.WriteConfig(config)
+	// Like a project generated by 'devslot boilerplate'
+	for _, dir := range []string{"hooks", "slots"} {
+		if err := os.MkdirAll(p.Path(dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(p.Path(".gitignore"), []byte("/repos/\n/slots/\n/archives/\n/.devslot.lock\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.MustRun("init")
+	return p
+}
+
+func TestE2E_Init(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1", "repo2")
+
+	for _, name := range []string{"repo1.git", "repo2.git"} {
+		if !testutil.DirExists(t, p.Path("repos", name)) {
+			t.Errorf("expected repos/%s to be cloned", name)
+		}
+	}
+
+	// A second init keeps the existing clones
+	p.MustRun("init")
+	if !testutil.DirExists(t, p.Path("repos", "repo1.git")) {
+		t.Error("repos/repo1.git disappeared after a second init")
+	}
+}
+
+func TestE2E_InitWithoutConfig(t *testing.T) {
+	p := e2e.NewProject(t)
+
+	_, stderr, code := p.Run("init")
+	if code != errors.ExitNotInProject {
+		t.Errorf("exit code = %d, want %d\n%s", code, errors.ExitNotInProject, stderr)
+	}
+}
+
+func TestE2E_CreateAndList(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+
+	if stdout := p.MustRun("list"); !strings.Contains(stdout, "No slots found") {
+		t.Errorf("expected no slots, got:\n%s", stdout)
+	}
+
+	p.CreateSlot("feature-a")
+	p.CreateSlot("feature-b")
+	if !testutil.FileExists(t, p.Path("slots", "feature-a", "repo1", "README.md")) {
+		t.Error("expected the worktree of repo1 to be checked out in feature-a")
+	}
+
+	if stdout := p.MustRun("list", "--porcelain"); stdout != "feature-a\nfeature-b\n" {
+		t.Errorf("list --porcelain = %q", stdout)
+	}
+
+	// Creating the same slot again fails
+	if _, _, code := p.Run("create", "feature-a"); code == 0 {
+		t.Error("expected creating an existing slot to fail")
+	}
+}
+
+func TestE2E_Destroy(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1", "repo2")
+	p.CreateSlot("doomed")
+	p.CreateSlot("kept")
+	p.WriteHook("pre-destroy", "#!/bin/sh\necho \"$DEVSLOT_SLOT_NAME\" > \"$DEVSLOT_ROOT/pre-destroy.out\"\n")
+	p.WriteHook("post-destroy", "#!/bin/sh\necho \"$DEVSLOT_SLOT_NAME\" > \"$DEVSLOT_ROOT/post-destroy.out\"\n")
+
+	p.MustRun("destroy", "doomed")
+
+	if testutil.DirExists(t, p.Path("slots", "doomed")) {
+		t.Error("expected slots/doomed to be removed")
+	}
+	if !testutil.DirExists(t, p.Path("slots", "kept", "repo1")) {
+		t.Error("destroying one slot removed another")
+	}
+	for _, hook := range []string{"pre-destroy", "post-destroy"} {
+		if got := strings.TrimSpace(testutil.ReadFile(t, p.Path(hook+".out"))); got != "doomed" {
+			t.Errorf("%s hook saw slot %q, want doomed", hook, got)
+		}
+	}
+	if stdout := p.MustRun("list", "--porcelain"); stdout != "kept\n" {
+		t.Errorf("list --porcelain = %q after destroy", stdout)
+	}
+
+	// The branch can be used by a new slot of the same name
+	p.CreateSlot("doomed")
+}
+
+func TestE2E_DestroyFailingHook(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+	p.CreateSlot("guarded")
+	p.WriteHook("pre-destroy", "#!/bin/sh\nexit 1\n")
+
+	_, stderr, code := p.Run("destroy", "guarded")
+	if code != errors.ExitHookFailed {
+		t.Errorf("exit code = %d, want %d\n%s", code, errors.ExitHookFailed, stderr)
+	}
+	if !testutil.DirExists(t, p.Path("slots", "guarded", "repo1")) {
+		t.Error("a failing pre-destroy hook should keep the slot")
+	}
+}
+
+func TestE2E_DestroyMissingSlot(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+
+	if _, _, code := p.Run("destroy", "missing"); code == 0 {
+		t.Error("expected destroying a missing slot to fail")
+	}
+}
+
+func TestE2E_Reload(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+	p.CreateSlot("work")
+
+	// A repository added to the configuration gets a worktree on reload
+	repo2 := p.AddLocalRepo("repo2")
+	p.WriteConfig(fmt.Sprintf("version: 1\nrepositories:\n  - name: repo1\n    url: %s\n  - name: repo2\n    url: %s\n",
+		p.Path("..", "origins", "repo1.git"), repo2))
+	p.MustRun("init")
+	p.WriteHook("post-reload", "#!/bin/sh\necho reloaded > \"$DEVSLOT_ROOT/post-reload.out\"\n")
+
+	p.MustRun("reload", "work")
+	if !testutil.DirExists(t, p.Path("slots", "work", "repo2")) {
+		t.Error("expected reload to create the worktree of repo2")
+	}
+	if !testutil.FileExists(t, p.Path("post-reload.out")) {
+		t.Error("expected the post-reload hook to run")
+	}
+
+	// Worktrees of repositories removed from the configuration are left alone
+	p.WriteConfig(fmt.Sprintf("version: 1\nrepositories:\n  - name: repo2\n    url: %s\n", repo2))
+	p.MustRun("reload", "work")
+	if !testutil.DirExists(t, p.Path("slots", "work", "repo1")) {
+		t.Error("reload removed the worktree of a repository no longer configured")
+	}
+}
+
+func TestE2E_ReloadMissingSlot(t *testing.T) {
+	p := newProjectWithRepos(t, "repo1")
+
+	if _, _, code := p.Run("reload", "missing"); code == 0 {
+		t.Error("expected reloading a missing slot to fail")
+	}
+}
@@ -1,7 +1,9 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"strings"
 )
 
 // UserError wraps an error with a user-friendly message and suggestion
@@ -9,6 +11,8 @@ type UserError struct {
 	Err        error
 	Message    string
 	Suggestion string
+	// Kind decides the exit code of the command
+	Kind Kind
 }
 
 // Error implements the error interface
@@ -33,9 +37,47 @@ func WithSuggestion(err error, message, suggestion string) error {
 	}
 }
 
+// WithSuggestionf creates a new UserError with a formatted suggestion
+func WithSuggestionf(err error, message, format string, args ...any) error {
+	return WithSuggestion(err, message, fmt.Sprintf(format, args...))
+}
+
+// Hint splits err into its message and the suggestion of the outermost UserError in its chain,
+// so that the suggestion can be shown on its own line. The hint is empty when there is no suggestion.
+func Hint(err error) (message, hint string) {
+	var userErr *UserError
+	if !stderrors.As(err, &userErr) || userErr.Suggestion == "" {
+		return err.Error(), ""
+	}
+	return strings.Replace(err.Error(), "\n"+userErr.Suggestion, "", 1), userErr.Suggestion
+}
+
+// WithNote appends an informational line to an error.
+// For a UserError the note is added after the suggestion; an empty note leaves the error unchanged.
+func WithNote(err error, note string) error {
+	if err == nil || note == "" {
+		return err
+	}
+
+	if userErr, ok := err.(*UserError); ok {
+		suggestion := note
+		if userErr.Suggestion != "" {
+			suggestion = userErr.Suggestion + "\n" + note
+		}
+		return &UserError{
+			Err:        userErr.Err,
+			Message:    userErr.Message,
+			Suggestion: suggestion,
+			Kind:       userErr.Kind,
+		}
+	}
+
+	return fmt.Errorf("%w\n%s", err, note)
+}
+
 // NotInProject returns an error indicating the user is not in a devslot project
 func NotInProject(err error) error {
-	return WithSuggestion(err,
+	return withKind(KindNotInProject, err,
 		"not in a devslot project",
 		"Run 'devslot boilerplate .' to create a new project")
 }
@@ -54,9 +96,44 @@ func SlotNotFound(name string) error {
 		"Run 'devslot list' to see available slots")
 }
 
+// InsideSlot returns an error indicating a command would remove the slot the current directory is in
+func InsideSlot(action, name, projectRoot string) error {
+	return WithSuggestion(fmt.Errorf("the current directory is inside slot %s", name),
+		fmt.Sprintf("refusing to %s slot %s", action, name),
+		fmt.Sprintf("Change to a directory outside the slot first, e.g. 'cd %s'", projectRoot))
+}
+
+// ArchiveNotFound returns an error indicating no archive matches a slot name or file
+func ArchiveNotFound(ref string) error {
+	return WithSuggestion(fmt.Errorf("archive %s", ref),
+		"archive not found",
+		"Use 'devslot list --archived' to see archived slots")
+}
+
+// WorktreeNotFound returns an error indicating a repository has no worktree in a slot
+func WorktreeNotFound(slotName, repoName string) error {
+	return WithSuggestion(fmt.Errorf("worktree not found"),
+		fmt.Sprintf("repository %s not found in slot %s", repoName, slotName),
+		fmt.Sprintf("Check the repository name in devslot.yaml or run 'devslot reload %s'", slotName))
+}
+
+// WorktreeCorrupted returns an error indicating worktrees of a slot are no longer connected to their repositories
+func WorktreeCorrupted(slotName string, repos []string) error {
+	return WithSuggestion(fmt.Errorf("not connected to their repositories: %s", strings.Join(repos, ", ")),
+		fmt.Sprintf("corrupted worktrees in slot %s", slotName),
+		fmt.Sprintf("Run 'devslot reload %s --repair' to recreate them; uncommitted changes in them are lost", slotName))
+}
+
+// UnknownWorktrees returns an error indicating a slot contains git worktrees of repositories devslot does not know
+func UnknownWorktrees(slotName string, dirs []string) error {
+	return WithSuggestion(fmt.Errorf("git worktrees of repositories not in devslot.yaml: %s", strings.Join(dirs, ", ")),
+		fmt.Sprintf("refusing to destroy slot %s", slotName),
+		fmt.Sprintf("Check them for work to keep, then run 'devslot destroy %s --force'", slotName))
+}
+
 // LockFailed returns an error indicating lock acquisition failed
 func LockFailed(err error) error {
-	return WithSuggestion(err,
+	return withKind(KindLockHeld, err,
 		"failed to acquire lock",
 		"Another devslot command may be running")
 }
@@ -75,20 +152,62 @@ func FetchFailed(err error) error {
 		"Check your network connection and repository access")
 }
 
+// RepositoryInUse returns an error indicating a repository cannot be deleted because slots have worktrees from it
+func RepositoryInUse(repoName string, slots []string) error {
+	return WithSuggestion(fmt.Errorf("worktrees exist in slots: %s", strings.Join(slots, ", ")),
+		fmt.Sprintf("refusing to delete repository %s", repoName),
+		"Destroy or reload those slots first, or use --force to delete it anyway")
+}
+
+// BundleNotFound returns an error indicating the git bundle for a repository is missing
+func BundleNotFound(repoName, path string) error {
+	return WithSuggestion(fmt.Errorf("%s does not exist", path),
+		fmt.Sprintf("no bundle found for %s", repoName),
+		fmt.Sprintf("Provide %s.bundle in the bundle directory or set 'bundle' for %s in devslot.yaml", repoName, repoName))
+}
+
+// BundleFailed returns an error indicating a repository could not be read from its git bundle
+func BundleFailed(repoName string, err error) error {
+	return WithSuggestion(err,
+		fmt.Sprintf("failed to read bundle for %s", repoName),
+		"Check the bundle with 'git bundle verify' and that it includes the required prerequisite commits")
+}
+
 // HookNotExecutable returns an error indicating a hook is not executable
 func HookNotExecutable(hookName string) error {
-	return WithSuggestion(fmt.Errorf("permission denied"),
+	return withKind(KindHookFailed, fmt.Errorf("permission denied"),
 		fmt.Sprintf("hook %s is not executable", hookName),
 		fmt.Sprintf("Run 'chmod +x hooks/%s' to fix", hookName))
 }
 
 // HookFailed returns an error indicating a hook execution failed
 func HookFailed(hookName string, err error) error {
-	return WithSuggestion(err,
+	return withKind(KindHookFailed, err,
 		fmt.Sprintf("hook %s failed", hookName),
 		fmt.Sprintf("Check the hook script at hooks/%s for errors", hookName))
 }
 
+// UnknownHookType returns an error indicating a hook type name is not recognized
+func UnknownHookType(name string, valid []string) error {
+	return withKind(KindUsage, fmt.Errorf("unknown hook type %q", name),
+		"invalid hook type",
+		fmt.Sprintf("Valid hook types: %s", strings.Join(valid, ", ")))
+}
+
+// InlineHookFailed returns an error indicating an inline hook command from devslot.yaml failed
+func InlineHookFailed(hookName, command string, err error) error {
+	return withKind(KindHookFailed, err,
+		fmt.Sprintf("inline hook %s command %q failed", hookName, command),
+		fmt.Sprintf("Check the hooks.%s entries in devslot.yaml", hookName))
+}
+
+// EnvrcTemplateFailed returns an error indicating the envrc_template could not be read or rendered
+func EnvrcTemplateFailed(path string, err error) error {
+	return WithSuggestion(err,
+		fmt.Sprintf("failed to render envrc template %s", path),
+		"Fix the template at the reported line; it is a Go text/template with .SlotName, .SlotDir and .Repos")
+}
+
 // WorktreeFailed returns an error indicating worktree creation failed
 func WorktreeFailed(repoName string, err error) error {
 	return WithSuggestion(err,
@@ -96,11 +215,47 @@ func WorktreeFailed(repoName string, err error) error {
 		"Ensure the branch exists or try 'devslot init' to update repositories")
 }
 
+// SlotIncomplete returns an error indicating a slot was kept without the worktrees that failed
+func SlotIncomplete(slotName string, failed []string, err error) error {
+	return WithSuggestion(err,
+		fmt.Sprintf("slot %s created without worktrees for %s", slotName, strings.Join(failed, ", ")),
+		fmt.Sprintf("Fix the problem and run 'devslot reload %s' to finish the slot", slotName))
+}
+
+// CreatePreflightFailed returns an error listing every problem that keeps a slot from being created
+func CreatePreflightFailed(slotName string, problems []error) error {
+	var list strings.Builder
+	for _, problem := range problems {
+		message, hint := Hint(problem)
+		fmt.Fprintf(&list, "\n  - %s", message)
+		if hint != "" {
+			fmt.Fprintf(&list, "\n    %s", hint)
+		}
+	}
+	return WithSuggestion(fmt.Errorf("%d problems found:%s", len(problems), list.String()),
+		fmt.Sprintf("cannot create slot %s", slotName),
+		"Fix the problems above and run the command again; nothing was created")
+}
+
+// ConfigFileNotFound returns an error for a --config file that does not exist
+func ConfigFileNotFound(path string) error {
+	return withKind(KindNotInProject, fmt.Errorf("configuration not found"),
+		fmt.Sprintf("configuration file %s does not exist", path),
+		"Check the --config flag and the DEVSLOT_CONFIG environment variable")
+}
+
+// ConfigFileUnreadable returns an error for a --config file that cannot be read
+func ConfigFileUnreadable(path string, err error) error {
+	return withKind(KindNotInProject, err,
+		fmt.Sprintf("cannot read configuration file %s", path),
+		"Check the --config flag and the permissions of the file")
+}
+
 // ConfigNotFound returns an error indicating devslot.yaml was not found
-func ConfigNotFound() error {
-	return WithSuggestion(fmt.Errorf("configuration not found"),
-		"devslot.yaml not found in any parent directory",
-		"Run 'devslot boilerplate .' to create a new project")
+func ConfigNotFound(dir string) error {
+	return withKind(KindNotInProject, fmt.Errorf("configuration not found"),
+		fmt.Sprintf("devslot.yaml not found in %s or any parent directory", dir),
+		"Run 'devslot boilerplate .' to create a new project, or point -C/--project-root at an existing one")
 }
 
 // YAMLParseFailed returns an error indicating YAML parsing failed
@@ -110,11 +265,241 @@ func YAMLParseFailed(err error) error {
 		"Check the devslot.yaml syntax")
 }
 
+// ConfigExtendsFailed returns an error indicating the file named by extends could not be included
+func ConfigExtendsFailed(extends, from string, err error) error {
+	return WithSuggestion(err,
+		fmt.Sprintf("cannot extend %s from %s", extends, from),
+		"extends takes the path of another devslot configuration file, relative to the file that extends it")
+}
+
+// ConfigExtendsCycle returns an error indicating configuration files extend each other in a loop
+func ConfigExtendsCycle(chain []string) error {
+	return WithSuggestion(fmt.Errorf("%s", strings.Join(chain, " -> ")),
+		"configuration files extend each other in a cycle",
+		"Remove extends from one of the files")
+}
+
 // UnsupportedVersion returns an error indicating unsupported config version
 func UnsupportedVersion(version int) error {
 	return WithSuggestion(fmt.Errorf("unsupported version"),
 		fmt.Sprintf("unsupported config version: %d", version),
-		"Only version 1 is supported")
+		"Versions 1 and 2 are supported")
+}
+
+// RenamedConfigKey returns an error for a version 1 key used in a version 2 devslot.yaml
+func RenamedConfigKey(key, replacement string) error {
+	return WithSuggestion(fmt.Errorf("%s is not a version 2 setting", key),
+		fmt.Sprintf("invalid key %s in devslot.yaml", key),
+		fmt.Sprintf("Use %s instead, or run 'devslot migrate-config' on a version 1 file", replacement))
+}
+
+// InvalidRepositoryName returns an error indicating a repository name in devslot.yaml is not usable
+func InvalidRepositoryName(name, reason string) error {
+	return WithSuggestion(fmt.Errorf("%s", reason),
+		fmt.Sprintf("invalid repository name %q", name),
+		"Use a plain name like 'api' or a namespaced name like 'platform/api' in devslot.yaml")
+}
+
+// InvalidRemoteName returns an error indicating the remote configured for a repository is not a valid remote name
+func InvalidRemoteName(repoName, remote string) error {
+	return WithSuggestion(fmt.Errorf("%q is not a valid remote name", remote),
+		fmt.Sprintf("invalid remote for repository %s", repoName),
+		"Use the name of a git remote like 'origin' or 'upstream' in devslot.yaml")
+}
+
+// BaseNotInSingleBranch returns an error indicating a base branch is missing from a repository cloned with single_branch
+func BaseNotInSingleBranch(base, branch, bareRepoPath, remote string) error {
+	return WithSuggestion(fmt.Errorf("%s is a single-branch clone of %s", bareRepoPath, branch),
+		fmt.Sprintf("base %s not found", base),
+		fmt.Sprintf("Remove single_branch from the repository in devslot.yaml and clone it again, or fetch the branch too with 'git -C %s remote set-branches --add %s %s' followed by 'devslot fetch'", bareRepoPath, remote, base))
+}
+
+// InvalidRepositoryBranch returns an error indicating the branch of a repository in devslot.yaml is invalid
+func InvalidRepositoryBranch(repoName, reason string) error {
+	return WithSuggestion(fmt.Errorf("%s", reason),
+		fmt.Sprintf("invalid branch for repository %s", repoName),
+		"Set branch together with single_branch: true, e.g. single_branch: true and branch: main")
+}
+
+// PathOutsideDirectory returns an error indicating a slot or repository name leads outside the directory meant to hold it
+func PathOutsideDirectory(name, dir string) error {
+	return WithSuggestion(fmt.Errorf("%q resolves to a path outside %s", name, dir),
+		"refusing to modify files outside the project",
+		"Check the slot and repository names in devslot.yaml and in the slot's .devslot.json")
+}
+
+// InvalidSlotName returns an error indicating a slot name breaks one of the naming rules
+func InvalidSlotName(name, reason string) error {
+	return withKind(KindUsage, fmt.Errorf("%s", reason),
+		fmt.Sprintf("invalid slot name %q", name),
+		"Use up to 100 letters, digits, '.', '_' and '-', not starting with '-' or '.', e.g. 'feature-x'")
+}
+
+// DuplicateRepository returns an error indicating two repositories map to the same bare repository
+func DuplicateRepository(name, other string) error {
+	return WithSuggestion(fmt.Errorf("%s and %s would share repos/%s.git", other, name, name),
+		fmt.Sprintf("duplicate repository %s in devslot.yaml", name),
+		"Give each repository a unique name, e.g. namespace them as 'platform/api' and 'billing/api'")
+}
+
+// RepositoryExists returns an error for adding a repository under a name that is listed with another URL
+func RepositoryExists(name, url string) error {
+	return withKind(KindUsage, fmt.Errorf("%s is already listed with URL %s", name, url),
+		fmt.Sprintf("repository %s already exists in devslot.yaml", name),
+		"Choose another name with --repo NAME=URL, or edit devslot.yaml to change the URL")
+}
+
+// NestedProject returns an error indicating a project would be created inside another project
+func NestedProject(dir, root string) error {
+	return withKind(KindUsage, fmt.Errorf("%s is inside the devslot project at %s", dir, root),
+		"refusing to create a nested project",
+		"Run devslot commands from "+root+" instead, or use --force to create the nested project anyway")
+}
+
+// SharedProjectDir returns an error for repos_dir and slots_dir pointing at the same directory
+func SharedProjectDir(dir string) error {
+	return WithSuggestion(fmt.Errorf("repos_dir and slots_dir both resolve to %s", dir),
+		"invalid directory settings in devslot.yaml",
+		"Point repos_dir and slots_dir at different directories")
+}
+
+// DoctorIssues returns an error indicating devslot doctor found problems
+func DoctorIssues() error {
+	return withKind(KindDoctorIssues, fmt.Errorf("some checks failed"),
+		"doctor check failed",
+		"Fix the issues reported above and run 'devslot doctor' again")
+}
+
+// HookLintIssues returns an error indicating devslot hooks lint found hooks that cannot run
+func HookLintIssues() error {
+	return withKind(KindDoctorIssues, fmt.Errorf("some hooks cannot run"),
+		"hook check failed",
+		"Fix the hooks reported above and run 'devslot hooks lint' again")
+}
+
+// UnknownGroup returns an error indicating a repository group is not defined in devslot.yaml
+func UnknownGroup(name string, known []string) error {
+	suggestion := "No repository in devslot.yaml has groups"
+	if len(known) > 0 {
+		suggestion = "Known groups: " + strings.Join(known, ", ")
+	}
+	return withKind(KindUsage, fmt.Errorf("no repository is in group %s", name),
+		fmt.Sprintf("unknown group %s", name),
+		suggestion)
+}
+
+// UnknownRepository returns an error indicating a repository is not defined in devslot.yaml
+func UnknownRepository(name string, known []string) error {
+	suggestion := "Add the repository to repositories in devslot.yaml"
+	if len(known) > 0 {
+		suggestion = "Known repositories: " + strings.Join(known, ", ")
+	}
+	return withKind(KindUsage, fmt.Errorf("repository %s is not in devslot.yaml", name),
+		fmt.Sprintf("unknown repository %s", name),
+		suggestion)
+}
+
+// UnshallowFailed returns an error indicating the history of some repositories could not be fetched
+func UnshallowFailed(err error) error {
+	return WithSuggestion(err,
+		"failed to unshallow repositories",
+		"Check your network connection and repository access, then run 'devslot unshallow' again")
+}
+
+// UnknownTemplate returns an error indicating a slot template is not defined in devslot.yaml
+func UnknownTemplate(name string, known []string) error {
+	suggestion := "Define slot templates under slot_templates in devslot.yaml"
+	if len(known) > 0 {
+		suggestion = "Known templates: " + strings.Join(known, ", ")
+	}
+	return withKind(KindUsage, fmt.Errorf("no slot template named %s", name),
+		fmt.Sprintf("unknown template %s", name),
+		suggestion)
+}
+
+// TemplateUnknownRepository returns an error indicating a slot template refers to a repository that is not configured
+func TemplateUnknownRepository(template, repoName string) error {
+	return WithSuggestion(fmt.Errorf("repository %s is not in devslot.yaml", repoName),
+		fmt.Sprintf("invalid slot template %s", template),
+		fmt.Sprintf("Add %s to repositories or remove it from slot_templates.%s", repoName, template))
+}
+
+// GitTooOld returns an error indicating the installed git lacks a feature devslot needs
+func GitTooOld(feature, required, installed string) error {
+	return WithSuggestion(fmt.Errorf("%s needs git %s or later, but git %s is installed", feature, required, installed),
+		"git is too old",
+		fmt.Sprintf("Upgrade git to %s or later", required))
+}
+
+// InvalidMinGitVersion returns an error indicating min_git_version in devslot.yaml is not a version
+func InvalidMinGitVersion(value string) error {
+	return WithSuggestion(fmt.Errorf("%q is not a git version", value),
+		"invalid min_git_version",
+		"Set min_git_version to a version such as \"2.30\" in devslot.yaml")
+}
+
+// InvalidGitEnv returns an error indicating a git_env entry in devslot.yaml is not a valid environment variable
+func InvalidGitEnv(name string) error {
+	return WithSuggestion(fmt.Errorf("%q is not a valid environment variable name", name),
+		"invalid git_env",
+		"Use names like HTTPS_PROXY or GIT_SSH_COMMAND under git_env in devslot.yaml")
+}
+
+// InvalidSeedFile returns an error indicating a seed_files entry in devslot.yaml is invalid
+func InvalidSeedFile(source, reason string) error {
+	return WithSuggestion(fmt.Errorf("%s", reason),
+		fmt.Sprintf("invalid seed file %q", source),
+		"Map files relative to the project root to paths relative to the slot, e.g. templates/.env.local: \"{repo}/.env.local\"")
+}
+
+// SeedFilesMissing returns an error indicating source files of seed_files do not exist
+func SeedFilesMissing(paths []string) error {
+	return WithSuggestion(fmt.Errorf("%s", strings.Join(paths, ", ")),
+		"seed files not found",
+		"Create them in the project or remove them from seed_files in devslot.yaml")
+}
+
+// InvalidSharedLink returns an error indicating a shared_links entry in devslot.yaml is invalid
+func InvalidSharedLink(repo, path, reason string) error {
+	target := fmt.Sprintf("invalid shared links of %s", repo)
+	if path != "" {
+		target = fmt.Sprintf("invalid shared link %q of %s", path, repo)
+	}
+	return WithSuggestion(fmt.Errorf("%s", reason), target,
+		"List paths relative to the worktree under the repository name, e.g. shared_links: {web: [node_modules/.cache]}")
+}
+
+// InvalidUsage returns an error indicating the command line is invalid
+func InvalidUsage(message, suggestion string) error {
+	return withKind(KindUsage, fmt.Errorf("invalid arguments"), message, suggestion)
+}
+
+// BranchNotFound returns an error indicating a branch to check out is missing from some repositories
+func BranchNotFound(branch string, repos []string) error {
+	return WithSuggestion(fmt.Errorf("not found in %s", strings.Join(repos, ", ")),
+		fmt.Sprintf("branch %s not found", branch),
+		"Use --missing=skip to leave those repositories unchanged, or --missing=default to switch them to their default branch")
+}
+
+// PullIncomplete returns an error indicating some repositories of a slot were not updated by pull
+func PullIncomplete(notUpdated, total int) error {
+	return WithSuggestion(fmt.Errorf("%d of %d repositories not updated", notUpdated, total),
+		"pull incomplete",
+		"See the reasons above; commit or stash changes and pull again")
+}
+
+// DestroyIncomplete returns an error indicating some of the slots to destroy were not destroyed
+func DestroyIncomplete(failed []string, total int) error {
+	return WithSuggestion(fmt.Errorf("%d of %d slots not destroyed: %s", len(failed), total, strings.Join(failed, ", ")),
+		"destroy incomplete",
+		"See the reasons above, fix them and destroy the remaining slots again")
+}
+
+// ReloadIncomplete returns an error indicating some slots could not be reloaded
+func ReloadIncomplete(failed []string, total int) error {
+	return WithSuggestion(fmt.Errorf("%d of %d slots not reloaded: %s", len(failed), total, strings.Join(failed, ", ")),
+		"reload incomplete",
+		"See the reasons above, fix them and run 'devslot sync' again")
 }
 
 // NoBranchesFound returns an error indicating no branches in repository
@@ -123,3 +508,11 @@ func NoBranchesFound() error {
 		"no branches found in repository",
 		"The repository may be empty or corrupted")
 }
+
+// GitNotFound returns an error indicating the git binary devslot runs is not installed;
+// install is how to install git on this operating system
+func GitNotFound(binary string, err error, install string) error {
+	return WithSuggestion(err,
+		fmt.Sprintf("git not found (%s)", binary),
+		fmt.Sprintf("Install git: %s\nOr set DEVSLOT_GIT, or git_binary in devslot.yaml, to the path of your git", install))
+}
@@ -104,17 +104,41 @@ func TestHelperFunctions(t *testing.T) {
 			wantMessage: "hook post-create failed",
 			wantSuggest: "Check the hook script at hooks/post-create for errors",
 		},
+		{
+			name:        "InlineHookFailed",
+			errFunc:     func() error { return InlineHookFailed("post-create", "make bootstrap", errors.New("exit 2")) },
+			wantMessage: `inline hook post-create command "make bootstrap" failed`,
+			wantSuggest: "Check the hooks.post-create entries in devslot.yaml",
+		},
 		{
 			name:        "WorktreeFailed",
 			errFunc:     func() error { return WorktreeFailed("my-repo", errors.New("branch error")) },
 			wantMessage: "failed to create worktree for my-repo",
 			wantSuggest: "Ensure the branch exists or try 'devslot init' to update repositories",
 		},
+		{
+			name:        "WorktreeCorrupted",
+			errFunc:     func() error { return WorktreeCorrupted("work", []string{"repo1", "repo2"}) },
+			wantMessage: "corrupted worktrees in slot work",
+			wantSuggest: "Run 'devslot reload work --repair' to recreate them; uncommitted changes in them are lost",
+		},
+		{
+			name:        "UnknownWorktrees",
+			errFunc:     func() error { return UnknownWorktrees("work", []string{"legacy"}) },
+			wantMessage: "refusing to destroy slot work",
+			wantSuggest: "Check them for work to keep, then run 'devslot destroy work --force'",
+		},
+		{
+			name:        "SlotIncomplete",
+			errFunc:     func() error { return SlotIncomplete("work", []string{"api"}, errors.New("fetch failed")) },
+			wantMessage: "slot work created without worktrees for api",
+			wantSuggest: "Fix the problem and run 'devslot reload work' to finish the slot",
+		},
 		{
 			name:        "ConfigNotFound",
-			errFunc:     func() error { return ConfigNotFound() },
-			wantMessage: "devslot.yaml not found in any parent directory",
-			wantSuggest: "Run 'devslot boilerplate .' to create a new project",
+			errFunc:     func() error { return ConfigNotFound("/tmp/work") },
+			wantMessage: "devslot.yaml not found in /tmp/work or any parent directory",
+			wantSuggest: "Run 'devslot boilerplate .' to create a new project, or point -C/--project-root at an existing one",
 		},
 		{
 			name:        "YAMLParseFailed",
@@ -124,9 +148,9 @@ func TestHelperFunctions(t *testing.T) {
 		},
 		{
 			name:        "UnsupportedVersion",
-			errFunc:     func() error { return UnsupportedVersion(2) },
-			wantMessage: "unsupported config version: 2",
-			wantSuggest: "Only version 1 is supported",
+			errFunc:     func() error { return UnsupportedVersion(3) },
+			wantMessage: "unsupported config version: 3",
+			wantSuggest: "Versions 1 and 2 are supported",
 		},
 		{
 			name:        "NoBranchesFound",
@@ -165,3 +189,92 @@ func TestErrorFormatting(t *testing.T) {
 		t.Errorf("Error format incorrect:\ngot:  %q\nwant: %q", err.Error(), expected)
 	}
 }
+
+func TestWithNote(t *testing.T) {
+	t.Run("appends to UserError suggestion", func(t *testing.T) {
+		err := WithNote(WorktreeFailed("repo", errors.New("boom")), "slot created by devslot v0.3.0")
+		want := "failed to create worktree for repo: boom\nEnsure the branch exists or try 'devslot init' to update repositories\nslot created by devslot v0.3.0"
+		if err.Error() != want {
+			t.Errorf("WithNote() = %q, want %q", err.Error(), want)
+		}
+		var userErr *UserError
+		if !errors.As(err, &userErr) {
+			t.Error("WithNote() should keep the UserError type")
+		}
+	})
+
+	t.Run("wraps plain errors", func(t *testing.T) {
+		base := errors.New("boom")
+		err := WithNote(base, "note")
+		if err.Error() != "boom\nnote" {
+			t.Errorf("WithNote() = %q", err.Error())
+		}
+		if !errors.Is(err, base) {
+			t.Error("WithNote() should wrap the original error")
+		}
+	})
+
+	t.Run("empty note leaves error unchanged", func(t *testing.T) {
+		base := errors.New("boom")
+		if err := WithNote(base, ""); err != base {
+			t.Errorf("WithNote() = %v, want original error", err)
+		}
+	})
+}
+
+func TestWithSuggestionf(t *testing.T) {
+	err := WithSuggestionf(errors.New("boom"), "operation failed", "Run 'devslot %s %s'", "reload", "dev")
+	if got, want := err.Error(), "operation failed: boom\nRun 'devslot reload dev'"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+		wantHint    string
+	}{
+		{
+			name:        "plain error",
+			err:         errors.New("boom"),
+			wantMessage: "boom",
+		},
+		{
+			name:        "user error",
+			err:         SlotNotFound("dev"),
+			wantMessage: "slot dev does not exist: slot not found",
+			wantHint:    "Run 'devslot list' to see available slots",
+		},
+		{
+			name:        "wrapped user error",
+			err:         fmt.Errorf("failed to destroy slot: %w", SlotNotFound("dev")),
+			wantMessage: "failed to destroy slot: slot dev does not exist: slot not found",
+			wantHint:    "Run 'devslot list' to see available slots",
+		},
+		{
+			name:        "user error with note",
+			err:         WithNote(SlotNotFound("dev"), "Slots are kept in slots/"),
+			wantMessage: "slot dev does not exist: slot not found",
+			wantHint:    "Run 'devslot list' to see available slots\nSlots are kept in slots/",
+		},
+		{
+			name:        "user error without suggestion",
+			err:         WithSuggestion(errors.New("boom"), "operation failed", ""),
+			wantMessage: "operation failed: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, hint := Hint(tt.err)
+			if message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", message, tt.wantMessage)
+			}
+			if hint != tt.wantHint {
+				t.Errorf("hint = %q, want %q", hint, tt.wantHint)
+			}
+		})
+	}
+}
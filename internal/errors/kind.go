@@ -0,0 +1,86 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// Kind classifies a UserError so that scripts can tell failures apart by exit code
+type Kind int
+
+const (
+	// KindGeneric is any failure without a more specific kind
+	KindGeneric Kind = iota
+	// KindUsage is an invalid command line
+	KindUsage
+	// KindNotInProject means no devslot.yaml was found
+	KindNotInProject
+	// KindLockHeld means the project lock could not be acquired
+	KindLockHeld
+	// KindHookFailed means a hook failed or could not be run
+	KindHookFailed
+	// KindDoctorIssues means devslot doctor found problems
+	KindDoctorIssues
+)
+
+// Exit codes of the devslot command
+const (
+	ExitGeneric      = 1
+	ExitUsage        = 2
+	ExitNotInProject = 3
+	ExitLockHeld     = 4
+	ExitHookFailed   = 5
+	ExitDoctorIssues = 6
+)
+
+// ExitCode returns the process exit code for the kind
+func (k Kind) ExitCode() int {
+	switch k {
+	case KindUsage:
+		return ExitUsage
+	case KindNotInProject:
+		return ExitNotInProject
+	case KindLockHeld:
+		return ExitLockHeld
+	case KindHookFailed:
+		return ExitHookFailed
+	case KindDoctorIssues:
+		return ExitDoctorIssues
+	default:
+		return ExitGeneric
+	}
+}
+
+// ExitStatus is the non-zero exit status of a command run by devslot, which devslot exits with as well
+type ExitStatus int
+
+func (s ExitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// ExitCode returns the exit code for err, taken from the outermost UserError with a specific kind.
+// Errors without a kind that wrap an ExitStatus exit with that status.
+func ExitCode(err error) int {
+	var userErr *UserError
+	for stderrors.As(err, &userErr) {
+		if userErr.Kind != KindGeneric {
+			return userErr.Kind.ExitCode()
+		}
+		err = userErr.Err
+	}
+	var status ExitStatus
+	if stderrors.As(err, &status) && status > 0 {
+		return int(status)
+	}
+	return ExitGeneric
+}
+
+// withKind creates a new UserError of the given kind
+func withKind(kind Kind, err error, message, suggestion string) error {
+	return &UserError{
+		Err:        err,
+		Message:    message,
+		Suggestion: suggestion,
+		Kind:       kind,
+	}
+}
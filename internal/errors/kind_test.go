@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "plain error", err: errors.New("boom"), want: ExitGeneric},
+		{name: "generic user error", err: SlotNotFound("dev"), want: ExitGeneric},
+		{name: "usage", err: UnknownHookType("post-foo", []string{"post-create"}), want: ExitUsage},
+		{name: "not in project", err: ConfigNotFound("/tmp"), want: ExitNotInProject},
+		{name: "lock held", err: LockFailed(errors.New("busy")), want: ExitLockHeld},
+		{name: "hook failed", err: HookFailed("post-create", errors.New("exit status 3")), want: ExitHookFailed},
+		{name: "inline hook failed", err: InlineHookFailed("post-create", "false", errors.New("exit status 1")), want: ExitHookFailed},
+		{name: "doctor issues", err: DoctorIssues(), want: ExitDoctorIssues},
+		{name: "wrapped", err: fmt.Errorf("failed to create slot: %w", HookFailed("post-create", errors.New("x"))), want: ExitHookFailed},
+		{name: "generic wrapping a kind", err: WithSuggestion(LockFailed(errors.New("busy")), "reload failed", ""), want: ExitLockHeld},
+		{name: "note keeps kind", err: WithNote(ConfigNotFound("/tmp"), "note"), want: ExitNotInProject},
+		{name: "exit status", err: fmt.Errorf("code failed: %w", ExitStatus(7)), want: 7},
+		{name: "kind beats exit status", err: HookFailed("post-create", ExitStatus(7)), want: ExitHookFailed},
+		{name: "joined", err: errors.Join(errors.New("a"), DoctorIssues()), want: ExitDoctorIssues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// projectEnv holds the git_env of the project, added to the environment of every git command
+	projectEnvMu sync.RWMutex
+	projectEnv   map[string]string
+
+	// configuredBinary is the git_binary of the project, used when DEVSLOT_GIT is not set
+	configuredBinaryMu sync.RWMutex
+	configuredBinary   string
+)
+
+// SetEnv sets the environment variables, such as HTTPS_PROXY or GIT_SSH_COMMAND,
+// that every git command run by this package gets in addition to the environment
+// of the process. They take precedence over variables of the same name. A nil map
+// clears them.
+func SetEnv(env map[string]string) {
+	projectEnvMu.Lock()
+	defer projectEnvMu.Unlock()
+	projectEnv = maps.Clone(env)
+}
+
+// SetBinary sets the git program, a path or a name looked up on PATH, that this
+// package runs when the DEVSLOT_GIT environment variable is not set. An empty
+// path restores the git on PATH.
+func SetBinary(path string) {
+	configuredBinaryMu.Lock()
+	defer configuredBinaryMu.Unlock()
+	configuredBinary = path
+}
+
+// Binary returns the git program this package runs: DEVSLOT_GIT, the path set
+// with SetBinary, or git from PATH
+func Binary() string {
+	if path := os.Getenv("DEVSLOT_GIT"); path != "" {
+		return path
+	}
+	configuredBinaryMu.RLock()
+	defer configuredBinaryMu.RUnlock()
+	if configuredBinary != "" {
+		return configuredBinary
+	}
+	return "git"
+}
+
+// RedactEnv returns the value of an environment variable for display. Values of
+// variables whose names suggest a secret are hidden, and so are credentials in
+// URLs, such as those of proxies.
+func RedactEnv(key, value string) string {
+	upper := strings.ToUpper(key)
+	for _, secret := range []string{"TOKEN", "PASSWORD", "SECRET", "CREDENTIAL", "AUTH"} {
+		if strings.Contains(upper, secret) {
+			return redactedUserinfo
+		}
+	}
+	return RedactURL(value)
+}
+
+// command returns a git command with the project environment applied
+func command(args ...string) *exec.Cmd {
+	cmd := exec.Command(Binary(), args...)
+	cmd.Env = environ()
+	return cmd
+}
+
+// commandContext is like command but is killed when ctx is done
+func commandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, Binary(), args...)
+	cmd.Env = environ()
+	return cmd
+}
+
+// environ returns the environment of the process with the project environment
+// applied; exec uses the last value of a variable that appears twice
+func environ() []string {
+	projectEnvMu.RLock()
+	defer projectEnvMu.RUnlock()
+
+	env := os.Environ()
+	for _, key := range slices.Sorted(maps.Keys(projectEnv)) {
+		env = append(env, key+"="+projectEnv[key])
+	}
+	return env
+}
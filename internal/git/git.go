@@ -1,66 +1,552 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/yammerjp/devslot/internal/errors"
 )
 
-// CloneBare clones a repository as a bare repository
+// CloneBare clones a repository as a bare repository.
+// git's stderr is streamed as usual and its last lines are included in the returned error.
 func CloneBare(url, destPath string) error {
-	cmd := exec.Command("git", "clone", "--bare", url, destPath)
+	return CloneBareFiltered(url, destPath, "")
+}
+
+// CloneBareFiltered clones a repository as a bare partial clone using a filter spec such as
+// blob:none. An empty filter performs a full clone.
+func CloneBareFiltered(url, destPath, filter string) error {
+	return CloneBareWithOptions(url, destPath, CloneOptions{Filter: filter})
+}
+
+// CloneOptions configures CloneBareWithOptions
+type CloneOptions struct {
+	// Filter is a partial clone filter spec such as blob:none; empty for a full clone
+	Filter string
+	// NoHardlinks copies the objects of a local repository instead of hardlinking them
+	NoHardlinks bool
+	// Output receives the output of git; nil means the standard streams
+	Output io.Writer
+	// Progress makes git report its progress even when Output is not a terminal
+	Progress bool
+	// Remote names the remote of the clone; empty means origin
+	Remote string
+	// SingleBranch clones only one branch and keeps fetching only that branch
+	SingleBranch bool
+	// Branch is the branch of a single-branch clone; empty means the remote's default branch
+	Branch string
+}
+
+// CloneBareSingleBranch clones a single branch of a repository as a bare repository. Later
+// fetches only update that branch. An empty branch clones the remote's default branch.
+func CloneBareSingleBranch(url, destPath, branch string) error {
+	return CloneBareWithOptions(url, destPath, CloneOptions{SingleBranch: true, Branch: branch})
+}
+
+// CloneBareWithOptions clones a repository as a bare repository.
+// Local repositories (paths and file:// URLs) are cloned with hardlinked objects
+// unless opts.NoHardlinks is set or a filter is given, which needs the regular transport.
+func CloneBareWithOptions(url, destPath string, opts CloneOptions) error {
+	if opts.Filter != "" {
+		if err := requireVersion("partial clone (--filter)", versionPartialClone); err != nil {
+			return err
+		}
+	}
+	remote := remoteOrDefault(opts.Remote)
+	cloneURL := url
+	args := []string{"clone", "--bare", "--origin", remote}
+	if opts.Progress {
+		args = append(args, "--progress")
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+		if opts.Branch != "" {
+			args = append(args, "--branch", opts.Branch)
+		}
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	} else if _, isLocal := ParseRepoURL(url); isLocal {
+		// git ignores --local for file:// URLs, so clone from the path itself
+		cloneURL = strings.TrimPrefix(url, "file://")
+		if opts.NoHardlinks {
+			args = append(args, "--no-hardlinks")
+		} else {
+			args = append(args, "--local")
+		}
+	}
+	args = append(args, cloneURL, destPath)
+
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if opts.Output != nil {
+		stdout, stderr = opts.Output, opts.Output
+	}
+	var captured bytes.Buffer
+	cmd := command(args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = io.MultiWriter(stderr, &captured)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(withoutRedraws(captured.String())); msg != "" {
+			return fmt.Errorf("%w: %s", err, lastLines(msg, 3))
+		}
+		return err
+	}
+
+	// Keep the configured URL as the remote's URL so it matches devslot.yaml
+	if cloneURL != url {
+		if err := SetRemoteURL(destPath, remote, url); err != nil {
+			return err
+		}
+	}
+
+	// A bare clone copies the remote's branches as local branches only; record them as
+	// remote-tracking branches too, so origin/<default> is available without another fetch
+	if err := copyBranchesToRemote(destPath, remote); err != nil {
+		return err
+	}
+	if opts.SingleBranch {
+		if err := configureSingleBranch(destPath, remote); err != nil {
+			return err
+		}
+	}
+	return EnsureFetchConfig(destPath, remote)
+}
+
+// configureSingleBranch limits the fetch refspec of a single-branch clone to the branch
+// it cloned, which is its HEAD, and records that branch as the default branch so new
+// branches don't start from a remote default branch that was never fetched
+func configureSingleBranch(bareRepoPath, remote string) error {
+	branch := symbolicBranch(bareRepoPath, "HEAD", "refs/heads/")
+	if branch == "" {
+		return fmt.Errorf("failed to determine the branch of the single-branch clone")
+	}
+	for key, value := range map[string]string{
+		"remote." + remote + ".fetch": singleBranchRefspec(remote, branch),
+		defaultBranchConfigKey:        branch,
+	} {
+		if output, err := command("-C", bareRepoPath, "config", key, value).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to configure %s: %w: %s", key, err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// singleBranchRefspec stores one branch of remote as its remote-tracking branch
+func singleBranchRefspec(remote, branch string) string {
+	return "+refs/heads/" + branch + ":refs/remotes/" + remote + "/" + branch
+}
+
+// SingleBranch returns the branch a single-branch clone fetches from remote, or an empty
+// string when the remote's fetch refspecs cover all branches or several of them
+func SingleBranch(bareRepoPath, remote string) string {
+	branches := fetchedBranches(bareRepoPath, remote)
+	if len(branches) != 1 {
+		return ""
+	}
+	return branches[0]
+}
+
+// fetchedBranches returns the branches the fetch refspecs of remote are limited to, e.g.
+// by a single-branch clone widened with 'git remote set-branches --add', or nil when they
+// cover all branches
+func fetchedBranches(bareRepoPath, remote string) []string {
+	output, err := command("-C", bareRepoPath, "config", "--get-all", "remote."+remote+".fetch").Output()
+	if err != nil {
+		return nil
+	}
+	var branches []string
+	for _, refspec := range strings.Fields(string(output)) {
+		src, _, _ := strings.Cut(strings.TrimPrefix(refspec, "+"), ":")
+		branch, ok := strings.CutPrefix(src, "refs/heads/")
+		if !ok || branch == "" || strings.Contains(branch, "*") {
+			return nil
+		}
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+// remoteRefspecs returns the refspecs fetches from remote use: the branches of a
+// single-branch clone, and all branches otherwise
+func remoteRefspecs(bareRepoPath, remote string) []string {
+	branches := fetchedBranches(bareRepoPath, remote)
+	if len(branches) == 0 {
+		return []string{fetchRefspec(remote)}
+	}
+	refspecs := make([]string, len(branches))
+	for i, branch := range branches {
+		refspecs[i] = singleBranchRefspec(remote, branch)
+	}
+	return refspecs
+}
+
+// DefaultRemote is the remote devslot fetches from unless devslot.yaml names another
+const DefaultRemote = "origin"
+
+// remoteOrDefault returns remote, or DefaultRemote when it is empty
+func remoteOrDefault(remote string) string {
+	if remote == "" {
+		return DefaultRemote
+	}
+	return remote
+}
+
+// copyBranchesToRemote creates refs/remotes/<remote>/<branch> for every local branch
+func copyBranchesToRemote(bareRepoPath, remote string) error {
+	output, err := command("-C", bareRepoPath, "for-each-ref", "--format=%(objectname) %(refname:lstrip=2)", "refs/heads/").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var updates strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if sha, branch, ok := strings.Cut(line, " "); ok {
+			fmt.Fprintf(&updates, "update refs/remotes/%s/%s %s\n", remote, branch, sha)
+		}
+	}
+	if updates.Len() == 0 {
+		return nil
+	}
+
+	cmd := command("-C", bareRepoPath, "update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(updates.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create remote-tracking branches: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// fetchRefspec stores the branches of remote as its remote-tracking branches
+func fetchRefspec(remote string) string {
+	return "+refs/heads/*:refs/remotes/" + remote + "/*"
+}
+
+// EnsureFetchConfig configures the remote's fetch refspec, which git clone --bare leaves unset,
+// and points refs/remotes/<remote>/HEAD at the default branch when it is missing.
+// Repositories without the remote are left unchanged.
+func EnsureFetchConfig(bareRepoPath, remote string) error {
+	if !HasRemote(bareRepoPath, remote) {
+		return nil
+	}
+
+	if getRepoConfig(bareRepoPath, "remote."+remote+".fetch") == "" {
+		if output, err := command("-C", bareRepoPath, "config", "remote."+remote+".fetch", fetchRefspec(remote)).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to configure fetch refspec: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	tracking := "refs/remotes/" + remote + "/"
+	if symbolicBranch(bareRepoPath, tracking+"HEAD", tracking) != "" {
+		return nil
+	}
+	// The cached default branch, or the HEAD the bare clone inherited from the remote
+	branch := getRepoConfig(bareRepoPath, defaultBranchConfigKey)
+	if branch == "" {
+		branch = symbolicBranch(bareRepoPath, "HEAD", "refs/heads/")
+	}
+	if branch == "" || !RefExists(bareRepoPath, tracking+branch) {
+		return nil
+	}
+	if output, err := command("-C", bareRepoPath, "symbolic-ref", tracking+"HEAD", tracking+branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set %s/HEAD: %w: %s", remote, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// EnsurePushConfig makes a plain git push work for new branches of the repository's
+// worktrees: push.autoSetupRemote pushes a branch without an upstream to the branch of
+// the same name and sets it as the upstream, and remote.pushDefault points git push at
+// the remote when it is not origin. Settings already made, in any git config file, are
+// kept. Repositories without the remote are left unchanged.
+func EnsurePushConfig(bareRepoPath, remote string) error {
+	if !HasRemote(bareRepoPath, remote) {
+		return nil
+	}
+
+	settings := [][2]string{{"push.autoSetupRemote", "true"}}
+	if remote != DefaultRemote {
+		settings = append(settings, [2]string{"remote.pushDefault", remote})
+	}
+	for _, setting := range settings {
+		if getRepoConfig(bareRepoPath, setting[0]) != "" {
+			continue
+		}
+		if output, err := command("-C", bareRepoPath, "config", setting[0], setting[1]).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to configure %s: %w: %s", setting[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// PartialCloneFilter returns the filter of a partial clone from remote, or an empty string for a full clone
+func PartialCloneFilter(repoPath, remote string) string {
+	output, err := command("-C", repoPath, "config", "--bool", "--get", "remote."+remote+".promisor").Output()
+	if err != nil || strings.TrimSpace(string(output)) != "true" {
+		return ""
+	}
+
+	output, err = command("-C", repoPath, "config", "--get", "remote."+remote+".partialclonefilter").Output()
+	if err != nil {
+		return "unknown filter"
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// IsShallow reports whether a repository has truncated history
+func IsShallow(repoPath string) bool {
+	output, err := command("-C", repoPath, "rev-parse", "--is-shallow-repository").Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// lastLines returns at most n trailing lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withoutRedraws keeps the final state of lines that git --progress redraws with
+// carriage returns, e.g. "Receiving objects:  50%\rReceiving objects: 100%"
+func withoutRedraws(output string) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		lines[i] = line[strings.LastIndexByte(line, '\r')+1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bundleConfigKey records the bundle a bare repository was last populated from
+const bundleConfigKey = "devslot.bundle"
+
+// originFetchedConfigKey records that the remote has been fetched directly at least once
+const originFetchedConfigKey = "devslot.originFetched"
+
+// CloneBareFromBundle clones a bare repository from a git bundle.
+// The remote is pointed at url so the repository can be fetched directly once connectivity exists,
+// and the bundle's branches are stored as the remote's remote-tracking branches.
+func CloneBareFromBundle(bundlePath, url, destPath, remote string) error {
+	cmd := command("clone", "--bare", "--origin", remote, bundlePath, destPath)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	if err := command("-C", destPath, "remote", "set-url", remote, url).Run(); err != nil {
+		return fmt.Errorf("failed to set %s URL: %w", remote, err)
+	}
+
+	if err := FetchBundle(destPath, remote, bundlePath); err != nil {
+		return err
+	}
+
+	// Point <remote>/HEAD at the bundle's HEAD branch so the default branch can be resolved offline
+	return EnsureFetchConfig(destPath, remote)
+}
+
+// FetchBundle fetches the branches of a git bundle into the remote's remote-tracking branches.
+// Only objects missing from the repository are transferred, so incremental bundles are supported.
+func FetchBundle(bareRepoPath, remote, bundlePath string) error {
+	cmd := command("-C", bareRepoPath, "fetch", bundlePath, fetchRefspec(remote))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	return command("-C", bareRepoPath, "config", bundleConfigKey, bundlePath).Run()
+}
+
+// BundleSource returns the bundle a repository was populated from, or an empty string
+func BundleSource(bareRepoPath string) string {
+	output, err := command("-C", bareRepoPath, "config", "--get", bundleConfigKey).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// OriginFetched reports whether the remote has ever been fetched directly
+func OriginFetched(bareRepoPath string) bool {
+	output, err := command("-C", bareRepoPath, "config", "--bool", "--get", originFetchedConfigKey).Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// ShouldFetchOrigin reports whether updates should be fetched from the remote.
+// Repositories initialized from bundles keep using the bundled refs until the remote has been fetched directly.
+func ShouldFetchOrigin(bareRepoPath, remote string) bool {
+	if !HasRemote(bareRepoPath, remote) {
+		return false
+	}
+	return BundleSource(bareRepoPath) == "" || OriginFetched(bareRepoPath)
 }
 
-// CreateWorktree creates a new worktree for a bare repository
+// CreateWorktree creates a new worktree for a bare repository. git's messages,
+// such as "HEAD is now at", go to stderr so that stdout stays machine-readable.
 func CreateWorktree(bareRepoPath, worktreePath, branch string) error {
 	// First, check if the branch exists
-	checkCmd := exec.Command("git", "-C", bareRepoPath, "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
+	checkCmd := command("-C", bareRepoPath, "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
 	if err := checkCmd.Run(); err != nil {
 		// Branch doesn't exist, create worktree with a new branch
-		cmd := exec.Command("git", "-C", bareRepoPath, "worktree", "add", "-b", branch, worktreePath)
-		cmd.Stdout = os.Stdout
+		cmd := command("-C", bareRepoPath, "worktree", "add", "-b", branch, worktreePath)
+		cmd.Stdout = os.Stderr
 		cmd.Stderr = os.Stderr
 		return cmd.Run()
 	}
 
 	// Branch exists, create worktree tracking the existing branch
-	cmd := exec.Command("git", "-C", bareRepoPath, "worktree", "add", worktreePath, branch)
-	cmd.Stdout = os.Stdout
+	cmd := command("-C", bareRepoPath, "worktree", "add", worktreePath, branch)
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
 // RemoveWorktree removes a worktree
 func RemoveWorktree(bareRepoPath, worktreePath string) error {
-	cmd := exec.Command("git", "-C", bareRepoPath, "worktree", "remove", worktreePath)
+	cmd := command("-C", bareRepoPath, "worktree", "remove", worktreePath)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-// ListWorktrees lists all worktrees for a bare repository
+// ForceRemoveWorktree removes a worktree even if it has uncommitted changes or is locked
+func ForceRemoveWorktree(bareRepoPath, worktreePath string) error {
+	cmd := command("-C", bareRepoPath, "worktree", "remove", "--force", "--force", worktreePath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ListWorktrees lists the paths of all worktrees registered for a bare repository.
+// The bare repository itself is not included.
 func ListWorktrees(bareRepoPath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", bareRepoPath, "worktree", "list", "--porcelain")
+	cmd := command("-C", bareRepoPath, "worktree", "list", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse the output to extract worktree paths
-	// This is a simplified implementation
+	return parseWorktreeList(string(output)), nil
+}
+
+// BranchWorktree returns the worktree of a bare repository that has branch checked out,
+// or an empty string when no worktree has
+func BranchWorktree(bareRepoPath, branch string) string {
+	output, err := command("-C", bareRepoPath, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return ""
+	}
+	for _, block := range strings.Split(strings.TrimSpace(string(output)), "\n\n") {
+		var path string
+		for _, line := range strings.Split(block, "\n") {
+			if p, ok := strings.CutPrefix(line, "worktree "); ok {
+				path = p
+			} else if line == "branch refs/heads/"+branch {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// parseWorktreeList extracts worktree paths from 'git worktree list --porcelain' output.
+// Entries are separated by blank lines; bare entries are skipped.
+func parseWorktreeList(output string) []string {
 	worktrees := []string{}
-	lines := string(output)
-	// TODO: Implement proper parsing
-	_ = lines
-	return worktrees, nil
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		var path string
+		bare := false
+		for _, line := range strings.Split(block, "\n") {
+			if p, ok := strings.CutPrefix(line, "worktree "); ok {
+				path = p
+			} else if line == "bare" {
+				bare = true
+			}
+		}
+		if path != "" && !bare {
+			worktrees = append(worktrees, path)
+		}
+	}
+	return worktrees
+}
+
+// WorktreeGitDir returns the git directory that the .git file of a linked worktree points at
+func WorktreeGitDir(worktreePath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%s is not a linked worktree", worktreePath)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return gitDir, nil
+}
+
+// CheckWorktree verifies that a worktree is still connected to its repository:
+// the git directory its .git file points at exists and git recognizes it
+func CheckWorktree(worktreePath string) error {
+	if info, err := os.Stat(filepath.Join(worktreePath, ".git")); err == nil && !info.IsDir() {
+		gitDir, err := WorktreeGitDir(worktreePath)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(gitDir); err != nil {
+			return fmt.Errorf("its git directory %s does not exist", gitDir)
+		}
+	}
+	if output, err := command("-C", worktreePath, "rev-parse", "--git-dir").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RepairWorktrees reconnects a bare repository and its worktrees after they were moved,
+// rewriting the paths recorded on both sides
+func RepairWorktrees(bareRepoPath string, worktreePaths ...string) error {
+	if err := requireVersion("git worktree repair", versionWorktreeRepair); err != nil {
+		return err
+	}
+	args := append([]string{"-C", bareRepoPath, "worktree", "repair"}, worktreePaths...)
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to repair worktrees: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PruneWorktrees removes registrations of worktrees whose directories no longer exist.
+// It returns git's report of each pruned entry; with dryRun nothing is removed.
+func PruneWorktrees(bareRepoPath string, dryRun bool) ([]string, error) {
+	args := []string{"-C", bareRepoPath, "worktree", "prune", "--verbose"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	output, err := command(args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	var pruned []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			pruned = append(pruned, line)
+		}
+	}
+	return pruned, nil
 }
 
 // IsValidRepository checks if a path is a valid git repository
@@ -71,7 +557,7 @@ func IsValidRepository(path string) bool {
 	}
 
 	// Check if it's a bare repository
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--is-bare-repository")
+	cmd := command("-C", path, "rev-parse", "--is-bare-repository")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -82,7 +568,7 @@ func IsValidRepository(path string) bool {
 
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(repoPath string) (string, error) {
-	cmd := exec.Command("git", "-C", repoPath, "branch", "--show-current")
+	cmd := command("-C", repoPath, "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
@@ -96,44 +582,52 @@ func GetCurrentBranch(repoPath string) (string, error) {
 	return branch, nil
 }
 
-// GetDefaultBranch returns the default branch name for a repository
-func GetDefaultBranch(bareRepoPath string) (string, error) {
-	// Try to get the symbolic ref for HEAD
-	cmd := exec.Command("git", "-C", bareRepoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
-	output, err := cmd.Output()
-	if err == nil {
-		// Extract branch name from refs/remotes/origin/main
-		branch := string(output)
-		if len(branch) > 0 && branch[len(branch)-1] == '\n' {
-			branch = branch[:len(branch)-1]
-		}
-		// Remove the refs/remotes/origin/ prefix
-		const prefix = "refs/remotes/origin/"
-		if len(branch) > len(prefix) {
-			return branch[len(prefix):], nil
+// defaultBranchConfigKey caches the default branch reported by the remote in the bare repository
+const defaultBranchConfigKey = "devslot.defaultBranch"
+
+// lsRemoteTimeout bounds the network lookup of the remote's default branch
+const lsRemoteTimeout = 10 * time.Second
+
+// GetDefaultBranch returns the default branch name for a repository. It is taken from, in order:
+// the devslot.defaultBranch cache, the remote's HEAD (via ls-remote, which fills the cache),
+// refs/remotes/<remote>/HEAD, the bare repository's own HEAD, and finally main, master or the first branch.
+func GetDefaultBranch(bareRepoPath, remote string) (string, error) {
+	if branch := getRepoConfig(bareRepoPath, defaultBranchConfigKey); branch != "" {
+		return branch, nil
+	}
+
+	// Repositories initialized from bundles stay offline until the remote is fetched directly
+	if ShouldFetchOrigin(bareRepoPath, remote) {
+		if branch := remoteDefaultBranch(bareRepoPath, remote); branch != "" {
+			_ = command("-C", bareRepoPath, "config", defaultBranchConfigKey, branch).Run()
+			return branch, nil
 		}
 	}
 
+	// Local symbolic refs, as long as they point at an existing branch
+	if branch := symbolicBranch(bareRepoPath, "refs/remotes/"+remote+"/HEAD", "refs/remotes/"+remote+"/"); branch != "" {
+		return branch, nil
+	}
+	if branch := symbolicBranch(bareRepoPath, "HEAD", "refs/heads/"); branch != "" && RefExists(bareRepoPath, "refs/heads/"+branch) {
+		return branch, nil
+	}
+
 	// Fallback: check common default branch names
 	for _, branch := range []string{"main", "master"} {
-		checkCmd := exec.Command("git", "-C", bareRepoPath, "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
+		checkCmd := command("-C", bareRepoPath, "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branch))
 		if err := checkCmd.Run(); err == nil {
 			return branch, nil
 		}
 	}
 
 	// Last resort: get the first branch
-	cmd = exec.Command("git", "-C", bareRepoPath, "for-each-ref", "--format=%(refname:short)", "--count=1", "refs/heads/")
-	output, err = cmd.Output()
+	cmd := command("-C", bareRepoPath, "for-each-ref", "--format=%(refname:short)", "--count=1", "refs/heads/")
+	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to find any branch: %w", err)
 	}
 
-	branch := string(output)
-	if len(branch) > 0 && branch[len(branch)-1] == '\n' {
-		branch = branch[:len(branch)-1]
-	}
-
+	branch := strings.TrimSpace(string(output))
 	if branch == "" {
 		return "", errors.NoBranchesFound()
 	}
@@ -141,38 +635,186 @@ func GetDefaultBranch(bareRepoPath string) (string, error) {
 	return branch, nil
 }
 
-// Fetch fetches updates from origin
-func Fetch(bareRepoPath string) error {
-	cmd := exec.Command("git", "-C", bareRepoPath, "fetch", "origin", "+refs/heads/*:refs/remotes/origin/*")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+// remoteDefaultBranch asks the remote which branch its HEAD points at.
+// It returns an empty string when the remote can't be reached in time.
+func remoteDefaultBranch(bareRepoPath, remote string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), lsRemoteTimeout)
+	defer cancel()
+
+	cmd := commandContext(ctx, "-C", bareRepoPath, "ls-remote", "--symref", remote, "HEAD")
+	// Never block on a credential prompt
+	cmd.Env = append(cmd.Env, "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return parseSymref(string(output))
+}
+
+// parseSymref extracts the branch from 'git ls-remote --symref <remote> HEAD' output,
+// whose first line looks like "ref: refs/heads/main<TAB>HEAD"
+func parseSymref(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		target, name, ok := strings.Cut(strings.TrimPrefix(line, "ref: "), "\t")
+		if !ok || !strings.HasPrefix(line, "ref: ") || name != "HEAD" {
+			continue
+		}
+		return strings.TrimPrefix(target, "refs/heads/")
+	}
+	return ""
+}
+
+// symbolicBranch resolves a symbolic ref and strips prefix from its target.
+// It returns an empty string if ref isn't a symbolic ref below prefix.
+func symbolicBranch(repoPath, ref, prefix string) string {
+	output, err := command("-C", repoPath, "symbolic-ref", ref).Output()
+	if err != nil {
+		return ""
+	}
+	target := strings.TrimSpace(string(output))
+	if !strings.HasPrefix(target, prefix) || len(target) == len(prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(target, prefix)
+}
+
+// getRepoConfig reads a git config value of a repository
+func getRepoConfig(repoPath, key string) string {
+	output, err := command("-C", repoPath, "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Fetch fetches updates from the remote
+func Fetch(bareRepoPath, remote string) error {
+	return fetchRemote(bareRepoPath, remote, os.Stdout, os.Stderr)
 }
 
-// GetBranchPrefix returns the branch prefix for new branches
-func GetBranchPrefix() string {
+// FetchUpdated fetches updates from the remote without streaming git's output,
+// reporting whether any of the remote's remote-tracking branches changed
+func FetchUpdated(bareRepoPath, remote string) (bool, error) {
+	before, err := remoteRefs(bareRepoPath, remote)
+	if err != nil {
+		return false, err
+	}
+
+	var output bytes.Buffer
+	if err := fetchRemote(bareRepoPath, remote, &output, &output); err != nil {
+		return false, fmt.Errorf("%w: %s", err, strings.TrimSpace(output.String()))
+	}
+
+	after, err := remoteRefs(bareRepoPath, remote)
+	if err != nil {
+		return false, err
+	}
+	return before != after, nil
+}
+
+// Unshallow fetches the full history of a shallow repository from the remote without
+// streaming git's output, which is included in the returned error instead
+func Unshallow(bareRepoPath, remote string) error {
+	args := append([]string{"-C", bareRepoPath, "fetch", "--unshallow", remote}, remoteRefspecs(bareRepoPath, remote)...)
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+	return nil
+}
+
+// remoteRefs returns a snapshot of the remote's remote-tracking branches
+func remoteRefs(bareRepoPath, remote string) (string, error) {
+	output, err := command("-C", bareRepoPath, "for-each-ref", "--format=%(objectname) %(refname)", "refs/remotes/"+remote+"/").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list remote refs: %w", err)
+	}
+	return string(output), nil
+}
+
+// fetchRemote fetches the branches of the remote (only one for single-branch clones) into its remote-tracking branches
+func fetchRemote(bareRepoPath, remote string, stdout, stderr io.Writer) error {
+	cmd := command(append([]string{"-C", bareRepoPath, "fetch", remote}, remoteRefspecs(bareRepoPath, remote)...)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	// Remember the first direct fetch of repositories initialized from bundles
+	if BundleSource(bareRepoPath) != "" && !OriginFetched(bareRepoPath) {
+		_ = command("-C", bareRepoPath, "config", originFetchedConfigKey, "true").Run()
+	}
+	return nil
+}
+
+// DefaultBranchPrefix is the branch name template used when no prefix is configured
+const DefaultBranchPrefix = "devslot/{user}/"
+
+// GetBranchPrefix returns the branch name template for new branches.
+// configured is the branch_prefix setting of devslot.yaml.
+func GetBranchPrefix(configured string) string {
 	// 1. Environment variable (for temporary override)
 	if prefix := os.Getenv("DEVSLOT_BRANCH_PREFIX"); prefix != "" {
 		return prefix
 	}
 
-	// 2. Git config (persistent setting)
+	// 2. devslot.yaml (shared project convention)
+	if configured != "" {
+		return configured
+	}
+
+	// 3. Git config (persistent personal setting)
 	if prefix := getGitConfig("devslot.branchPrefix"); prefix != "" {
 		return prefix
 	}
 
-	// 3. Git email local part (default)
-	if localPart := getGitEmailLocalPart(); localPart != "" {
-		return fmt.Sprintf("devslot/%s/", localPart)
-	}
+	// 4. devslot/<git email local part>/ (default)
+	return DefaultBranchPrefix
+}
+
+// branchPlaceholder matches {user}, {slot}, {date} and {date:<Go time layout>}
+var branchPlaceholder = regexp.MustCompile(`\{(user|slot|date)(?::([^}]*))?\}`)
 
-	// 4. Fallback
-	return "devslot/user/"
+// now returns the current time; replaced in tests
+var now = time.Now
+
+// RenderBranchName expands the placeholders of a branch name template for a slot.
+// Each substituted value is sanitized with SanitizeBranchComponent. The slot name is
+// appended as it is unless the template contains {slot}, so that slots whose names
+// differ only in case or punctuation get different branches.
+func RenderBranchName(template, slotName string) string {
+	hasSlot := false
+	user := ""
+	name := branchPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		match := branchPlaceholder.FindStringSubmatch(placeholder)
+		switch match[1] {
+		case "user":
+			// Look up git config at most once, however often {user} appears
+			if user == "" {
+				user = SanitizeBranchComponent(getGitEmailLocalPart())
+			}
+			return user
+		case "slot":
+			hasSlot = true
+			return SanitizeBranchComponent(slotName)
+		default:
+			layout := match[2]
+			if layout == "" {
+				layout = "2006-01-02"
+			}
+			return SanitizeBranchComponent(now().Format(layout))
+		}
+	})
+
+	if !hasSlot {
+		name += slotName
+	}
+	return name
 }
 
 // getGitConfig reads a git config value
 func getGitConfig(key string) string {
-	cmd := exec.Command("git", "config", "--get", key)
+	cmd := command("config", "--get", key)
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -221,62 +863,326 @@ func SanitizeBranchComponent(name string) string {
 	return name
 }
 
-// CreateWorktreeWithFetch creates a new worktree after fetching latest changes
-func CreateWorktreeWithFetch(bareRepoPath, worktreePath, slotName string) error {
-	// Check if remote origin exists
-	checkRemoteCmd := exec.Command("git", "-C", bareRepoPath, "remote", "get-url", "origin")
-	if err := checkRemoteCmd.Run(); err != nil {
-		// No remote origin, create without fetch (for tests)
-		return CreateWorktreeWithoutFetch(bareRepoPath, worktreePath, slotName)
+// CreateWorktreeWithFetch creates a worktree for branchName after fetching latest changes from remote.
+// A new branch starts at <remote>/<default branch>; see addBranchWorktree for existing branches.
+func CreateWorktreeWithFetch(bareRepoPath, remote, worktreePath, branchName string) error {
+	return CreateWorktreeFrom(bareRepoPath, remote, worktreePath, branchName, "")
+}
+
+// CreateWorktreeFrom is like CreateWorktreeWithFetch, but a new branch starts at base
+// (a branch, preferably from the remote, a tag or a commit) unless base is empty
+func CreateWorktreeFrom(bareRepoPath, remote, worktreePath, branchName, base string) error {
+	// Check if the remote exists
+	if !HasRemote(bareRepoPath, remote) {
+		// No remote, create without fetch (for tests)
+		if base != "" {
+			return addBranchWorktree(bareRepoPath, remote, worktreePath, branchName, base)
+		}
+		return CreateWorktreeWithoutFetch(bareRepoPath, worktreePath, branchName)
 	}
 
-	// 1. Fetch latest changes
-	if err := Fetch(bareRepoPath); err != nil {
-		return errors.FetchFailed(err)
+	// 1. Fetch latest changes (bundle-initialized repositories use their bundled refs)
+	if ShouldFetchOrigin(bareRepoPath, remote) {
+		if err := Fetch(bareRepoPath, remote); err != nil {
+			return errors.FetchFailed(err)
+		}
 	}
 
-	// 2. Get default branch
-	defaultBranch, err := GetDefaultBranch(bareRepoPath)
+	// 2. Start new branches from <remote>/<default branch>, or from the base, preferring
+	// its fetched state when it is a branch
+	switch {
+	case base == "":
+		defaultBranch, err := GetDefaultBranch(bareRepoPath, remote)
+		if err != nil {
+			return err
+		}
+		base = remote + "/" + defaultBranch
+	case RefExists(bareRepoPath, "refs/remotes/"+remote+"/"+base):
+		base = remote + "/" + base
+	case !BranchExists(bareRepoPath, remote, branchName) && !CommitExists(bareRepoPath, base):
+		// Other branches are never fetched into a single-branch clone
+		if branch := SingleBranch(bareRepoPath, remote); branch != "" {
+			return errors.BaseNotInSingleBranch(base, branch, bareRepoPath, remote)
+		}
+	}
+
+	// 3. Create worktree for the branch, starting new branches from the base
+	return addBranchWorktree(bareRepoPath, remote, worktreePath, branchName, base)
+}
+
+// CreateWorktreeWithoutFetch creates a worktree for branchName without fetching (for local/test repos).
+// A new branch starts at the local default branch.
+func CreateWorktreeWithoutFetch(bareRepoPath, worktreePath, branchName string) error {
+	// Get default branch
+	defaultBranch, err := GetDefaultBranch(bareRepoPath, DefaultRemote)
 	if err != nil {
 		return err
 	}
 
-	// 3. Generate branch name
-	prefix := GetBranchPrefix()
-	branchName := fmt.Sprintf("%s%s", prefix, slotName)
+	return addBranchWorktree(bareRepoPath, DefaultRemote, worktreePath, branchName, defaultBranch)
+}
 
-	// 4. Create worktree with new branch from origin/defaultBranch
-	cmd := exec.Command("git", "-C", bareRepoPath,
-		"worktree", "add", "-b", branchName,
-		worktreePath,
-		fmt.Sprintf("origin/%s", defaultBranch))
+// addBranchWorktree adds a worktree for branch. An existing local branch is checked out,
+// a branch that only exists on the remote gets a local branch tracking it, and otherwise
+// a new branch is created at startPoint.
+func addBranchWorktree(bareRepoPath, remote, worktreePath, branch, startPoint string) error {
+	var args []string
+	track := false
+	switch {
+	case RefExists(bareRepoPath, "refs/heads/"+branch):
+		args = []string{"worktree", "add", worktreePath, branch}
+	case RefExists(bareRepoPath, "refs/remotes/"+remote+"/"+branch):
+		args = []string{"worktree", "add", "--no-track", "-b", branch, worktreePath, remote + "/" + branch}
+		track = true
+	default:
+		// Without --no-track a new branch would track <remote>/<default>, and a plain
+		// git push refuses to push to an upstream of another name
+		args = []string{"worktree", "add", "--no-track", "-b", branch, worktreePath, startPoint}
+	}
 
-	cmd.Stdout = os.Stdout
+	// Like CreateWorktree, keep git's messages out of stdout
+	cmd := command(append([]string{"-C", bareRepoPath}, args...)...)
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
 
-	return cmd.Run()
+	// Bare clones have no fetch refspec, so git can't set up tracking itself
+	if track {
+		for key, value := range map[string]string{
+			"branch." + branch + ".remote": remote,
+			"branch." + branch + ".merge":  "refs/heads/" + branch,
+		} {
+			if output, err := command("-C", bareRepoPath, "config", key, value).CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to track %s/%s: %w: %s", remote, branch, err, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+	return nil
 }
 
-// CreateWorktreeWithoutFetch creates a new worktree without fetching (for local/test repos)
-func CreateWorktreeWithoutFetch(bareRepoPath, worktreePath, slotName string) error {
-	// Get default branch
-	defaultBranch, err := GetDefaultBranch(bareRepoPath)
+// BranchExists reports whether branch exists locally or as a remote-tracking branch of the remote
+func BranchExists(repoPath, remote, branch string) bool {
+	return RefExists(repoPath, "refs/heads/"+branch) || RefExists(repoPath, "refs/remotes/"+remote+"/"+branch)
+}
+
+// UniqueBranchName returns branch, or branch with the first numeric suffix (-2, -3, ...)
+// that doesn't exist in any of the repositories. remotes maps the path of each repository
+// to the remote whose branches count as well.
+func UniqueBranchName(branch string, remotes map[string]string) string {
+	exists := func(name string) bool {
+		for repoPath, remote := range remotes {
+			if BranchExists(repoPath, remote, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	candidate := branch
+	for n := 2; exists(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d", branch, n)
+	}
+	return candidate
+}
+
+// HasRemote checks if the repository has the named remote configured
+func HasRemote(repoPath, remote string) bool {
+	cmd := command("-C", repoPath, "remote", "get-url", remote)
+	return cmd.Run() == nil
+}
+
+// Remotes returns the names of the remotes configured in the repository
+func Remotes(repoPath string) ([]string, error) {
+	output, err := command("-C", repoPath, "remote").Output()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// GetRemoteURL returns the URL of the named remote
+func GetRemoteURL(repoPath, remote string) (string, error) {
+	output, err := command("-C", repoPath, "remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetRemoteURL changes the URL of the named remote
+func SetRemoteURL(repoPath, remote, url string) error {
+	if output, err := command("-C", repoPath, "remote", "set-url", remote, url).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set URL of remote %s: %w: %s", remote, err, strings.TrimSpace(string(output)))
 	}
+	return nil
+}
+
+// SameURL reports whether two repository URLs point at the same repository,
+// ignoring trivial differences such as a trailing slash or .git suffix
+func SameURL(a, b string) bool {
+	return normalizeURL(a) == normalizeURL(b)
+}
+
+// normalizeURL strips parts of a repository URL that don't change which repository it refers to
+func normalizeURL(url string) string {
+	url = strings.TrimSpace(url)
+	url = strings.TrimRight(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	return strings.TrimRight(url, "/")
+}
 
-	// Generate branch name
-	prefix := GetBranchPrefix()
-	branchName := fmt.Sprintf("%s%s", prefix, slotName)
+// RefExists checks if a fully-qualified ref (e.g. refs/heads/main) exists
+func RefExists(repoPath, ref string) bool {
+	cmd := command("-C", repoPath, "show-ref", "--verify", "--quiet", ref)
+	return cmd.Run() == nil
+}
 
-	// Create worktree with new branch from local defaultBranch
-	cmd := exec.Command("git", "-C", bareRepoPath,
-		"worktree", "add", "-b", branchName,
-		worktreePath,
-		defaultBranch)
+// CommitExists reports whether rev (a branch, tag or commit) names a commit of the repository
+func CommitExists(repoPath, rev string) bool {
+	return command("-C", repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}").Run() == nil
+}
 
+// IsDirty reports whether a worktree has uncommitted or untracked changes
+func IsDirty(worktreePath string) (bool, error) {
+	cmd := command("-C", worktreePath, "status", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// Stash stashes all changes in a worktree, including untracked files
+func Stash(worktreePath, message string) error {
+	cmd := command("-C", worktreePath, "stash", "push", "--include-untracked", "-m", message)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
 
+// SwitchBranch switches a worktree to a branch.
+// When the local branch does not exist, it is created tracking <remote>/<branch>.
+func SwitchBranch(worktreePath, remote, branch string) error {
+	var cmd *exec.Cmd
+	if RefExists(worktreePath, fmt.Sprintf("refs/heads/%s", branch)) {
+		cmd = command("-C", worktreePath, "switch", branch)
+	} else {
+		cmd = command("-C", worktreePath, "switch", "-c", branch, "--track", remote+"/"+branch)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
+
+// Upstream returns the upstream of the branch checked out in a worktree (e.g. origin/main),
+// or an empty string when it has none
+func Upstream(worktreePath string) string {
+	output, err := command("-C", worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// HeadCommit returns the commit checked out in a worktree
+func HeadCommit(worktreePath string) (string, error) {
+	output, err := command("-C", worktreePath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FastForward fast-forwards the branch checked out in a worktree to its upstream.
+// It fails without changing anything when the branch has diverged.
+func FastForward(worktreePath string) error {
+	if output, err := command("-C", worktreePath, "merge", "--ff-only", "@{upstream}").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+	return nil
+}
+
+// RebaseOntoUpstream rebases the branch checked out in a worktree onto its upstream.
+// A rebase that stops on conflicts is aborted, leaving the worktree as it was.
+func RebaseOntoUpstream(worktreePath string) error {
+	output, err := command("-C", worktreePath, "rebase", "@{upstream}").CombinedOutput()
+	if err != nil {
+		_ = command("-C", worktreePath, "rebase", "--abort").Run()
+		return fmt.Errorf("%w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+	return nil
+}
+
+// DiffFiles returns a unified diff between two files, given relative to dir.
+// It returns an empty string when the files are identical.
+func DiffFiles(dir, a, b string) (string, error) {
+	cmd := command("diff", "--no-index", "--no-color", "--", a, b)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		// Exit status 1 means the files differ
+		return string(output), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// TopLevel returns the root of the working tree containing dir, or an error when
+// dir is not inside a git working tree
+func TopLevel(dir string) (string, error) {
+	output, err := command("-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git working tree: %w", dir, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Init creates a git repository in dir. The initial branch follows the user's
+// init.defaultBranch setting.
+func Init(dir string) error {
+	if output, err := command("-C", dir, "init", "--quiet").CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+	return nil
+}
+
+// CommitPaths stages paths, given relative to dir, and commits only those paths.
+// It reports false without committing when they have no changes.
+func CommitPaths(dir, message string, paths ...string) (bool, error) {
+	args := append([]string{"-C", dir, "add", "--"}, paths...)
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("failed to stage files: %w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+
+	args = append([]string{"-C", dir, "diff", "--cached", "--quiet", "--"}, paths...)
+	if err := command(args...).Run(); err == nil {
+		return false, nil
+	}
+
+	args = append([]string{"-C", dir, "commit", "--quiet", "-m", message, "--"}, paths...)
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("%w: %s", err, lastLines(strings.TrimSpace(string(output)), 3))
+	}
+	return true, nil
+}
+
+// TrackedFiles lists the files under paths, given relative to dir, that the
+// git working tree containing dir tracks
+func TrackedFiles(dir string, paths ...string) ([]string, error) {
+	args := append([]string{"-C", dir, "ls-files", "-z", "--"}, paths...)
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,34 @@
+package git
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// CheckInstalled fails with installation instructions when the git program
+// devslot runs cannot be found, which would otherwise surface as an exec error
+// wrapped in the first clone or worktree that fails
+func CheckInstalled() (string, error) {
+	binary := Binary()
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", errors.GitNotFound(binary, err, installHint(runtime.GOOS))
+	}
+	return path, nil
+}
+
+// installHint returns how to install git on an operating system
+func installHint(goos string) string {
+	switch goos {
+	case "darwin":
+		return "run 'xcode-select --install' or 'brew install git'"
+	case "windows":
+		return "install Git for Windows from https://git-scm.com/download/win or run 'winget install Git.Git'"
+	case "linux":
+		return "use your package manager, e.g. 'sudo apt install git' or 'sudo dnf install git'"
+	default:
+		return "see https://git-scm.com/downloads"
+	}
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	t.Setenv("DEVSLOT_GIT", "")
+	t.Cleanup(func() { SetBinary("") })
+
+	if got := Binary(); got != "git" {
+		t.Errorf("Binary() = %q, want git", got)
+	}
+	SetBinary("/opt/git/bin/git")
+	if got := Binary(); got != "/opt/git/bin/git" {
+		t.Errorf("Binary() = %q, want the configured binary", got)
+	}
+	t.Setenv("DEVSLOT_GIT", "/usr/local/bin/git")
+	if got := Binary(); got != "/usr/local/bin/git" {
+		t.Errorf("Binary() = %q, want DEVSLOT_GIT to take precedence", got)
+	}
+}
+
+func TestCheckInstalled(t *testing.T) {
+	t.Setenv("DEVSLOT_GIT", "")
+	want, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	if path, err := CheckInstalled(); err != nil || path != want {
+		t.Errorf("CheckInstalled() = %q, %v, want %q", path, err, want)
+	}
+
+	t.Setenv("DEVSLOT_GIT", filepath.Join(t.TempDir(), "git"))
+	_, err = CheckInstalled()
+	if err == nil {
+		t.Fatal("CheckInstalled() succeeded for a missing binary")
+	}
+	for _, want := range []string{"git not found", "Install git", "DEVSLOT_GIT"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	// Commands run the configured binary
+	if _, err := Version(); err == nil {
+		t.Error("Version() succeeded with a missing binary")
+	}
+}
+
+func TestInstallHint(t *testing.T) {
+	for goos, want := range map[string]string{
+		"darwin":  "brew install git",
+		"linux":   "apt install git",
+		"windows": "Git for Windows",
+		"plan9":   "git-scm.com",
+	} {
+		if got := installHint(goos); !strings.Contains(got, want) {
+			t.Errorf("installHint(%q) = %q, want it to mention %q", goos, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// VersionNumber is a git release such as 2.39.2
+type VersionNumber struct {
+	Major, Minor, Patch int
+}
+
+// DefaultMinVersion is the oldest git that doctor accepts without a warning when
+// devslot.yaml sets no min_git_version
+var DefaultMinVersion = VersionNumber{2, 30, 0}
+
+// Versions of git that introduced features devslot uses
+var (
+	// versionPartialClone is the first git whose clone accepts --filter
+	versionPartialClone = VersionNumber{2, 19, 0}
+	// versionWorktreeRepair is the first git with 'git worktree repair'
+	versionWorktreeRepair = VersionNumber{2, 30, 0}
+)
+
+// versionPattern matches the version number in 'git --version' output and in
+// versions written in devslot.yaml
+var versionPattern = regexp.MustCompile(`^(?:git version )?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion parses the output of 'git --version', such as "git version 2.39.2
+// (Apple Git-143)" or "git version 2.45.1.windows.1", or a bare version such as 2.30.
+// Anything after major.minor.patch is ignored.
+func ParseVersion(s string) (VersionNumber, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return VersionNumber{}, fmt.Errorf("unrecognized git version %q", strings.TrimSpace(s))
+	}
+	var v VersionNumber
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+	}
+	return v, nil
+}
+
+// String returns the version as major.minor.patch
+func (v VersionNumber) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 when v is older than, the same as or newer than other
+func (v VersionNumber) Compare(other VersionNumber) int {
+	return cmp.Or(cmp.Compare(v.Major, other.Major), cmp.Compare(v.Minor, other.Minor), cmp.Compare(v.Patch, other.Patch))
+}
+
+// installedVersions caches the version of every git program Version ran, so
+// 'git --version' runs once per process unless the binary changes
+var (
+	installedVersionsMu sync.Mutex
+	installedVersions   = map[string]*installedVersion{}
+)
+
+type installedVersion struct {
+	version VersionNumber
+	err     error
+}
+
+// Version returns the version of the git that devslot runs
+func Version() (VersionNumber, error) {
+	binary := Binary()
+	installedVersionsMu.Lock()
+	defer installedVersionsMu.Unlock()
+	if cached, ok := installedVersions[binary]; ok {
+		return cached.version, cached.err
+	}
+
+	cached := &installedVersion{}
+	if output, err := command("--version").Output(); err != nil {
+		cached.err = fmt.Errorf("failed to run git --version: %w", err)
+	} else {
+		cached.version, cached.err = ParseVersion(string(output))
+	}
+	installedVersions[binary] = cached
+	return cached.version, cached.err
+}
+
+// requireVersion fails with a message naming the required version when the installed
+// git is older than min. When the version cannot be determined, git is left to fail on its own.
+func requireVersion(feature string, min VersionNumber) error {
+	installed, err := Version()
+	if err != nil {
+		return nil
+	}
+	return checkVersion(installed, feature, min)
+}
+
+// checkVersion fails when installed is older than min
+func checkVersion(installed VersionNumber, feature string, min VersionNumber) error {
+	if installed.Compare(min) >= 0 {
+		return nil
+	}
+	return errors.GitTooOld(feature, min.String(), installed.String())
+}
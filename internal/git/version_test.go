@@ -0,0 +1,80 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    VersionNumber
+		wantErr bool
+	}{
+		{input: "git version 2.39.2\n", want: VersionNumber{2, 39, 2}},
+		{input: "git version 2.39.2 (Apple Git-143)", want: VersionNumber{2, 39, 2}},
+		{input: "git version 2.45.1.windows.1", want: VersionNumber{2, 45, 1}},
+		{input: "git version 2.47.0.rc1", want: VersionNumber{2, 47, 0}},
+		{input: "git version 1.8.3.1", want: VersionNumber{1, 8, 3}},
+		{input: "2.30", want: VersionNumber{2, 30, 0}},
+		{input: "2.30.1", want: VersionNumber{2, 30, 1}},
+		{input: "git version", wantErr: true},
+		{input: "2", wantErr: true},
+		{input: "version 2.30", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseVersion() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionNumber_Compare(t *testing.T) {
+	tests := []struct {
+		a, b VersionNumber
+		want int
+	}{
+		{VersionNumber{2, 30, 0}, VersionNumber{2, 30, 0}, 0},
+		{VersionNumber{2, 29, 9}, VersionNumber{2, 30, 0}, -1},
+		{VersionNumber{2, 30, 1}, VersionNumber{2, 30, 0}, 1},
+		{VersionNumber{3, 0, 0}, VersionNumber{2, 99, 0}, 1},
+		{VersionNumber{1, 99, 99}, VersionNumber{2, 0, 0}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	if err := checkVersion(VersionNumber{2, 39, 2}, "git worktree repair", versionWorktreeRepair); err != nil {
+		t.Errorf("checkVersion() error = %v for a new enough git", err)
+	}
+	err := checkVersion(VersionNumber{2, 17, 1}, "git worktree repair", versionWorktreeRepair)
+	if err == nil || !strings.Contains(err.Error(), "git worktree repair needs git 2.30.0 or later, but git 2.17.1 is installed") {
+		t.Errorf("checkVersion() error = %v", err)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	v, err := Version()
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if v.Major < 2 {
+		t.Errorf("Version() = %v, expected git 2 or later in the test environment", v)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package hook
+
+import (
+	"os"
+	"os/exec"
+)
+
+// hookFile returns the path of the hook file whose path without extension is base
+func hookFile(base string) string {
+	return base
+}
+
+// Executable reports whether a hook file may be run: it needs an executable bit
+func Executable(info os.FileInfo) bool {
+	return info.Mode().Perm()&0111 != 0
+}
+
+// fileCommand returns the command running a hook file
+func fileCommand(path string) *exec.Cmd {
+	return exec.Command(path)
+}
+
+// shellCommand returns the command running an inline hook
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
+
+// runsShebang reports whether hook files without extension are started through
+// their #! line
+func runsShebang() bool {
+	return true
+}
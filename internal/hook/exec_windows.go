@@ -0,0 +1,62 @@
+//go:build windows
+
+package hook
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// hookExtensions are the hook file variants looked for after the file without extension
+var hookExtensions = []string{".cmd", ".bat", ".ps1", ".exe"}
+
+// hookFile returns the path of the hook file whose path without extension is base:
+// base itself or its first variant with one of hookExtensions that exists
+func hookFile(base string) string {
+	for _, ext := range append([]string{""}, hookExtensions...) {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return base
+}
+
+// Executable reports whether a hook file may be run. NTFS has no executable bit,
+// so every hook file may.
+func Executable(info os.FileInfo) bool {
+	return true
+}
+
+// fileCommand returns the command running a hook file. Files without extension,
+// such as the scripts 'devslot boilerplate' generates, are run with sh, e.g. that
+// of Git for Windows.
+func fileCommand(path string) *exec.Cmd {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		return exec.Command("powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path)
+	case ".cmd", ".bat", ".exe":
+		return exec.Command(path)
+	}
+	return exec.Command("sh", path)
+}
+
+// shellCommand returns the command running an inline hook: with sh when it is
+// installed, so hooks work the same as elsewhere, otherwise with cmd
+func shellCommand(command string) *exec.Cmd {
+	if _, err := exec.LookPath("sh"); err == nil {
+		return exec.Command("sh", "-c", command)
+	}
+	// cmd does not parse its command line like other programs, so pass it as is
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: "cmd /C " + command}
+	return cmd
+}
+
+// runsShebang reports whether hook files without extension are started through
+// their #! line; they are run with sh instead
+func runsShebang() bool {
+	return false
+}
@@ -1,40 +1,167 @@
 package hook
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/logger"
+	"github.com/yammerjp/devslot/internal/version"
 )
 
 // Type represents the type of hook
 type Type string
 
 const (
-	PostCreate  Type = "post-create"
-	PreDestroy  Type = "pre-destroy"
-	PostDestroy Type = "post-destroy"
-	PostReload  Type = "post-reload"
-	PostInit    Type = "post-init"
+	PostCreate   Type = "post-create"
+	PreDestroy   Type = "pre-destroy"
+	PostDestroy  Type = "post-destroy"
+	PostReload   Type = "post-reload"
+	PostInit     Type = "post-init"
+	PostCheckout Type = "post-checkout"
 )
 
+// Types lists every hook type in lifecycle order
+var Types = []Type{PostInit, PostCreate, PostReload, PostCheckout, PreDestroy, PostDestroy}
+
+// Descriptions tells when each hook type runs. Doctor, 'devslot hooks list' and
+// the boilerplate hooks all describe hooks with it.
+var Descriptions = map[Type]string{
+	PostInit:     "after 'devslot init' clones or fetches the repositories",
+	PostCreate:   "after 'devslot create' creates a slot",
+	PostReload:   "after 'devslot reload' reloads a slot",
+	PostCheckout: "after 'devslot checkout' switches the branch of a slot",
+	PreDestroy:   "before 'devslot destroy' removes a slot",
+	PostDestroy:  "after 'devslot destroy' removes a slot",
+}
+
+// ParseType validates a hook type name
+func ParseType(name string) (Type, error) {
+	names := make([]string, len(Types))
+	for i, t := range Types {
+		if string(t) == name {
+			return t, nil
+		}
+		names[i] = string(t)
+	}
+	return "", errors.UnknownHookType(name, names)
+}
+
+// WorkDir selects the working directory a hook runs in
+type WorkDir int
+
+const (
+	// WorkDirProjectRoot runs the hook in the project root
+	WorkDirProjectRoot WorkDir = iota
+	// WorkDirSlot runs the hook in the slot directory
+	WorkDirSlot
+)
+
+// DefaultWorkDirs returns the working directory of each hook type.
+// Slot-scoped hooks run in the slot directory; hooks without an existing slot run in the project root.
+func DefaultWorkDirs() map[Type]WorkDir {
+	return map[Type]WorkDir{
+		PostCreate:   WorkDirSlot,
+		PreDestroy:   WorkDirSlot,
+		PostReload:   WorkDirSlot,
+		PostCheckout: WorkDirSlot,
+		PostDestroy:  WorkDirProjectRoot,
+		PostInit:     WorkDirProjectRoot,
+	}
+}
+
+// Repository describes a repository passed to hooks in DEVSLOT_REPOSITORIES_JSON
+type Repository struct {
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	WorktreePath string `json:"worktree_path"`
+	Branch       string `json:"branch"`
+}
+
+// RepositoriesEnv returns the hook environment variables describing repositories:
+// DEVSLOT_REPOSITORIES (space-separated names, kept for backward compatibility)
+// and DEVSLOT_REPOSITORIES_JSON (an array of Repository objects)
+func RepositoriesEnv(repos []Repository) map[string]string {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+	}
+
+	if repos == nil {
+		repos = []Repository{}
+	}
+	// Marshaling a slice of plain structs cannot fail
+	data, _ := json.Marshal(repos)
+
+	return map[string]string{
+		"DEVSLOT_REPOSITORIES":      strings.Join(names, " "),
+		"DEVSLOT_REPOSITORIES_JSON": string(data),
+	}
+}
+
+var (
+	// command is the devslot command that runs the hooks of this process
+	commandMu      sync.RWMutex
+	currentCommand string
+)
+
+// SetCommand sets the devslot command, such as "create" or "hooks run", that
+// hooks get as DEVSLOT_COMMAND. It is empty when devslot is used as a library.
+func SetCommand(name string) {
+	commandMu.Lock()
+	defer commandMu.Unlock()
+	currentCommand = name
+}
+
+// invocationCommand returns the command set with SetCommand
+func invocationCommand() string {
+	commandMu.RLock()
+	defer commandMu.RUnlock()
+	return currentCommand
+}
+
 // Runner executes hooks
 type Runner struct {
 	projectRoot string
+	// ReposDir and SlotsDir are the project's repos/ and slots/ directories
+	ReposDir string
+	SlotsDir string
+	// WorkDirs decides the working directory per hook type; unlisted types run in the project root
+	WorkDirs map[Type]WorkDir
+	// Stdout and Stderr receive the hook's output
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // NewRunner creates a new hook runner
 func NewRunner(projectRoot string) *Runner {
 	return &Runner{
 		projectRoot: projectRoot,
+		ReposDir:    filepath.Join(projectRoot, "repos"),
+		SlotsDir:    filepath.Join(projectRoot, "slots"),
+		WorkDirs:    DefaultWorkDirs(),
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
 	}
 }
 
+// Path returns the path of the hook file for a hook type. On Windows, it is the
+// first of the file without extension and its .cmd, .bat, .ps1 and .exe variants
+// that exists.
+func (r *Runner) Path(hookType Type) string {
+	return hookFile(filepath.Join(r.projectRoot, "hooks", string(hookType)))
+}
+
 // Run executes a hook if it exists
 func (r *Runner) Run(hookType Type, slotName string, env map[string]string) error {
-	hookPath := filepath.Join(r.projectRoot, "hooks", string(hookType))
+	hookPath := r.Path(hookType)
 
 	// Check if hook exists and is executable
 	info, err := os.Stat(hookPath)
@@ -47,43 +174,109 @@ func (r *Runner) Run(hookType Type, slotName string, env map[string]string) erro
 	}
 
 	// Check if file is executable
-	if info.Mode().Perm()&0111 == 0 {
+	if !Executable(info) {
 		return errors.HookNotExecutable(string(hookType))
 	}
 
 	// Prepare command
-	cmd := exec.Command(hookPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := fileCommand(hookPath)
+	cmd.Dir = r.Dir(hookType, slotName)
+	cmd.Stdout = r.Stdout
+	cmd.Stderr = r.Stderr
+	cmd.Env = r.environ(hookType, slotName, env)
+	slog.Debug("running hook", "hook", hookType, "dir", cmd.Dir)
 
-	// Set environment variables
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, fmt.Sprintf("DEVSLOT_ROOT=%s", r.projectRoot))
-	cmd.Env = append(cmd.Env, fmt.Sprintf("DEVSLOT_SLOT_NAME=%s", slotName))
-	cmd.Env = append(cmd.Env, fmt.Sprintf("DEVSLOT_SLOT_DIR=%s", filepath.Join(r.projectRoot, "slots", slotName)))
-	cmd.Env = append(cmd.Env, fmt.Sprintf("DEVSLOT_REPOS_DIR=%s", filepath.Join(r.projectRoot, "repos")))
+	// Execute hook
+	if err := cmd.Run(); err != nil {
+		return errors.HookFailed(string(hookType), err)
+	}
+
+	return nil
+}
+
+// RunInline executes inline hook commands defined in devslot.yaml.
+// Each command is run via 'sh -c' with the same environment as file hooks.
+func (r *Runner) RunInline(hookType Type, slotName string, commands []string, env map[string]string) error {
+	for _, command := range commands {
+		cmd := shellCommand(command)
+		cmd.Dir = r.Dir(hookType, slotName)
+		cmd.Stdout = r.Stdout
+		cmd.Stderr = r.Stderr
+		cmd.Env = r.environ(hookType, slotName, env)
+		slog.Debug("running inline hook", "hook", hookType, "command", command, "dir", cmd.Dir)
+
+		if err := cmd.Run(); err != nil {
+			return errors.InlineHookFailed(string(hookType), command, err)
+		}
+	}
+
+	return nil
+}
+
+// RunAll executes the hook file (if it exists) followed by the inline hook commands
+func (r *Runner) RunAll(hookType Type, slotName string, commands []string, env map[string]string) error {
+	if err := r.Run(hookType, slotName, env); err != nil {
+		return err
+	}
+	return r.RunInline(hookType, slotName, commands, env)
+}
+
+// Dir returns the working directory for a hook.
+// Slot-scoped hooks fall back to the project root when the slot directory does not exist.
+func (r *Runner) Dir(hookType Type, slotName string) string {
+	if r.WorkDirs[hookType] == WorkDirSlot && slotName != "" {
+		slotDir := filepath.Join(r.SlotsDir, slotName)
+		if info, err := os.Stat(slotDir); err == nil && info.IsDir() {
+			return slotDir
+		}
+	}
+	return r.projectRoot
+}
+
+// Env returns the devslot-specific variables passed to a hook, including the custom environment
+func (r *Runner) Env(hookType Type, slotName string, env map[string]string) map[string]string {
+	vars := map[string]string{
+		"DEVSLOT_HOOK_TYPE":     string(hookType),
+		"DEVSLOT_ROOT":          r.projectRoot,
+		"DEVSLOT_SLOT_NAME":     slotName,
+		"DEVSLOT_SLOT_DIR":      filepath.Join(r.SlotsDir, slotName),
+		"DEVSLOT_REPOS_DIR":     r.ReposDir,
+		"DEVSLOT_COMMAND":       invocationCommand(),
+		"DEVSLOT_VERSION":       version.Version,
+		"DEVSLOT_INVOCATION_ID": logger.InvocationID(),
+	}
 
 	// Add custom environment variables
 	for k, v := range env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+		vars[k] = v
 	}
 
-	// Execute hook
-	if err := cmd.Run(); err != nil {
-		return errors.HookFailed(string(hookType), err)
+	return vars
+}
+
+// environ builds the environment passed to hooks
+func (r *Runner) environ(hookType Type, slotName string, env map[string]string) []string {
+	vars := r.Env(hookType, slotName, env)
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
 	}
+	slices.Sort(keys)
 
-	return nil
+	environ := os.Environ()
+	for _, k := range keys {
+		environ = append(environ, fmt.Sprintf("%s=%s", k, vars[k]))
+	}
+	return environ
 }
 
 // Exists checks if a hook exists
 func (r *Runner) Exists(hookType Type) bool {
-	hookPath := filepath.Join(r.projectRoot, "hooks", string(hookType))
-	info, err := os.Stat(hookPath)
+	info, err := os.Stat(r.Path(hookType))
 	if err != nil {
 		return false
 	}
 
 	// Check if it's a regular file and executable
-	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+	return info.Mode().IsRegular() && Executable(info)
 }
@@ -0,0 +1,114 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/logger"
+	"github.com/yammerjp/devslot/internal/testutil"
+	"github.com/yammerjp/devslot/internal/version"
+)
+
+func TestRunner_WorkingDirectory(t *testing.T) {
+	tests := []struct {
+		name     string
+		hookType Type
+		slotName string
+		wantSlot bool
+	}{
+		{name: "post-create runs in slot", hookType: PostCreate, slotName: "slot1", wantSlot: true},
+		{name: "pre-destroy runs in slot", hookType: PreDestroy, slotName: "slot1", wantSlot: true},
+		{name: "post-reload runs in slot", hookType: PostReload, slotName: "slot1", wantSlot: true},
+		{name: "post-init runs in root", hookType: PostInit, slotName: "", wantSlot: false},
+		{name: "post-destroy runs in root", hookType: PostDestroy, slotName: "slot1", wantSlot: false},
+		{name: "missing slot falls back to root", hookType: PostCreate, slotName: "gone", wantSlot: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.TempDir(t)
+			slotDir := filepath.Join(root, "slots", "slot1")
+			if err := os.MkdirAll(slotDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			outFile := filepath.Join(root, "pwd.txt")
+			testutil.CreateExecutable(t, filepath.Join(root, "hooks", string(tt.hookType)),
+				"#!/bin/sh\npwd -P > \""+outFile+"\"\n")
+
+			runner := NewRunner(root)
+			if err := runner.Run(tt.hookType, tt.slotName, nil); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			want := root
+			if tt.wantSlot {
+				want = slotDir
+			}
+			want, err := filepath.EvalSymlinks(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := strings.TrimSpace(testutil.ReadFile(t, outFile))
+			if got != want {
+				t.Errorf("hook working directory = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRunner_InlineWorkingDirectory(t *testing.T) {
+	root := testutil.TempDir(t)
+	slotDir := filepath.Join(root, "slots", "slot1")
+	if err := os.MkdirAll(slotDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(root)
+	if err := runner.RunInline(PostCreate, "slot1", []string{"pwd -P > marker.txt"}, nil); err != nil {
+		t.Fatalf("RunInline() error = %v", err)
+	}
+
+	if !testutil.FileExists(t, filepath.Join(slotDir, "marker.txt")) {
+		t.Error("inline hook did not run in the slot directory")
+	}
+}
+
+func TestRunner_CustomWorkDir(t *testing.T) {
+	root := testutil.TempDir(t)
+	slotDir := filepath.Join(root, "slots", "slot1")
+	if err := os.MkdirAll(slotDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(root)
+	runner.WorkDirs[PostCreate] = WorkDirProjectRoot
+	if err := runner.RunInline(PostCreate, "slot1", []string{"touch marker.txt"}, nil); err != nil {
+		t.Fatalf("RunInline() error = %v", err)
+	}
+
+	if !testutil.FileExists(t, filepath.Join(root, "marker.txt")) {
+		t.Error("inline hook did not run in the project root")
+	}
+}
+
+func TestRunner_InvocationEnv(t *testing.T) {
+	root := testutil.TempDir(t)
+	outFile := filepath.Join(root, "env.txt")
+	testutil.CreateExecutable(t, filepath.Join(root, "hooks", string(PostInit)),
+		"#!/bin/sh\necho \"$DEVSLOT_COMMAND|$DEVSLOT_VERSION|$DEVSLOT_INVOCATION_ID\" > \""+outFile+"\"\n")
+
+	SetCommand("init")
+	defer SetCommand("")
+	if err := NewRunner(root).Run(PostInit, "", nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "init|" + version.Version + "|" + logger.InvocationID()
+	if got := strings.TrimSpace(testutil.ReadFile(t, outFile)); got != want {
+		t.Errorf("hook environment = %q, want %q", got, want)
+	}
+}
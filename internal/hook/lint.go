@@ -0,0 +1,151 @@
+package hook
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// EnvVars lists the DEVSLOT_ variables devslot sets for hooks or reads from the environment
+var EnvVars = []string{
+	"DEVSLOT_HOOK_TYPE",
+	"DEVSLOT_ROOT",
+	"DEVSLOT_SLOT_NAME",
+	"DEVSLOT_SLOT_DIR",
+	"DEVSLOT_REPOS_DIR",
+	"DEVSLOT_COMMAND",
+	"DEVSLOT_VERSION",
+	"DEVSLOT_INVOCATION_ID",
+	"DEVSLOT_REPOSITORIES",
+	"DEVSLOT_REPOSITORIES_JSON",
+	"DEVSLOT_BRANCH_NAME",
+	"DEVSLOT_CLONED_REPOSITORIES",
+	"DEVSLOT_SKIPPED_REPOSITORIES",
+	"DEVSLOT_PROJECT_ROOT",
+	"DEVSLOT_CONFIG",
+	"DEVSLOT_BRANCH_PREFIX",
+	"DEVSLOT_EDITOR",
+	"DEVSLOT_MAX_SEARCH_DEPTH",
+	"DEVSLOT_GIT",
+}
+
+// ProblemKind classifies a problem found by Lint
+type ProblemKind int
+
+const (
+	// ProblemNotExecutable is a hook file without an executable bit
+	ProblemNotExecutable ProblemKind = iota
+	// ProblemNoShebang is a script without a #! line, which cannot be executed
+	ProblemNoShebang
+	// ProblemInterpreterNotFound is a shebang naming a program that is not installed
+	ProblemInterpreterNotFound
+	// ProblemCRLF is a script with Windows line endings, which break the shebang and the shell
+	ProblemCRLF
+	// ProblemUnknownVariable is a reference to a DEVSLOT_ variable devslot does not set
+	ProblemUnknownVariable
+)
+
+// Problem is something wrong with a hook file
+type Problem struct {
+	Kind    ProblemKind
+	Message string
+}
+
+// variablePattern matches references to devslot variables in a script
+var variablePattern = regexp.MustCompile(`\bDEVSLOT_[A-Z0-9_]*[A-Z0-9]`)
+
+// Lint inspects a hook file for the mistakes that make hooks fail: a missing
+// executable bit, a missing shebang or interpreter, CRLF line endings and
+// DEVSLOT_ variables that are never set. Binary files are only checked for
+// the executable bit.
+func Lint(path string) ([]Problem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	if !Executable(info) {
+		problems = append(problems, Problem{Kind: ProblemNotExecutable, Message: "is not executable"})
+	}
+	if bytes.IndexByte(data[:min(len(data), 512)], 0) >= 0 {
+		return problems, nil
+	}
+	script := filepath.Ext(path) == ""
+
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	if script && runsShebang() {
+		if interpreter, ok := strings.CutPrefix(firstLine, "#!"); !ok {
+			problems = append(problems, Problem{Kind: ProblemNoShebang, Message: "has no #! line naming its interpreter"})
+		} else if program := shebangProgram(interpreter); program == "" {
+			problems = append(problems, Problem{Kind: ProblemNoShebang, Message: "has an empty #! line"})
+		} else if !interpreterExists(program) {
+			problems = append(problems, Problem{Kind: ProblemInterpreterNotFound, Message: fmt.Sprintf("needs %s, which was not found", program)})
+		}
+	}
+	if script && bytes.Contains(data, []byte("\r\n")) {
+		problems = append(problems, Problem{Kind: ProblemCRLF, Message: "has Windows (CRLF) line endings"})
+	}
+
+	var unknown []string
+	for _, name := range variablePattern.FindAllString(string(data), -1) {
+		if !slices.Contains(EnvVars, name) && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		problems = append(problems, Problem{Kind: ProblemUnknownVariable,
+			Message: fmt.Sprintf("uses %s, which devslot does not set", strings.Join(unknown, ", "))})
+	}
+	return problems, nil
+}
+
+// shebangProgram returns the program a shebang line runs: the interpreter, or
+// the program run by /usr/bin/env. It is empty when the line names nothing.
+func shebangProgram(line string) string {
+	fields := strings.Fields(strings.TrimSuffix(line, "\r"))
+	if len(fields) == 0 {
+		return ""
+	}
+	if filepath.Base(fields[0]) != "env" {
+		return fields[0]
+	}
+	// Skip the options of env, such as -S, and variable assignments
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+			return field
+		}
+	}
+	return fields[0]
+}
+
+// interpreterExists reports whether the program of a shebang is installed:
+// absolute paths must exist, other names are looked up on PATH
+func interpreterExists(program string) bool {
+	if filepath.IsAbs(program) {
+		_, err := os.Stat(program)
+		return err == nil
+	}
+	_, err := exec.LookPath(program)
+	return err == nil
+}
+
+// MakeExecutable adds the executable bits to a hook file wherever it is readable
+func MakeExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	perm := info.Mode().Perm()
+	return os.Chmod(path, perm|(perm&0444)>>2)
+}
@@ -0,0 +1,116 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		executable bool
+		want       map[ProblemKind]string
+	}{
+		{
+			name:       "valid script",
+			content:    "#!/bin/sh\necho \"$DEVSLOT_SLOT_NAME $DEVSLOT_REPOSITORIES_JSON\"\n",
+			executable: true,
+		},
+		{
+			name:       "env shebang",
+			content:    "#!/usr/bin/env -S sh -e\necho \"$DEVSLOT_BRANCH_NAME\"\n",
+			executable: true,
+		},
+		{
+			name:    "not executable",
+			content: "#!/bin/sh\ntrue\n",
+			want:    map[ProblemKind]string{ProblemNotExecutable: "is not executable"},
+		},
+		{
+			name:       "missing shebang",
+			content:    "echo hello\n",
+			executable: true,
+			want:       map[ProblemKind]string{ProblemNoShebang: "has no #! line"},
+		},
+		{
+			name:       "missing interpreter",
+			content:    "#!/usr/bin/env devslot-no-such-shell\ntrue\n",
+			executable: true,
+			want:       map[ProblemKind]string{ProblemInterpreterNotFound: "needs devslot-no-such-shell, which was not found"},
+		},
+		{
+			name:       "missing absolute interpreter",
+			content:    "#!/no/such/bash\ntrue\n",
+			executable: true,
+			want:       map[ProblemKind]string{ProblemInterpreterNotFound: "needs /no/such/bash"},
+		},
+		{
+			name:       "CRLF line endings",
+			content:    "#!/bin/sh\r\necho hello\r\n",
+			executable: true,
+			want:       map[ProblemKind]string{ProblemCRLF: "CRLF"},
+		},
+		{
+			name:       "unknown variables",
+			content:    "#!/bin/sh\necho \"$DEVSLOT_SLOT $DEVSLOT_SLOT_NAME ${DEVSLOT_WORKTREE_DIR} $DEVSLOT_SLOT\"\n",
+			executable: true,
+			want:       map[ProblemKind]string{ProblemUnknownVariable: "uses DEVSLOT_SLOT, DEVSLOT_WORKTREE_DIR, which devslot does not set"},
+		},
+		{
+			name:       "binary",
+			content:    "\x7fELF\x02\x01\x01\x00\x00\x00DEVSLOT_UNKNOWN\r\n",
+			executable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(testutil.TempDir(t), "post-create")
+			if tt.executable {
+				testutil.CreateExecutable(t, path, tt.content)
+			} else {
+				testutil.CreateFile(t, path, tt.content)
+			}
+
+			problems, err := Lint(path)
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Lint() = %+v, want %d problems", problems, len(tt.want))
+			}
+			for _, problem := range problems {
+				want, ok := tt.want[problem.Kind]
+				if !ok {
+					t.Errorf("unexpected problem %+v", problem)
+				} else if !strings.Contains(problem.Message, want) {
+					t.Errorf("message = %q, want it to contain %q", problem.Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMakeExecutable(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t), "post-create")
+	testutil.CreateFile(t, path, "#!/bin/sh\n")
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MakeExecutable(path); err != nil {
+		t.Fatalf("MakeExecutable() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0750 {
+		t.Errorf("mode = %o, want 750", perm)
+	}
+}
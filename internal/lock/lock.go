@@ -3,40 +3,47 @@ package lock
 import (
 	"fmt"
 	"os"
-	"syscall"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type FileLock struct {
 	path string
 	file *os.File
+	// exclusive takes the lock by creating the lock file instead of with flock,
+	// which is not available on every platform
+	exclusive bool
 }
 
 func New(lockPath string) *FileLock {
 	return &FileLock{
-		path: lockPath,
+		path:      lockPath,
+		exclusive: !flockSupported,
 	}
 }
 
+// emptyLockGrace is how long a lock file that names no process yet is taken to
+// belong to a process that just created it
+const emptyLockGrace = 10 * time.Second
+
 func (l *FileLock) Acquire() error {
-	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open lock file: %w", err)
+	var file *os.File
+	var err error
+	if l.exclusive {
+		file, err = l.create()
+	} else {
+		file, err = l.flock()
 	}
-
-	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
 	if err != nil {
-		file.Close()
-		if err == syscall.EWOULDBLOCK {
-			return fmt.Errorf("another devslot process is already running")
-		}
-		return fmt.Errorf("failed to acquire lock: %w", err)
+		return err
 	}
 
 	l.file = file
 
-	// Write PID and timestamp to lock file
-	content := fmt.Sprintf("PID: %d\nTime: %s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	// Record who holds the lock for 'devslot doctor' and error messages
+	content := fmt.Sprintf("PID: %d\nTime: %s\nCommand: %s\n", os.Getpid(), time.Now().Format(time.RFC3339), command())
 	if err := file.Truncate(0); err != nil {
 		_ = l.Release()
 		return fmt.Errorf("failed to truncate lock file: %w", err)
@@ -54,8 +61,24 @@ func (l *FileLock) Release() error {
 		return nil
 	}
 
-	err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
-	if err != nil {
+	if l.exclusive {
+		// The lock is the file itself
+		if err := l.file.Close(); err != nil {
+			return fmt.Errorf("failed to close lock file: %w", err)
+		}
+		l.file = nil
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove lock file: %w", err)
+		}
+		return nil
+	}
+
+	// Clear the holder so that only lock files left by crashed processes name one
+	if err := l.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to clear lock file: %w", err)
+	}
+
+	if err := unlock(l.file); err != nil {
 		return fmt.Errorf("failed to unlock: %w", err)
 	}
 
@@ -66,3 +89,144 @@ func (l *FileLock) Release() error {
 	l.file = nil
 	return nil
 }
+
+// create takes the lock by creating the lock file, which only one process can do.
+// A lock file that names a process that no longer runs, or that has named none for
+// longer than emptyLockGrace, is left over and replaced.
+func (l *FileLock) create() (*os.File, error) {
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			return file, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if holder, ok := leftover(l.path); !ok || attempt > 0 {
+			return nil, busy(holder)
+		}
+		if err := l.removeLeftover(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// removeLeftover removes the lock file if it is still left over. Removing is guarded by
+// a second file created the same way as the lock, so that processes that found the same
+// left over lock file cannot remove the lock one of them has taken in the meantime.
+func (l *FileLock) removeLeftover() error {
+	guardPath := l.path + ".replace"
+	guard, err := os.OpenFile(guardPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		// Another process is replacing the lock file, unless it crashed while doing so
+		if info, statErr := os.Stat(guardPath); statErr != nil || time.Since(info.ModTime()) < emptyLockGrace {
+			return busy(nil)
+		}
+		if err := os.Remove(guardPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale lock file: %w", err)
+		}
+		guard, err = os.OpenFile(guardPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if os.IsExist(err) {
+			return busy(nil)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer func() {
+		_ = guard.Close()
+		_ = os.Remove(guardPath)
+	}()
+
+	// Only the guard holder removes lock files, but one may have been taken since it was read
+	if holder, ok := leftover(l.path); !ok {
+		return busy(holder)
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale lock file: %w", err)
+	}
+	return nil
+}
+
+// leftover reads the holder of the lock file at lockPath and reports whether nothing
+// holds the lock: the file is gone, names a process that no longer runs, or has named
+// none for longer than emptyLockGrace
+func leftover(lockPath string) (*Holder, bool) {
+	holder := ReadHolder(lockPath)
+	if holder != nil {
+		return holder, !holder.Alive()
+	}
+	info, err := os.Stat(lockPath)
+	return nil, err != nil || time.Since(info.ModTime()) >= emptyLockGrace
+}
+
+// busy returns the error for a lock held by another process
+func busy(holder *Holder) error {
+	if holder != nil && holder.Command != "" {
+		return fmt.Errorf("another devslot process is already running (PID %d: %s)", holder.PID, holder.Command)
+	}
+	return fmt.Errorf("another devslot process is already running")
+}
+
+// Holder describes the process recorded in a lock file
+type Holder struct {
+	PID int
+	// Time is when the lock was taken; zero if not recorded
+	Time time.Time
+	// Command is the devslot command line; empty in lock files written by older versions
+	Command string
+}
+
+// Alive reports whether the holder's process still exists
+func (h *Holder) Alive() bool {
+	return h.PID == os.Getpid() || processAlive(h.PID)
+}
+
+// ReadHolder reads the holder recorded in the lock file at lockPath.
+// It returns nil when the file is missing, empty or does not name a process.
+func ReadHolder(lockPath string) *Holder {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil
+	}
+
+	var holder Holder
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "PID":
+			holder.PID, _ = strconv.Atoi(value)
+		case "Time":
+			holder.Time, _ = time.Parse(time.RFC3339, value)
+		case "Command":
+			holder.Command = value
+		}
+	}
+	if holder.PID <= 0 {
+		return nil
+	}
+	return &holder
+}
+
+// Stale reports the PID recorded in the lock file at lockPath and whether that
+// process is no longer running. A missing or unreadable file is not stale.
+func Stale(lockPath string) (int, bool) {
+	holder := ReadHolder(lockPath)
+	if holder == nil {
+		return 0, false
+	}
+	return holder.PID, !holder.Alive()
+}
+
+// command returns the command line of this process, e.g. "devslot create dev"
+func command() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+}
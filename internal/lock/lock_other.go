@@ -0,0 +1,31 @@
+//go:build !unix
+
+package lock
+
+import (
+	stderrors "errors"
+	"os"
+)
+
+// flockSupported is false where there is no flock; New creates the lock file
+// exclusively instead
+const flockSupported = false
+
+func (l *FileLock) flock() (*os.File, error) {
+	return nil, stderrors.ErrUnsupported
+}
+
+func unlock(file *os.File) error {
+	return stderrors.ErrUnsupported
+}
+
+// processAlive reports whether a process with the given PID exists. On Windows,
+// finding a process opens it, which fails once it has exited.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
@@ -1,10 +1,13 @@
 package lock
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFileLock(t *testing.T) {
@@ -96,3 +99,207 @@ func TestFileLock(t *testing.T) {
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && strings.Contains(s, substr)
 }
+
+func TestStale(t *testing.T) {
+	// The test binary runs no test and exits
+	proc := exec.Command(os.Args[0], "-test.run=^$")
+	if err := proc.Run(); err != nil {
+		t.Fatal(err)
+	}
+	deadPID := proc.Process.Pid
+
+	tests := []struct {
+		name      string
+		content   string
+		wantPID   int
+		wantStale bool
+	}{
+		{name: "missing file", wantPID: 0},
+		{name: "dead process", content: fmt.Sprintf("PID: %d\nTime: 2024-01-01T00:00:00Z\n", deadPID), wantPID: deadPID, wantStale: true},
+		{name: "current process", content: fmt.Sprintf("PID: %d\n", os.Getpid()), wantPID: os.Getpid()},
+		{name: "running process", content: fmt.Sprintf("PID: %d\n", os.Getppid()), wantPID: os.Getppid()},
+		{name: "garbage", content: "not a lock file\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+			if tt.content != "" {
+				if err := os.WriteFile(lockPath, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pid, stale := Stale(lockPath)
+			if pid != tt.wantPID || stale != tt.wantStale {
+				t.Errorf("Stale() = (%d, %v), want (%d, %v)", pid, stale, tt.wantPID, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestReadHolder(t *testing.T) {
+	t.Run("records the running command", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		l := New(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = l.Release() }()
+
+		holder := ReadHolder(lockPath)
+		if holder == nil {
+			t.Fatal("ReadHolder() = nil, want holder")
+		}
+		if holder.PID != os.Getpid() || !holder.Alive() {
+			t.Errorf("ReadHolder() PID = %d, want %d", holder.PID, os.Getpid())
+		}
+		if holder.Command == "" || holder.Time.IsZero() {
+			t.Errorf("ReadHolder() = %+v, want command and time", holder)
+		}
+	})
+
+	t.Run("release clears the holder", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		l := New(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Release(); err != nil {
+			t.Fatal(err)
+		}
+
+		if holder := ReadHolder(lockPath); holder != nil {
+			t.Errorf("ReadHolder() = %+v after release, want nil", holder)
+		}
+	})
+
+	t.Run("older lock file without command", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		if err := os.WriteFile(lockPath, []byte("PID: 42\nTime: 2024-01-01T00:00:00Z\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		holder := ReadHolder(lockPath)
+		if holder == nil {
+			t.Fatal("ReadHolder() = nil, want holder")
+		}
+		if holder.PID != 42 || holder.Command != "" || holder.Time.Year() != 2024 {
+			t.Errorf("ReadHolder() = %+v, want PID 42 from 2024 without command", holder)
+		}
+	})
+}
+
+func TestFileLock_Exclusive(t *testing.T) {
+	// The lock used where flock is not available, tested on every platform
+	newExclusive := func(lockPath string) *FileLock {
+		return &FileLock{path: lockPath, exclusive: true}
+	}
+
+	t.Run("release removes the lock file", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		l := newExclusive(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		if holder := ReadHolder(lockPath); holder == nil || holder.PID != os.Getpid() {
+			t.Errorf("ReadHolder() = %+v, want this process", holder)
+		}
+		if err := l.Release(); err != nil {
+			t.Fatalf("Release() error = %v", err)
+		}
+		if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+			t.Errorf("lock file still exists after release: %v", err)
+		}
+		if err := l.Release(); err != nil {
+			t.Errorf("second Release() error = %v", err)
+		}
+	})
+
+	t.Run("prevents concurrent locks", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		l := newExclusive(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		defer func() { _ = l.Release() }()
+
+		err := newExclusive(lockPath).Acquire()
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("another devslot process is already running (PID %d:", os.Getpid())) {
+			t.Errorf("second Acquire() error = %v, want the holder", err)
+		}
+	})
+
+	t.Run("replaces the lock of a process that exited", func(t *testing.T) {
+		// The test binary runs no test and exits
+		proc := exec.Command(os.Args[0], "-test.run=^$")
+		if err := proc.Run(); err != nil {
+			t.Fatal(err)
+		}
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("PID: %d\n", proc.Process.Pid)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		l := newExclusive(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v, want the stale lock to be replaced", err)
+		}
+		_ = l.Release()
+	})
+
+	t.Run("an empty lock file is only left over once it is old", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := newExclusive(lockPath).Acquire(); err == nil {
+			t.Fatal("Acquire() succeeded, want a just created lock file to count as held")
+		}
+
+		old := time.Now().Add(-time.Minute)
+		if err := os.Chtimes(lockPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		l := newExclusive(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v, want the old empty lock file to be replaced", err)
+		}
+		_ = l.Release()
+	})
+
+	t.Run("a left over lock is not replaced while another process replaces it", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".devslot.lock")
+		if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Minute)
+		if err := os.Chtimes(lockPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		guardPath := lockPath + ".replace"
+		if err := os.WriteFile(guardPath, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := newExclusive(lockPath).Acquire(); err == nil {
+			t.Fatal("Acquire() succeeded, want the lock to count as held while it is being replaced")
+		}
+		if _, err := os.Stat(lockPath); err != nil {
+			t.Errorf("lock file was removed while being replaced by another process: %v", err)
+		}
+
+		// A guard that is old was left by a process that crashed while replacing the lock
+		if err := os.Chtimes(guardPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		l := newExclusive(lockPath)
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v, want the old guard and lock file to be replaced", err)
+		}
+		_ = l.Release()
+		if _, err := os.Stat(guardPath); !os.IsNotExist(err) {
+			t.Errorf("guard file still exists after replacing the lock: %v", err)
+		}
+	})
+}
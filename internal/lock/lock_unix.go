@@ -0,0 +1,43 @@
+//go:build unix
+
+package lock
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// flockSupported tells New to take the lock with flock, which the kernel releases
+// when the process exits
+const flockSupported = true
+
+// flock opens the lock file and takes an exclusive flock on it
+func (l *FileLock) flock() (*os.File, error) {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		file.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, busy(ReadHolder(l.path))
+		}
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	return file, nil
+}
+
+// unlock releases the flock taken by flock
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || stderrors.Is(err, syscall.EPERM)
+}
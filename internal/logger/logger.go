@@ -2,9 +2,13 @@ package logger
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"sync"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -25,6 +29,8 @@ type Options struct {
 	AddSource bool
 	// Format specifies the output format ("text" or "json")
 	Format string
+	// KeepTime keeps timestamps, which are otherwise removed for cleaner CLI output
+	KeepTime bool
 }
 
 // DefaultOptions returns default logger options
@@ -37,6 +43,15 @@ func DefaultOptions() Options {
 	}
 }
 
+// ParseLevel parses a level name (debug, info, warn or error)
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	return level, nil
+}
+
 // New creates a new slog.Logger with the given options
 func New(opts Options) *slog.Logger {
 	var handler slog.Handler
@@ -46,7 +61,7 @@ func New(opts Options) *slog.Logger {
 		AddSource: opts.AddSource,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			// Remove time from logs for cleaner CLI output
-			if a.Key == slog.TimeKey && len(groups) == 0 {
+			if a.Key == slog.TimeKey && len(groups) == 0 && !opts.KeepTime {
 				return slog.Attr{}
 			}
 			// Simplify level output
@@ -74,9 +89,20 @@ func New(opts Options) *slog.Logger {
 		handler = slog.NewTextHandler(opts.Writer, handlerOpts)
 	}
 
-	return slog.New(handler)
+	return slog.New(handler).With("invocation", InvocationID())
 }
 
+// InvocationID returns a random ID generated once per process. Every log line
+// carries it, and hooks get it as DEVSLOT_INVOCATION_ID, so the logs of one
+// devslot run and of the hooks it ran can be joined.
+var InvocationID = sync.OnceValue(func() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%d", os.Getpid())
+	}
+	return hex.EncodeToString(id)
+})
+
 // WithContext returns a new context with the logger attached
 func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SlowThreshold is how long an operation may take before its timings are shown without --timings
+const SlowThreshold = 30 * time.Second
+
+// Step is a timed, labeled part of an operation, such as cloning one repository
+type Step struct {
+	Label    string
+	Duration time.Duration
+}
+
+// Recorder collects the durations of steps. A nil Recorder records nothing, so code
+// can time its steps whether or not anyone asked for the timings.
+type Recorder struct {
+	// OnStop is called with every step that finished, e.g. to log it
+	OnStop func(Step)
+
+	mu    sync.Mutex
+	now   func() time.Time
+	start time.Time
+	steps []Step
+}
+
+// New returns a Recorder whose operation starts now
+func New() *Recorder {
+	return NewWithClock(time.Now)
+}
+
+// NewWithClock returns a Recorder reading the time from now; tests pass a fake clock
+func NewWithClock(now func() time.Time) *Recorder {
+	return &Recorder{now: now, start: now()}
+}
+
+// Start starts timing a step and returns the function that stops it. Steps may run
+// concurrently.
+func (r *Recorder) Start(label string) (stop func()) {
+	if r == nil {
+		return func() {}
+	}
+	started := r.now()
+	return func() {
+		step := Step{Label: label, Duration: r.now().Sub(started)}
+		r.mu.Lock()
+		r.steps = append(r.steps, step)
+		r.mu.Unlock()
+		if r.OnStop != nil {
+			r.OnStop(step)
+		}
+	}
+}
+
+// Steps returns the finished steps, slowest first
+func (r *Recorder) Steps() []Step {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	steps := slices.Clone(r.steps)
+	r.mu.Unlock()
+	slices.SortStableFunc(steps, func(a, b Step) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+	return steps
+}
+
+// Elapsed returns the time since the Recorder was created
+func (r *Recorder) Elapsed() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.now().Sub(r.start)
+}
+
+// Slow reports whether the operation took longer than SlowThreshold
+func (r *Recorder) Slow() bool {
+	return r.Elapsed() > SlowThreshold
+}
+
+// Render writes the steps as a table in the given order, followed by the total time
+func Render(w io.Writer, steps []Step, total time.Duration) {
+	width := len("Total")
+	for _, step := range steps {
+		width = max(width, len(step.Label))
+	}
+	fmt.Fprintln(w, "Timings:")
+	for _, step := range steps {
+		fmt.Fprintf(w, "  %-*s  %8s\n", width, step.Label, formatDuration(step.Duration))
+	}
+	fmt.Fprintf(w, "  %-*s  %8s\n", width, "Total", formatDuration(total))
+}
+
+// formatDuration rounds a duration for display: milliseconds below a second,
+// tenths of a second above
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
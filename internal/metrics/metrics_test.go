@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestRecorder_StepsSlowestFirst(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewWithClock(clock.Now)
+
+	var stopped []string
+	r.OnStop = func(step Step) { stopped = append(stopped, step.Label) }
+
+	stopApp := r.Start("clone app")
+	clock.Advance(2 * time.Second)
+	stopLib := r.Start("clone lib")
+	clock.Advance(5 * time.Second)
+	stopLib()
+	stopApp()
+	stopWeb := r.Start("clone web")
+	clock.Advance(500 * time.Millisecond)
+	stopWeb()
+
+	want := []Step{
+		{Label: "clone app", Duration: 7 * time.Second},
+		{Label: "clone lib", Duration: 5 * time.Second},
+		{Label: "clone web", Duration: 500 * time.Millisecond},
+	}
+	steps := r.Steps()
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d: %v", len(steps), len(want), steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d = %v, want %v", i, steps[i], want[i])
+		}
+	}
+	if len(stopped) != 3 || stopped[0] != "clone lib" {
+		t.Errorf("OnStop saw %v, want the steps in the order they stopped", stopped)
+	}
+
+	if got := r.Elapsed(); got != 7500*time.Millisecond {
+		t.Errorf("Elapsed() = %v, want 7.5s", got)
+	}
+	if r.Slow() {
+		t.Error("Slow() = true below the threshold")
+	}
+	clock.Advance(SlowThreshold)
+	if !r.Slow() {
+		t.Error("Slow() = false above the threshold")
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	r.Start("clone app")()
+	if steps := r.Steps(); steps != nil {
+		t.Errorf("Steps() = %v, want nil", steps)
+	}
+	if r.Slow() {
+		t.Error("Slow() = true for a nil Recorder")
+	}
+}
+
+func TestRender(t *testing.T) {
+	steps := []Step{
+		{Label: "worktree frontend", Duration: 12340 * time.Millisecond},
+		{Label: "worktree api", Duration: 1234567 * time.Microsecond},
+		{Label: "worktree lib", Duration: 81234 * time.Microsecond},
+	}
+
+	var buf bytes.Buffer
+	Render(&buf, steps, 13700*time.Millisecond)
+
+	want := `Timings:
+  worktree frontend     12.3s
+  worktree api           1.2s
+  worktree lib           81ms
+  Total                 13.7s
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
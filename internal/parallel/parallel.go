@@ -0,0 +1,45 @@
+package parallel
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sync"
+)
+
+// Task is a named unit of work, such as fetching one repository
+type Task struct {
+	Name string
+	Run  func() error
+}
+
+// Run runs the tasks with at most limit of them at once (one when limit is not positive).
+// Unlike errgroup, a failing task does not stop the others: every task runs to completion.
+// It returns the error of each task in task order, and all failures joined into one error
+// that names each failed task, or nil when every task succeeded.
+func Run(limit int, tasks []Task) ([]error, error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = task.Run()
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", tasks[i].Name, err))
+		}
+	}
+	return errs, stderrors.Join(failed...)
+}
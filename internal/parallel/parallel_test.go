@@ -0,0 +1,75 @@
+package parallel
+
+import (
+	stderrors "errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun_AllTasksRunDespiteFailures(t *testing.T) {
+	errNetwork := stderrors.New("network unreachable")
+	var ran atomic.Int32
+	tasks := []Task{
+		{Name: "app", Run: func() error { ran.Add(1); return errNetwork }},
+		{Name: "lib", Run: func() error { time.Sleep(10 * time.Millisecond); ran.Add(1); return nil }},
+		{Name: "web", Run: func() error { ran.Add(1); return stderrors.New("permission denied") }},
+		{Name: "api", Run: func() error { time.Sleep(10 * time.Millisecond); ran.Add(1); return nil }},
+	}
+
+	errs, err := Run(2, tasks)
+	if got := ran.Load(); got != 4 {
+		t.Errorf("%d tasks ran, want 4", got)
+	}
+	if errs[0] != errNetwork || errs[1] != nil || errs[2] == nil || errs[3] != nil {
+		t.Errorf("unexpected task errors: %v", errs)
+	}
+	if err == nil {
+		t.Fatal("expected a joined error")
+	}
+	for _, want := range []string{"app: network unreachable", "web: permission denied"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err.Error())
+		}
+	}
+	if strings.Contains(err.Error(), "lib") || strings.Contains(err.Error(), "api") {
+		t.Errorf("successful tasks should not be named: %q", err.Error())
+	}
+	if !stderrors.Is(err, errNetwork) {
+		t.Error("expected the joined error to wrap the task errors")
+	}
+}
+
+func TestRun_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	var tasks []Task
+	for range 8 {
+		tasks = append(tasks, Task{Name: "task", Run: func() error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		}})
+	}
+
+	if _, err := Run(3, tasks); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("%d tasks ran at once, want at most 3", got)
+	}
+}
+
+func TestRun_NoTasks(t *testing.T) {
+	errs, err := Run(4, nil)
+	if len(errs) != 0 || err != nil {
+		t.Errorf("Run(nil) = %v, %v", errs, err)
+	}
+}
@@ -0,0 +1,445 @@
+package slot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+// ArchiveDir is the directory of the project that holds archived slots
+const ArchiveDir = "archives"
+
+// archiveTimeLayout is the timestamp appended to the slot name in archive file names
+const archiveTimeLayout = "20060102-150405"
+
+// archiveSuffix is the extension of archive files
+const archiveSuffix = ".tar.gz"
+
+// worktreeAdminDir is the directory inside an archive that holds the git
+// administrative files (HEAD, index, ...) of each worktree, keyed by repository name
+const worktreeAdminDir = ".devslot-worktrees"
+
+// Archive describes an archived slot
+type Archive struct {
+	Slot       string    // Name of the archived slot
+	Path       string    // Path of the archive file
+	ArchivedAt time.Time // When the slot was archived
+}
+
+// Archive packs a slot, including uncommitted and untracked files, into
+// archives/<name>-<timestamp>.tar.gz and removes its worktrees. Hooks are not run.
+// It returns the path of the archive.
+func (m *Manager) Archive(name string) (string, error) {
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return "", errors.SlotNotFound(name)
+	}
+
+	worktrees, err := worktreeDirs(slotPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read slot directory: %w", err)
+	}
+
+	// The git administrative directory of each linked worktree is archived along
+	// with the slot so that the index and HEAD survive the round trip
+	adminDirs := map[string]string{}
+	bareRepos := map[string]string{}
+	for _, repoName := range worktrees {
+		worktreePath := filepath.Join(slotPath, repoName)
+		gitDir, err := git.WorktreeGitDir(worktreePath)
+		if err != nil {
+			continue
+		}
+		adminDirs[repoName] = gitDir
+		bareRepos[repoName] = m.bareRepoPath(repoName)
+	}
+
+	archivesPath := m.archivesDir
+	if err := os.MkdirAll(archivesPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archives directory: %w", err)
+	}
+	archivePath := filepath.Join(archivesPath, name+"-"+time.Now().Format(archiveTimeLayout)+archiveSuffix)
+	if _, err := os.Stat(archivePath); err == nil {
+		return "", fmt.Errorf("archive %s already exists", archivePath)
+	}
+
+	tmp, err := os.CreateTemp(archivesPath, TempPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchive(tmp, name, slotPath, adminDirs); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), archivePath); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	// Everything is in the archive now, so uncommitted changes can be dropped
+	for _, repoName := range worktrees {
+		bareRepoPath, ok := bareRepos[repoName]
+		if !ok || !git.IsValidRepository(bareRepoPath) {
+			continue
+		}
+		if err := git.ForceRemoveWorktree(bareRepoPath, filepath.Join(slotPath, repoName)); err != nil {
+			m.warnf("Warning: failed to remove worktree %s: %v\n", repoName, err)
+		}
+	}
+
+	if err := os.RemoveAll(slotPath); err != nil {
+		return archivePath, fmt.Errorf("failed to remove slot directory: %w", err)
+	}
+
+	return archivePath, nil
+}
+
+// writeArchive writes the slot directory as <name>/ and the worktree
+// administrative directories as .devslot-worktrees/<repo>/ to a gzipped tar stream
+func writeArchive(w io.Writer, name, slotPath string, adminDirs map[string]string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := addTree(tw, slotPath, name); err != nil {
+		return err
+	}
+	repoNames := make([]string, 0, len(adminDirs))
+	for repoName := range adminDirs {
+		repoNames = append(repoNames, repoName)
+	}
+	sort.Strings(repoNames)
+	for _, repoName := range repoNames {
+		if err := addTree(tw, adminDirs[repoName], worktreeAdminDir+"/"+repoName); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// addTree adds the directory root to a tar stream under the name prefix
+func addTree(tw *tar.Writer, root, prefix string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			// Sockets, pipes and devices can't be restored meaningfully
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = prefix
+		if rel != "." {
+			header.Name = prefix + "/" + filepath.ToSlash(rel)
+		}
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// Restore recreates a slot from an archive made by Archive and registers its
+// worktrees with their repositories again. The archive is removed afterwards.
+// It returns the name of the restored slot.
+func (m *Manager) Restore(archivePath string) (string, error) {
+	name, _, ok := parseArchiveName(filepath.Base(archivePath))
+	if !ok {
+		return "", fmt.Errorf("%s is not a slot archive", archivePath)
+	}
+	slotPath := m.getSlotPath(name)
+	if _, err := os.Stat(slotPath); err == nil {
+		return "", errors.SlotAlreadyExists(name)
+	}
+
+	slotsPath := m.slotsDir
+	if err := os.MkdirAll(slotsPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create slots directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(slotsPath, TempPrefix+"restore-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractArchive(archivePath, tmpDir); err != nil {
+		return "", fmt.Errorf("failed to extract archive: %w", err)
+	}
+	extracted := filepath.Join(tmpDir, name)
+	if _, err := os.Stat(extracted); err != nil {
+		return "", fmt.Errorf("archive %s does not contain slot %s", archivePath, name)
+	}
+
+	// Put the administrative directories back into the bare repositories,
+	// pointing them at the final location of the worktrees
+	worktrees, err := worktreeDirs(extracted)
+	if err != nil {
+		return "", fmt.Errorf("failed to read slot directory: %w", err)
+	}
+	repaired := map[string][]string{}
+	for _, repoName := range worktrees {
+		adminSrc := filepath.Join(tmpDir, worktreeAdminDir, filepath.FromSlash(repoName))
+		if _, err := os.Stat(adminSrc); err != nil {
+			continue
+		}
+		bareRepoPath := m.bareRepoPath(repoName)
+		if !git.IsValidRepository(bareRepoPath) {
+			m.warnf("Warning: repository %s not found, worktree not registered\n", repoName)
+			continue
+		}
+
+		adminPath, err := newWorktreeAdminPath(bareRepoPath, filepath.Base(repoName))
+		if err != nil {
+			return "", err
+		}
+		if err := os.Rename(adminSrc, adminPath); err != nil {
+			return "", fmt.Errorf("failed to register worktree %s: %w", repoName, err)
+		}
+		worktreePath := filepath.Join(slotPath, repoName)
+		if err := os.WriteFile(filepath.Join(adminPath, "gitdir"), []byte(filepath.Join(worktreePath, ".git")+"\n"), 0644); err != nil {
+			return "", fmt.Errorf("failed to register worktree %s: %w", repoName, err)
+		}
+		if err := os.WriteFile(filepath.Join(extracted, repoName, ".git"), []byte("gitdir: "+adminPath+"\n"), 0644); err != nil {
+			return "", fmt.Errorf("failed to register worktree %s: %w", repoName, err)
+		}
+		repaired[bareRepoPath] = append(repaired[bareRepoPath], worktreePath)
+	}
+
+	if err := os.Rename(extracted, slotPath); err != nil {
+		return "", fmt.Errorf("failed to restore slot directory: %w", err)
+	}
+
+	for bareRepoPath, worktreePaths := range repaired {
+		if err := git.RepairWorktrees(bareRepoPath, worktreePaths...); err != nil {
+			m.warnf("Warning: %v\n", err)
+		}
+	}
+
+	if err := os.Remove(archivePath); err != nil {
+		m.warnf("Warning: failed to remove archive %s: %v\n", archivePath, err)
+	}
+
+	return name, nil
+}
+
+// newWorktreeAdminPath picks an unused directory in the worktrees/ directory of a
+// bare repository, named like git does after the worktree's base name
+func newWorktreeAdminPath(bareRepoPath, base string) (string, error) {
+	worktreesPath := filepath.Join(bareRepoPath, "worktrees")
+	if err := os.MkdirAll(worktreesPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+	for i := 0; ; i++ {
+		candidate := filepath.Join(worktreesPath, base)
+		if i > 0 {
+			candidate += fmt.Sprint(i)
+		}
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate, nil
+		}
+	}
+}
+
+// extractArchive unpacks a gzipped tar stream into dest, refusing entries that
+// would end up outside of it
+func extractArchive(archivePath, dest string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in archive", header.Name)
+		}
+		mode := fs.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+			if err := os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ListArchives returns the archived slots of the project, sorted by slot name
+// and then from the newest archive to the oldest
+func (m *Manager) ListArchives() ([]Archive, error) {
+	archivesPath := m.archivesDir
+	entries, err := os.ReadDir(archivesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Archive{}, nil
+		}
+		return nil, fmt.Errorf("failed to read archives directory: %w", err)
+	}
+
+	archives := []Archive{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name, archivedAt, ok := parseArchiveName(entry.Name())
+		if !ok {
+			continue
+		}
+		archives = append(archives, Archive{
+			Slot:       name,
+			Path:       filepath.Join(archivesPath, entry.Name()),
+			ArchivedAt: archivedAt,
+		})
+	}
+	sort.SliceStable(archives, func(i, j int) bool {
+		if archives[i].Slot != archives[j].Slot {
+			return archives[i].Slot < archives[j].Slot
+		}
+		return archives[i].ArchivedAt.After(archives[j].ArchivedAt)
+	})
+
+	return archives, nil
+}
+
+// FindArchive resolves an archive given as a path, a file name in the archives
+// directory, or a slot name (the newest archive of that slot)
+func (m *Manager) FindArchive(ref string) (string, error) {
+	if strings.HasSuffix(ref, archiveSuffix) {
+		for _, path := range []string{ref, filepath.Join(m.archivesDir, ref)} {
+			if _, err := os.Stat(path); err == nil {
+				return filepath.Abs(path)
+			}
+		}
+		return "", errors.ArchiveNotFound(ref)
+	}
+
+	archives, err := m.ListArchives()
+	if err != nil {
+		return "", err
+	}
+	for _, archive := range archives {
+		if archive.Slot == ref {
+			return archive.Path, nil
+		}
+	}
+	return "", errors.ArchiveNotFound(ref)
+}
+
+// parseArchiveName splits an archive file name into the slot name and the time it was archived
+func parseArchiveName(fileName string) (string, time.Time, bool) {
+	base, ok := strings.CutSuffix(fileName, archiveSuffix)
+	if !ok || len(base) < len(archiveTimeLayout)+2 {
+		return "", time.Time{}, false
+	}
+	sep := len(base) - len(archiveTimeLayout) - 1
+	if base[sep] != '-' {
+		return "", time.Time{}, false
+	}
+	archivedAt, err := time.ParseInLocation(archiveTimeLayout, base[sep+1:], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return base[:sep], archivedAt, true
+}
+
+// bareRepoPath returns the bare repository of a worktree, falling back to the
+// old naming convention without the .git suffix
+func (m *Manager) bareRepoPath(repoName string) string {
+	bareRepoPath := filepath.Join(m.reposDir, repoName+".git")
+	if !git.IsValidRepository(bareRepoPath) {
+		bareRepoPath = filepath.Join(m.reposDir, repoName)
+	}
+	return bareRepoPath
+}
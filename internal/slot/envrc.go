@@ -0,0 +1,84 @@
+package slot
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// EnvrcFileName is the file in the slot directory rendered from envrc_template
+const EnvrcFileName = ".envrc"
+
+// portRange is the number of ports that the port template function spreads slots over
+const portRange = 1000
+
+// EnvrcData is the data available to the envrc template
+type EnvrcData struct {
+	SlotName string
+	SlotDir  string
+	Repos    []EnvrcRepo
+}
+
+// EnvrcRepo describes a worktree of the slot in the envrc template
+type EnvrcRepo struct {
+	Name   string
+	Path   string
+	Branch string
+}
+
+// loadEnvrcTemplate parses the envrc_template of the project.
+// It returns nil when the project does not configure one.
+func (m *Manager) loadEnvrcTemplate(cfg *config.Config) (*template.Template, error) {
+	if cfg.EnvrcTemplate == "" {
+		return nil, nil
+	}
+
+	path := cfg.EnvrcTemplate
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.projectRoot, path)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.EnvrcTemplateFailed(cfg.EnvrcTemplate, err)
+	}
+
+	tmpl, err := template.New(cfg.EnvrcTemplate).Option("missingkey=error").Funcs(template.FuncMap{
+		"port": func(base int, slotName string) int {
+			h := fnv.New32a()
+			h.Write([]byte(slotName))
+			return base + int(h.Sum32()%portRange)
+		},
+	}).Parse(string(text))
+	if err != nil {
+		return nil, errors.EnvrcTemplateFailed(cfg.EnvrcTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// writeEnvrc renders the envrc template into the slot directory
+func (m *Manager) writeEnvrc(tmpl *template.Template, name string, cfg *config.Config, meta *Metadata) error {
+	slotPath := m.getSlotPath(name)
+	data := EnvrcData{SlotName: name, SlotDir: slotPath}
+	for _, repo := range cfg.Repositories {
+		r := EnvrcRepo{Name: repo.Name, Path: filepath.Join(slotPath, repo.Name)}
+		if meta != nil {
+			r.Branch = meta.Branches[repo.Name]
+		}
+		data.Repos = append(data.Repos, r)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return errors.EnvrcTemplateFailed(cfg.EnvrcTemplate, err)
+	}
+	if err := os.WriteFile(filepath.Join(slotPath, EnvrcFileName), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", EnvrcFileName, err)
+	}
+	return nil
+}
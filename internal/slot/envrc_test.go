@@ -0,0 +1,91 @@
+package slot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/config"
+)
+
+func TestWriteEnvrc(t *testing.T) {
+	projectRoot := t.TempDir()
+	fixture, err := filepath.Abs(filepath.Join("testdata", "envrc.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		EnvrcTemplate: fixture,
+		Repositories:  []config.Repository{{Name: "api"}, {Name: "web"}},
+	}
+	meta := &Metadata{Branches: map[string]string{"api": "devslot/me/feature-x", "web": "main"}}
+
+	m := NewManager(projectRoot)
+	slotPath := m.getSlotPath("feature-x")
+	if err := os.MkdirAll(slotPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := m.loadEnvrcTemplate(cfg)
+	if err != nil {
+		t.Fatalf("loadEnvrcTemplate() error = %v", err)
+	}
+	if err := m.writeEnvrc(tmpl, "feature-x", cfg, meta); err != nil {
+		t.Fatalf("writeEnvrc() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(slotPath, EnvrcFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "export DEVSLOT_SLOT_NAME=feature-x\n" +
+		"export DEVSLOT_SLOT_DIR=" + slotPath + "\n" +
+		"export api_DIR=" + filepath.Join(slotPath, "api") + "  # devslot/me/feature-x\n" +
+		"export web_DIR=" + filepath.Join(slotPath, "web") + "  # main\n" +
+		"export APP_PORT=3" // followed by the slot's port offset
+	if !strings.HasPrefix(string(got), want) {
+		t.Errorf(".envrc = %q, want prefix %q", got, want)
+	}
+
+	// The port is stable for a slot name
+	if err := m.writeEnvrc(tmpl, "feature-x", cfg, meta); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := os.ReadFile(filepath.Join(slotPath, EnvrcFileName))
+	if string(again) != string(got) {
+		t.Errorf("rendering is not stable: %q != %q", again, got)
+	}
+}
+
+func TestLoadEnvrcTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "parse error", template: "export A=1\n{{ nosuchfunc }}\n", wantErr: "envrc.tmpl:2:"},
+		{name: "unknown field", template: "export A=1\nexport B=2\n{{ .Nope }}\n", wantErr: "envrc.tmpl:3:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := t.TempDir()
+			if err := os.WriteFile(filepath.Join(projectRoot, "envrc.tmpl"), []byte(tt.template), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg := &config.Config{EnvrcTemplate: "envrc.tmpl"}
+			m := NewManager(projectRoot)
+			if err := os.MkdirAll(m.getSlotPath("x"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			tmpl, err := m.loadEnvrcTemplate(cfg)
+			if err == nil {
+				err = m.writeEnvrc(tmpl, "x", cfg, nil)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package slot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/config"
+)
+
+// SharedCacheDir is the directory, relative to the project root, that shared_links point into
+const SharedCacheDir = ".devslot/cache"
+
+// sharedLink is a path of shared_links in a worktree of a slot
+type sharedLink struct {
+	Name   string // Path of the link relative to the slot directory, e.g. web/node_modules/.cache
+	Link   string // Absolute path of the link
+	Target string // Absolute path of the shared directory it points to
+}
+
+// planLinks lists the shared links of the given repositories of a slot
+func (m *Manager) planLinks(cfg *config.Config, slotPath string, repos []string) []sharedLink {
+	var links []sharedLink
+	for _, repo := range repos {
+		for _, path := range cfg.SharedLinks[repo] {
+			path = filepath.Clean(filepath.FromSlash(path))
+			links = append(links, sharedLink{
+				Name:   filepath.ToSlash(filepath.Join(repo, path)),
+				Link:   filepath.Join(slotPath, repo, path),
+				Target: filepath.Join(m.projectRoot, filepath.FromSlash(SharedCacheDir), repo, path),
+			})
+		}
+	}
+	return links
+}
+
+// linkValue returns what the symlink of a shared link contains: the target relative
+// to the link's directory, so the link survives moving the whole project
+func (l sharedLink) linkValue() string {
+	rel, err := filepath.Rel(filepath.Dir(l.Link), l.Target)
+	if err != nil {
+		return l.Target
+	}
+	return rel
+}
+
+// problem describes what is wrong with a shared link, or returns "" when it is intact
+func (l sharedLink) problem() string {
+	info, err := os.Lstat(l.Link)
+	switch {
+	case os.IsNotExist(err):
+		return "is missing"
+	case err != nil:
+		return err.Error()
+	case info.Mode()&os.ModeSymlink == 0:
+		return "is not a symlink to the shared cache"
+	}
+	if value, err := os.Readlink(l.Link); err != nil || (value != l.linkValue() && value != l.Target) {
+		return "points somewhere other than the shared cache"
+	}
+	if info, err := os.Stat(l.Target); err != nil || !info.IsDir() {
+		return "points at a missing shared cache directory"
+	}
+	return ""
+}
+
+// writeLinks creates the shared links that are missing or point elsewhere, creating
+// their shared directories on first use. A file or directory in the way is kept with
+// a warning, since it may hold work. It returns the links it created.
+func (m *Manager) writeLinks(links []sharedLink) ([]string, error) {
+	var created []string
+	for _, link := range links {
+		if err := os.MkdirAll(link.Target, 0755); err != nil {
+			return created, fmt.Errorf("failed to create shared cache for %s: %w", link.Name, err)
+		}
+		if link.problem() == "" {
+			continue
+		}
+
+		if info, err := os.Lstat(link.Link); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				m.warnf("Keeping %s, which is not a symlink to the shared cache; remove it to share %s\n", link.Name, link.Target)
+				continue
+			}
+			// A stale link of ours, e.g. from an older project location
+			if err := os.Remove(link.Link); err != nil {
+				return created, fmt.Errorf("failed to replace link %s: %w", link.Name, err)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(link.Link), 0755); err != nil {
+			return created, fmt.Errorf("failed to create directory for %s: %w", link.Name, err)
+		}
+		if err := os.Symlink(link.linkValue(), link.Link); err != nil {
+			return created, fmt.Errorf("failed to link %s: %w", link.Name, err)
+		}
+		created = append(created, link.Name)
+	}
+	return created, nil
+}
+
+// removeLinks removes the shared links of a worktree before it is removed, so the
+// shared cache is never followed and git does not see them as untracked files.
+// Paths that are not symlinks are left alone.
+func removeLinks(links []sharedLink) {
+	for _, link := range links {
+		if info, err := os.Lstat(link.Link); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			os.Remove(link.Link)
+		}
+	}
+}
+
+// BrokenLinks returns the shared links of a slot's existing worktrees that are not
+// intact, mapped to what is wrong with them
+func (m *Manager) BrokenLinks(name string, cfg *config.Config) (map[string]string, error) {
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	var repos []string
+	for _, repo := range cfg.Repositories {
+		if isWorktree(filepath.Join(slotPath, repo.Name)) {
+			repos = append(repos, repo.Name)
+		}
+	}
+
+	broken := map[string]string{}
+	for _, link := range m.planLinks(cfg, slotPath, repos) {
+		if problem := link.problem(); problem != "" {
+			broken[link.Name] = problem
+		}
+	}
+	return broken, nil
+}
@@ -0,0 +1,82 @@
+package slot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MetadataFileName is the name of the metadata file stored in each slot directory
+const MetadataFileName = ".devslot.json"
+
+// Metadata records how a slot was created
+type Metadata struct {
+	CreatedAt      time.Time `json:"created_at"`
+	DevslotVersion string    `json:"devslot_version,omitempty"`
+	Args           []string  `json:"args,omitempty"`
+	// Branch is the branch the slot was created with or last checked out
+	Branch   string            `json:"branch,omitempty"`
+	Branches map[string]string `json:"branches,omitempty"`
+	// Description says what the slot is for
+	Description string `json:"description,omitempty"`
+	// Groups are the repository groups the slot was created with (all repositories when empty)
+	Groups []string `json:"groups,omitempty"`
+	// Template is the slot template the slot was created from
+	Template string `json:"template,omitempty"`
+	// Repositories are the repositories the slot was limited to by its template (all when empty)
+	Repositories []string `json:"repositories,omitempty"`
+	// Incomplete lists the repositories whose worktree could not be created when the
+	// slot was kept after a failed create; reload clears it once they are created
+	Incomplete []string `json:"incomplete,omitempty"`
+	// Seeds holds the SHA-256 of each file copied from seed_files, by its path in the slot,
+	// so that reseeding replaces only copies that were not changed since
+	Seeds map[string]string `json:"seeds,omitempty"`
+}
+
+// Provenance describes which devslot version and command created the slot.
+// It returns an empty string when the information was not recorded.
+func (m *Metadata) Provenance() string {
+	if m == nil || m.DevslotVersion == "" {
+		return ""
+	}
+	return fmt.Sprintf("slot created by devslot %s with 'devslot %s'", m.DevslotVersion, strings.Join(m.Args, " "))
+}
+
+// LoadMetadata reads the metadata file of a slot.
+// It returns nil without error when the slot has no metadata (e.g. slots created by older versions).
+func LoadMetadata(slotPath string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(slotPath, MetadataFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read slot metadata: %w", err)
+	}
+
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse slot metadata: %w", err)
+	}
+	if meta.Branches == nil {
+		meta.Branches = map[string]string{}
+	}
+
+	return &meta, nil
+}
+
+// SaveMetadata writes the metadata file of a slot
+func SaveMetadata(slotPath string, meta *Metadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode slot metadata: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(slotPath, MetadataFileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write slot metadata: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,387 @@
+package slot
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/hook"
+)
+
+// PlannedWorktree is a worktree an operation creates
+type PlannedWorktree struct {
+	Repo      string // Repository name
+	Path      string // Worktree directory
+	Branch    string // Branch checked out in the worktree
+	NewBranch bool   // Whether the branch is created (create, and reload of an incomplete slot)
+	Base      string // Where a new branch starts; empty for the default branch (create only)
+	Remote    string // Remote a new branch starts from (create, and reload of an incomplete slot)
+}
+
+// CreatePlan describes what creating a slot does, before anything is changed
+type CreatePlan struct {
+	Name         string
+	Path         string
+	Branch       string            // Branch checked out in every worktree
+	BranchPrefix string            // Template the branch was named from; empty when the branch was given
+	Worktrees    []PlannedWorktree // Worktrees to create
+	Skipped      []string          // Optional repositories that are not cloned
+	Hooks        []hook.Type       // Hooks that run
+
+	cfg  *config.Config
+	opts *CreateOptions
+}
+
+// DestroyPlan describes what destroying a slot removes
+type DestroyPlan struct {
+	Name      string
+	Path      string
+	Worktrees []string    // Repository names of the worktrees in the slot
+	Unknown   []string    // Other directories in the slot, removed with it
+	Foreign   []string    // Git worktrees of repositories that are not configured, removed with DestroyOptions.Force
+	Hooks     []hook.Type // Hooks that run
+
+	cfg         *config.Config
+	meta        *Metadata
+	bareRepos   map[string]string // Bare repository of each worktree
+	strictHooks bool              // A failing post-destroy hook fails the destruction
+}
+
+// ReloadPlan describes the worktrees a reload creates and removes
+type ReloadPlan struct {
+	Name     string
+	Path     string
+	Missing  []PlannedWorktree // Worktrees to create, on the slot's branch for repositories that failed in create and the default branch otherwise
+	Existing []string          // Repositories whose worktree already exists
+	Repair   []PlannedWorktree // Corrupted worktrees to recreate, with ReloadOptions.Repair
+	Skipped  []string          // Optional repositories that are not cloned
+	Prune    []string          // Worktrees of repositories no longer configured, with ReloadOptions.Prune
+	Hooks    []hook.Type       // Hooks that run
+
+	cfg  *config.Config
+	meta *Metadata
+	opts *ReloadOptions
+}
+
+// PlanCreate checks that a slot can be created and works out its branch and worktrees.
+// Every problem found, such as missing repositories, branches checked out elsewhere and
+// missing seed files, is reported in one error.
+func (m *Manager) PlanCreate(name string, cfg *config.Config, opts *CreateOptions) (*CreatePlan, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	// Problems are collected so that all of them are reported at once, before anything is created
+	var problems []error
+	if _, err := os.Stat(slotPath); err == nil {
+		problems = append(problems, errors.SlotAlreadyExists(name))
+	} else {
+		// Slot names that differ only in case share a directory on case-insensitive filesystems
+		existing, err := m.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range existing {
+			if strings.EqualFold(other, name) {
+				problems = append(problems, errors.SlotAlreadyExists(other))
+			}
+		}
+	}
+
+	// Only the repositories of the requested groups get worktrees
+	if len(opts.Groups) > 0 {
+		if _, err := cfg.ReposInGroups(opts.Groups...); err != nil {
+			return nil, err
+		}
+		cfg = cfg.WithGroups(opts.Groups)
+	}
+	cfg = cfg.WithRepositories(opts.Repositories)
+
+	if err := m.checkRepositoryPaths(slotPath, cfg); err != nil {
+		return nil, err
+	}
+
+	plan := &CreatePlan{Name: name, Path: slotPath, Branch: opts.Branch, cfg: cfg, opts: opts}
+
+	// Branch checked out in every worktree
+	if plan.Branch == "" {
+		plan.BranchPrefix = opts.BranchPrefix
+		if plan.BranchPrefix == "" {
+			plan.BranchPrefix = git.GetBranchPrefix(cfg.BranchPrefix)
+		}
+		plan.Branch = git.RenderBranchName(plan.BranchPrefix, name)
+
+		if opts.FreshBranch {
+			remotes := make(map[string]string, len(cfg.Repositories))
+			for _, repo := range cfg.Repositories {
+				remotes[filepath.Join(m.reposDir, repo.BareRepoName())] = repo.RemoteName()
+			}
+			plan.Branch = git.UniqueBranchName(plan.Branch, remotes)
+		}
+	}
+
+	for _, repo := range cfg.Repositories {
+		bareRepoPath := filepath.Join(m.reposDir, repo.BareRepoName())
+		if err := m.CheckRepository(repo); err != nil {
+			if repo.Optional && stderrors.Is(err, ErrNotCloned) {
+				plan.Skipped = append(plan.Skipped, repo.Name)
+				continue
+			}
+			problems = append(problems, err)
+			continue
+		}
+		worktree := PlannedWorktree{Repo: repo.Name, Path: filepath.Join(slotPath, repo.Name), Branch: plan.Branch, Remote: repo.RemoteName()}
+		if opts.Branch == "" {
+			// An explicit --branch applies to every repository, overriding the template
+			if override, ok := opts.Branches[repo.Name]; ok {
+				if override.Branch != "" {
+					worktree.Branch = override.Branch
+				}
+				worktree.Base = override.Base
+			}
+			worktree.NewBranch = !git.BranchExists(bareRepoPath, repo.RemoteName(), worktree.Branch)
+		}
+		if err := checkWorktree(bareRepoPath, worktree); err != nil {
+			problems = append(problems, err)
+		}
+		plan.Worktrees = append(plan.Worktrees, worktree)
+	}
+
+	// The envrc template and the seed files are checked here too, so that a slot is
+	// never left half-created because of them
+	if _, err := m.loadEnvrcTemplate(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	planned := make([]string, 0, len(plan.Worktrees))
+	for _, worktree := range plan.Worktrees {
+		planned = append(planned, worktree.Repo)
+	}
+	if _, err := m.planSeeds(cfg, planned, true); err != nil {
+		problems = append(problems, err)
+	}
+
+	switch len(problems) {
+	case 0:
+	case 1:
+		return nil, problems[0]
+	default:
+		return nil, errors.CreatePreflightFailed(name, problems)
+	}
+
+	plan.Hooks = m.existingHooks(cfg, hook.PostCreate)
+	return plan, nil
+}
+
+// PlanDestroy lists what destroying a slot removes. Worktrees are matched to
+// their bare repositories through the configured and recorded repositories;
+// git worktrees of other repositories make the plan fail without opts.Force.
+func (m *Manager) PlanDestroy(name string, cfg *config.Config, opts *DestroyOptions) (*DestroyPlan, error) {
+	if opts == nil {
+		opts = &DestroyOptions{}
+	}
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return nil, errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repositories the slot may have worktrees of: the configured ones, including
+	// those outside the slot's groups, and those recorded when it was created
+	known := map[string]string{}
+	for _, repo := range cfg.Repositories {
+		known[repo.Name] = m.bareRepoPath(repo.Name)
+	}
+	if meta != nil {
+		for repoName := range meta.Branches {
+			if _, ok := known[repoName]; !ok {
+				known[repoName] = m.bareRepoPath(repoName)
+			}
+		}
+	}
+
+	dirs, err := worktreeDirs(slotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slot directory: %w", err)
+	}
+
+	plan := &DestroyPlan{Name: name, Path: slotPath, meta: meta, bareRepos: map[string]string{}, strictHooks: opts.StrictHooks || cfg.StrictHooks}
+	for _, dir := range dirs {
+		worktreePath := filepath.Join(slotPath, dir)
+		bareRepoPath, ok := known[dir]
+		switch {
+		case ok && isWorktree(worktreePath):
+			plan.Worktrees = append(plan.Worktrees, dir)
+			plan.bareRepos[dir] = bareRepoPath
+		case isWorktree(worktreePath):
+			plan.Foreign = append(plan.Foreign, dir)
+		case strings.HasPrefix(dir, "."):
+			// Tool state such as .direnv, removed with the slot without a mention
+		default:
+			plan.Unknown = append(plan.Unknown, dir)
+		}
+	}
+	if len(plan.Foreign) > 0 && !opts.Force {
+		return nil, errors.UnknownWorktrees(name, plan.Foreign)
+	}
+
+	plan.cfg = slotConfig(cfg, meta)
+	plan.Hooks = m.existingHooks(plan.cfg, hook.PreDestroy, hook.PostDestroy)
+	return plan, nil
+}
+
+// PlanReload lists the missing worktrees of a slot and, with opts.Prune, the
+// worktrees to remove. Worktrees that pruning keeps are reported as warnings.
+// Worktrees no longer connected to their repository are recreated with
+// opts.Repair and make the plan fail without it.
+func (m *Manager) PlanReload(name string, cfg *config.Config, opts *ReloadOptions) (*ReloadPlan, error) {
+	if opts == nil {
+		opts = &ReloadOptions{}
+	}
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return nil, errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = &Metadata{
+			CreatedAt: time.Now(),
+			Branches:  map[string]string{},
+		}
+	}
+	cfg = slotConfig(cfg, meta)
+	if err := m.checkRepositoryPaths(slotPath, cfg); err != nil {
+		return nil, err
+	}
+
+	plan := &ReloadPlan{Name: name, Path: slotPath, cfg: cfg, meta: meta, opts: opts}
+	for _, repo := range cfg.Repositories {
+		bareRepoPath := filepath.Join(m.reposDir, repo.BareRepoName())
+		worktreePath := filepath.Join(slotPath, repo.Name)
+		if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+			if !isWorktree(worktreePath) || !git.IsValidRepository(bareRepoPath) {
+				plan.Existing = append(plan.Existing, repo.Name)
+				continue
+			}
+			if err := git.CheckWorktree(worktreePath); err == nil {
+				plan.Existing = append(plan.Existing, repo.Name)
+				continue
+			}
+			branch, err := previousBranch(bareRepoPath, repo.RemoteName(), meta.Branches[repo.Name])
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine branch for %s: %w", repo.Name, err)
+			}
+			plan.Repair = append(plan.Repair, PlannedWorktree{Repo: repo.Name, Path: worktreePath, Branch: branch})
+			continue
+		}
+		if repo.Optional && !git.IsValidRepository(bareRepoPath) {
+			plan.Skipped = append(plan.Skipped, repo.Name)
+			continue
+		}
+
+		// Worktrees that failed when the slot was created get the slot's branch, created the
+		// way create would have; other missing worktrees are created on the default branch
+		if slices.Contains(meta.Incomplete, repo.Name) && meta.Branch != "" {
+			plan.Missing = append(plan.Missing, PlannedWorktree{Repo: repo.Name, Path: worktreePath, Branch: meta.Branch,
+				NewBranch: !git.BranchExists(bareRepoPath, repo.RemoteName(), meta.Branch), Remote: repo.RemoteName()})
+			continue
+		}
+		branch, err := git.GetDefaultBranch(bareRepoPath, repo.RemoteName())
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch for %s: %w", repo.Name, err)
+		}
+		plan.Missing = append(plan.Missing, PlannedWorktree{Repo: repo.Name, Path: worktreePath, Branch: branch})
+	}
+
+	if len(plan.Repair) > 0 && !opts.Repair {
+		repos := make([]string, 0, len(plan.Repair))
+		for _, worktree := range plan.Repair {
+			repos = append(repos, worktree.Repo)
+		}
+		return nil, errors.WorktreeCorrupted(name, repos)
+	}
+
+	if opts.Prune {
+		if plan.Prune, err = m.pruneCandidates(slotPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	plan.Hooks = m.existingHooks(cfg, hook.PostReload)
+	return plan, nil
+}
+
+// previousBranch returns the branch a repaired worktree is recreated on: the
+// branch recorded for it when it still exists, otherwise the default branch
+func previousBranch(bareRepoPath, remote, recorded string) (string, error) {
+	if recorded != "" && git.BranchExists(bareRepoPath, remote, recorded) {
+		return recorded, nil
+	}
+	return git.GetDefaultBranch(bareRepoPath, remote)
+}
+
+// pruneCandidates returns the worktrees of a slot whose repositories cfg no longer
+// lists. Worktrees with uncommitted changes, or whose repository is gone so that
+// their state cannot be checked, are kept with a warning.
+func (m *Manager) pruneCandidates(slotPath string, cfg *config.Config) ([]string, error) {
+	worktrees, err := worktreeDirs(slotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slot directory: %w", err)
+	}
+
+	var candidates []string
+	for _, repoName := range worktrees {
+		if slices.ContainsFunc(cfg.Repositories, func(r config.Repository) bool { return r.Name == repoName }) {
+			continue
+		}
+		worktreePath := filepath.Join(slotPath, repoName)
+		if !isWorktree(worktreePath) {
+			continue // Not created by devslot
+		}
+		if !git.IsValidRepository(m.bareRepoPath(repoName)) {
+			m.warnf("Keeping %s: its repository is gone, remove the directory yourself if it is no longer needed\n", repoName)
+			continue
+		}
+		if dirty, err := git.IsDirty(worktreePath); err != nil || dirty {
+			m.warnf("Keeping worktree %s: it has uncommitted changes\n", repoName)
+			continue
+		}
+		candidates = append(candidates, repoName)
+	}
+	return candidates, nil
+}
+
+// existingHooks returns the hook types among types that have a hook file or inline commands
+func (m *Manager) existingHooks(cfg *config.Config, types ...hook.Type) []hook.Type {
+	var existing []hook.Type
+	for _, hookType := range types {
+		if m.hookRunner.Exists(hookType) || len(cfg.InlineHooks(string(hookType))) > 0 {
+			existing = append(existing, hookType)
+		}
+	}
+	return existing
+}
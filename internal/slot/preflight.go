@@ -0,0 +1,54 @@
+package slot
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+)
+
+// ErrNotCloned is wrapped by the error of CheckRepository for repositories that are not cloned
+var ErrNotCloned = stderrors.New("does not exist (run 'devslot init' first)")
+
+// CheckRepository checks that the bare repository of a configured repository exists
+// and is a git repository
+func (m *Manager) CheckRepository(repo config.Repository) error {
+	bareRepoPath := filepath.Join(m.reposDir, repo.BareRepoName())
+	if git.IsValidRepository(bareRepoPath) {
+		return nil
+	}
+	if _, err := os.Stat(bareRepoPath); err == nil {
+		return fmt.Errorf("bare repository %s: %s is not a valid git repository (move it away and run 'devslot init')", repo.Name, bareRepoPath)
+	}
+	return fmt.Errorf("bare repository %s %w", repo.Name, ErrNotCloned)
+}
+
+// checkWorktree checks that git can add a planned worktree: its branch must not be
+// checked out elsewhere, and the base of a new branch must exist or be fetchable
+func checkWorktree(bareRepoPath string, worktree PlannedWorktree) error {
+	if git.RefExists(bareRepoPath, "refs/heads/"+worktree.Branch) {
+		if other := git.BranchWorktree(bareRepoPath, worktree.Branch); other != "" {
+			return fmt.Errorf("branch %s of %s is already checked out in %s", worktree.Branch, worktree.Repo, other)
+		}
+		return nil
+	}
+
+	base := worktree.Base
+	if base == "" || git.BranchExists(bareRepoPath, worktree.Remote, worktree.Branch) ||
+		git.CommitExists(bareRepoPath, base) || git.RefExists(bareRepoPath, "refs/remotes/"+worktree.Remote+"/"+base) {
+		return nil
+	}
+	// Fetching before the worktree is added may still bring the base, except into
+	// single-branch clones and repositories that are not fetched
+	if branch := git.SingleBranch(bareRepoPath, worktree.Remote); branch != "" {
+		return errors.BaseNotInSingleBranch(base, branch, bareRepoPath, worktree.Remote)
+	}
+	if !git.ShouldFetchOrigin(bareRepoPath, worktree.Remote) {
+		return fmt.Errorf("base %s of %s does not exist", base, worktree.Repo)
+	}
+	return nil
+}
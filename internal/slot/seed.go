@@ -0,0 +1,115 @@
+package slot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+// seedFile is a file of seed_files to copy into a slot
+type seedFile struct {
+	Source string // Absolute path of the file in the project
+	Dest   string // Path of the copy, relative to the slot directory
+}
+
+// planSeeds expands the seed_files of the project for the given repositories of a
+// slot. Entries without {repo} in their destination are only included with slotRoot.
+// It fails, listing them, when source files are missing.
+func (m *Manager) planSeeds(cfg *config.Config, repos []string, slotRoot bool) ([]seedFile, error) {
+	var seeds []seedFile
+	var missing []string
+	add := func(source, dest string) {
+		path := filepath.Join(m.projectRoot, source)
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			missing = append(missing, source)
+			return
+		}
+		seeds = append(seeds, seedFile{Source: path, Dest: filepath.FromSlash(dest)})
+	}
+
+	for _, source := range slices.Sorted(maps.Keys(cfg.SeedFiles)) {
+		dest := cfg.SeedFiles[source]
+		if !strings.Contains(dest, config.SeedRepoPlaceholder) {
+			if slotRoot {
+				add(source, dest)
+			}
+			continue
+		}
+		for _, repo := range repos {
+			add(strings.ReplaceAll(source, config.SeedRepoPlaceholder, repo), strings.ReplaceAll(dest, config.SeedRepoPlaceholder, repo))
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.SeedFilesMissing(missing)
+	}
+	return seeds, nil
+}
+
+// writeSeeds copies seed files into a slot, keeping their permissions, and records
+// the checksum of each copy in the metadata. A file that already exists is only
+// replaced when it is still the copy that was seeded, so files changed in the slot
+// (or shipped by the repository) are kept. It returns the files it wrote.
+func (m *Manager) writeSeeds(slotPath string, seeds []seedFile, meta *Metadata) ([]string, error) {
+	var written []string
+	for _, seed := range seeds {
+		data, err := os.ReadFile(seed.Source)
+		if err != nil {
+			return written, fmt.Errorf("failed to read seed file: %w", err)
+		}
+		info, err := os.Stat(seed.Source)
+		if err != nil {
+			return written, fmt.Errorf("failed to read seed file: %w", err)
+		}
+
+		key := filepath.ToSlash(seed.Dest)
+		dest := filepath.Join(slotPath, seed.Dest)
+		if current, err := os.ReadFile(dest); err == nil {
+			if bytes.Equal(current, data) {
+				meta.recordSeed(key, data)
+				continue
+			}
+			if recorded, ok := meta.Seeds[key]; !ok || recorded != checksum(current) {
+				m.warnf("Keeping %s, which differs from its seed file\n", key)
+				continue
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory for %s: %w", key, err)
+		}
+		if err := os.WriteFile(dest, data, info.Mode().Perm()); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		// WriteFile applies the umask and keeps the mode of an existing file
+		if err := os.Chmod(dest, info.Mode().Perm()); err != nil {
+			return written, fmt.Errorf("failed to set the permissions of %s: %w", key, err)
+		}
+		meta.recordSeed(key, data)
+		written = append(written, key)
+	}
+	return written, nil
+}
+
+// recordSeed remembers the checksum of a seeded file
+func (meta *Metadata) recordSeed(path string, data []byte) {
+	if meta.Seeds == nil {
+		meta.Seeds = map[string]string{}
+	}
+	meta.Seeds[path] = checksum(data)
+}
+
+// checksum returns the hex SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -3,170 +3,505 @@ package slot
 import (
 	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/yammerjp/devslot/internal/config"
 	"github.com/yammerjp/devslot/internal/errors"
 	"github.com/yammerjp/devslot/internal/git"
 	"github.com/yammerjp/devslot/internal/hook"
+	"github.com/yammerjp/devslot/internal/metrics"
+	"github.com/yammerjp/devslot/internal/parallel"
 )
 
+// TempPrefix marks temporary directories in slots/ that are not slots
+const TempPrefix = ".tmp-"
+
 // Manager manages slots
 type Manager struct {
 	projectRoot string
+	reposDir    string
+	slotsDir    string
+	archivesDir string
 	hookRunner  *hook.Runner
+	// Err receives warnings about operations that continue despite a failure; nil discards them
+	Err io.Writer
+	// Timings records how long the worktree of each repository took; nil records nothing
+	Timings *metrics.Recorder
 }
 
 // CreateOptions contains options for creating a slot
 type CreateOptions struct {
-	Branch string // Branch to checkout (empty means default branch)
+	Branch       string   // Branch to checkout (empty means a new branch named from BranchPrefix)
+	BranchPrefix string   // Branch name template for the new branch (see git.RenderBranchName)
+	FreshBranch  bool     // Add a numeric suffix to the new branch name instead of reusing an existing branch
+	Description  string   // What the slot is for, recorded in the slot metadata
+	Groups       []string // Only create worktrees for repositories in these groups (all when empty)
+	Version      string   // devslot version recorded in the slot metadata
+	Args         []string // Sanitized command-line arguments recorded in the slot metadata
+	KeepPartial  bool     // Keep the created worktrees when others fail, marking the slot incomplete
+	// Template is the name of the slot template the options come from, recorded in the slot metadata
+	Template string
+	// Repositories limits the slot to these repositories (all when empty); it is combined with Groups
+	Repositories []string
+	// Branches overrides the branch of individual repositories when Branch is empty
+	Branches map[string]config.BranchOverride
+}
+
+// ApplyTemplate fills in the options from the named slot template of cfg. Options
+// that are already set take precedence: a description or groups replace those of
+// the template, and a branch replaces its per-repository branches.
+func (o *CreateOptions) ApplyTemplate(cfg *config.Config, name string) error {
+	template, err := cfg.Template(name)
+	if err != nil {
+		return err
+	}
+	o.Template = name
+	if o.Description == "" {
+		o.Description = template.Description
+	}
+	if len(o.Groups) == 0 {
+		o.Repositories = template.Repositories
+	}
+	if o.Branch == "" {
+		o.Branches = template.Branches
+	}
+	return nil
+}
+
+// DestroyOptions contains options for destroying a slot
+type DestroyOptions struct {
+	Force       bool // Also remove git worktrees of repositories that are not configured
+	StrictHooks bool // Fail when the post-destroy hook fails, as with strict_hooks in devslot.yaml
+}
+
+// ReloadOptions contains options for reloading a slot
+type ReloadOptions struct {
+	UpdateBranches bool // Re-record the currently checked-out branches in the slot metadata
+	Prune          bool // Remove worktrees of repositories that are no longer configured
+	Repair         bool // Recreate worktrees that are no longer connected to their repository
+	Reseed         bool // Copy the seed files again, replacing copies that were not changed since
+}
+
+// CreateResult reports what creating a slot did
+type CreateResult struct {
+	SlotPath string
+	Branch   string       // Branch checked out in every worktree
+	Repos    []RepoResult // Worktrees that were created, in devslot.yaml order
+	Skipped  []string     // Optional repositories that are not cloned
+	Seeded   []string     // Seed files copied into the slot, relative to the slot directory
+	Linked   []string     // Shared links created in the slot, relative to the slot directory
+}
+
+// RepoResult is a worktree created in a slot
+type RepoResult struct {
+	Name           string
+	Branch         string
+	WorktreePath   string
+	ExistingBranch bool // Whether the branch already existed and was checked out again
+}
+
+// ReloadResult reports the worktrees a reload changed
+type ReloadResult struct {
+	Created  []string // Repositories whose missing worktree was created
+	Repaired []string // Repositories whose corrupted worktree was recreated
+	Skipped  []string // Repositories left as they were: their worktree exists, or they are optional and not cloned
+	Pruned   []string // Repositories whose worktree was removed because they are no longer configured
+	Seeded   []string // Seed files copied into the slot, relative to the slot directory
+	Linked   []string // Shared links created in the slot, relative to the slot directory
+}
+
+// DestroyResult reports what destroying a slot removed
+type DestroyResult struct {
+	RemovedWorktrees []string // Repositories whose worktree was removed
+	Warnings         []string // Problems that did not stop the slot from being destroyed
+}
+
+// CheckoutOptions contains options for switching the branch of a slot
+type CheckoutOptions struct {
+	Stash   bool   // Stash uncommitted changes instead of leaving dirty worktrees untouched
+	Missing string // What to do with repositories without the branch: MissingSkip (default), MissingDefault or MissingFail
+}
+
+// Handling of repositories where the branch to check out doesn't exist
+const (
+	MissingSkip    = "skip"    // Leave them on their current branch
+	MissingDefault = "default" // Switch them to their default branch
+	MissingFail    = "fail"    // Abort before switching any repository
+)
+
+// CheckoutResult reports what happened to each repository during a checkout
+type CheckoutResult struct {
+	Switched   []string          // Repositories now on the requested branch
+	Stashed    []string          // Repositories whose changes were stashed before switching
+	Missing    []string          // Repositories where the branch does not exist (left unchanged unless switched to their default branch)
+	Defaulted  map[string]string // Repositories without the branch that were switched to their default branch
+	Dirty      []string          // Repositories with uncommitted changes (left unchanged)
+	NoWorktree []string          // Repositories without a worktree in the slot (left for reload to create)
+	Total      int               // Repositories of the slot, without groups it was not created with and absent optional ones
+}
+
+// PullOptions contains options for updating the worktrees of a slot from their upstreams
+type PullOptions struct {
+	Rebase   bool // Rebase onto the upstream instead of fast-forwarding
+	Parallel int  // Number of repositories fetched at once (1 when not positive)
+}
+
+// PullState describes what happened to a worktree during Pull
+type PullState string
+
+const (
+	PullUpdated    PullState = "updated"
+	PullUpToDate   PullState = "up to date"
+	PullMissing    PullState = "missing"
+	PullNoUpstream PullState = "no upstream"
+	PullDirty      PullState = "dirty"
+	PullFailed     PullState = "failed"
+)
+
+// PullResult is the outcome of pulling one repository of a slot
+type PullResult struct {
+	Name     string
+	State    PullState
+	Branch   string // Branch checked out in the worktree
+	Upstream string // Upstream the branch was updated from (e.g. origin/main)
+	From, To string // Commits before and after the update
+	Err      error  // Why the update failed (PullFailed only)
+}
+
+// RepoStatus describes the state of a repository worktree in a slot
+type RepoStatus struct {
+	Name           string
+	Optional       bool
+	Exists         bool
+	Branch         string
+	RecordedBranch string
+}
+
+// Drifted reports whether the checked-out branch differs from the branch recorded at creation
+func (s RepoStatus) Drifted() bool {
+	return s.Exists && s.RecordedBranch != "" && s.Branch != s.RecordedBranch
 }
 
 // NewManager creates a new slot manager
 func NewManager(projectRoot string) *Manager {
 	return &Manager{
 		projectRoot: projectRoot,
+		reposDir:    filepath.Join(projectRoot, "repos"),
+		slotsDir:    filepath.Join(projectRoot, "slots"),
+		archivesDir: filepath.Join(projectRoot, ArchiveDir),
 		hookRunner:  hook.NewRunner(projectRoot),
 	}
 }
 
+// SetDirs points the manager (and the hooks it runs) at the repos and slots
+// directories configured in devslot.yaml
+func (m *Manager) SetDirs(cfg *config.Config) {
+	m.reposDir = cfg.ReposDir(m.projectRoot)
+	m.slotsDir = cfg.SlotsDir(m.projectRoot)
+	m.archivesDir = cfg.ArchivesDir(m.projectRoot)
+	m.hookRunner.ReposDir = m.reposDir
+	m.hookRunner.SlotsDir = m.slotsDir
+}
+
+// SetHookStdout redirects the standard output of hooks run by the manager
+func (m *Manager) SetHookStdout(w io.Writer) {
+	m.hookRunner.Stdout = w
+}
+
+// SetHookStderr redirects the standard error of hooks run by the manager
+func (m *Manager) SetHookStderr(w io.Writer) {
+	m.hookRunner.Stderr = w
+}
+
+// warnf writes a warning to Err, if set
+func (m *Manager) warnf(format string, args ...any) {
+	if m.Err != nil {
+		fmt.Fprintf(m.Err, format, args...)
+	}
+}
+
 // Create creates a new slot
-func (m *Manager) Create(name string, cfg *config.Config, opts *CreateOptions) error {
-	if err := m.validateSlotName(name); err != nil {
-		return err
+func (m *Manager) Create(name string, cfg *config.Config, opts *CreateOptions) (*CreateResult, error) {
+	plan, err := m.PlanCreate(name, cfg, opts)
+	if err != nil {
+		return nil, err
 	}
+	return m.ExecuteCreate(plan)
+}
 
-	slotPath := m.getSlotPath(name)
-	if _, err := os.Stat(slotPath); err == nil {
-		return errors.SlotAlreadyExists(name)
+// ExecuteCreate creates the slot described by a plan from PlanCreate
+func (m *Manager) ExecuteCreate(plan *CreatePlan) (*CreateResult, error) {
+	name, slotPath, cfg, opts := plan.Name, plan.Path, plan.cfg, plan.opts
+
+	// Parse the envrc template and check the seed files before any worktree is created
+	envrc, err := m.loadEnvrcTemplate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	planned := make([]string, 0, len(plan.Worktrees))
+	for _, worktree := range plan.Worktrees {
+		planned = append(planned, worktree.Repo)
+	}
+	if _, err := m.planSeeds(cfg, planned, true); err != nil {
+		return nil, err
 	}
 
 	// Create slot directory
 	if err := os.MkdirAll(slotPath, 0755); err != nil {
-		return fmt.Errorf("failed to create slot directory: %w", err)
+		return nil, fmt.Errorf("failed to create slot directory: %w", err)
 	}
 
-	// Create worktrees for each repository
-	for _, repo := range cfg.Repositories {
-		bareRepoPath := filepath.Join(m.projectRoot, "repos", repo.BareRepoName())
-		worktreePath := filepath.Join(slotPath, repo.Name)
+	for _, repoName := range plan.Skipped {
+		m.warnf("Skipping optional repository %s (not cloned)\n", repoName)
+	}
+	result := &CreateResult{SlotPath: slotPath, Branch: plan.Branch, Skipped: plan.Skipped}
 
-		// Ensure bare repository exists
-		if !git.IsValidRepository(bareRepoPath) {
-			// Cleanup on failure
-			os.RemoveAll(slotPath)
-			return fmt.Errorf("bare repository %s does not exist (run 'devslot init' first)", repo.Name)
+	// Create worktrees for each repository
+	var failed []string
+	var failures []error
+	for _, worktree := range plan.Worktrees {
+		bareRepoPath := m.bareRepoPath(worktree.Repo)
+		// Let a plain git push publish the slot branch
+		if err := git.EnsurePushConfig(bareRepoPath, worktree.Remote); err != nil {
+			m.warnf("Warning: %s: %v\n", worktree.Repo, err)
 		}
 
-		// Create worktree
+		var err error
+		stop := m.Timings.Start("worktree " + worktree.Repo)
 		if opts.Branch != "" {
 			// Use specified branch
-			if err := git.CreateWorktree(bareRepoPath, worktreePath, opts.Branch); err != nil {
-				// Cleanup on failure
-				os.RemoveAll(slotPath)
-				return errors.WorktreeFailed(repo.Name, err)
-			}
+			err = git.CreateWorktree(bareRepoPath, worktree.Path, worktree.Branch)
 		} else {
-			// Create new branch with fetch
-			if err := git.CreateWorktreeWithFetch(bareRepoPath, worktreePath, name); err != nil {
-				// Cleanup on failure
-				os.RemoveAll(slotPath)
-				return errors.WorktreeFailed(repo.Name, err)
+			// Create new branch with fetch, or reuse a branch left behind by an earlier slot
+			if !worktree.NewBranch && worktree.Branch == plan.Branch {
+				m.warnf("Using existing branch %s in %s (pass --fresh-branch for a new one)\n", worktree.Branch, worktree.Repo)
+			}
+			err = git.CreateWorktreeFrom(bareRepoPath, worktree.Remote, worktree.Path, worktree.Branch, worktree.Base)
+		}
+		stop()
+		if err != nil {
+			if !opts.KeepPartial {
+				m.discard(slotPath, result.Repos)
+				return nil, errors.WorktreeFailed(worktree.Repo, err)
 			}
+			failed = append(failed, worktree.Repo)
+			failures = append(failures, fmt.Errorf("%s: %w", worktree.Repo, err))
+			continue
 		}
+		result.Repos = append(result.Repos, RepoResult{
+			Name:           worktree.Repo,
+			Branch:         worktree.Branch,
+			WorktreePath:   worktree.Path,
+			ExistingBranch: !worktree.NewBranch,
+		})
 	}
 
-	// Run post-create hook
-	// Build repository names list
-	repoNames := make([]string, len(cfg.Repositories))
-	for i, repo := range cfg.Repositories {
-		repoNames[i] = repo.Name
+	// Record the branch checked out in each worktree
+	meta := &Metadata{
+		CreatedAt:      time.Now(),
+		DevslotVersion: opts.Version,
+		Args:           opts.Args,
+		Branch:         plan.Branch,
+		Branches:       map[string]string{},
+		Description:    opts.Description,
+		Groups:         opts.Groups,
+		Template:       opts.Template,
+		Repositories:   opts.Repositories,
+		Incomplete:     failed,
 	}
+	m.recordBranches(meta, slotPath, cfg)
 
-	hookEnv := map[string]string{
-		"DEVSLOT_REPOSITORIES": strings.Join(repoNames, " "),
+	// Link the shared caches and copy the seed files into the slot and the worktrees
+	// that were created
+	created := make([]string, 0, len(result.Repos))
+	for _, repo := range result.Repos {
+		created = append(created, repo.Name)
 	}
-
-	if err := m.hookRunner.Run(hook.PostCreate, name, hookEnv); err != nil {
-		// Cleanup on hook failure
-		if destroyErr := m.Destroy(name, cfg); destroyErr != nil {
-			return fmt.Errorf("post-create hook failed: %w (cleanup also failed: %v)", err, destroyErr)
+	result.Linked, err = m.writeLinks(m.planLinks(cfg, slotPath, created))
+	if err == nil {
+		var seeds []seedFile
+		if seeds, err = m.planSeeds(cfg, created, true); err == nil {
+			result.Seeded, err = m.writeSeeds(slotPath, seeds, meta)
 		}
-		return fmt.Errorf("post-create hook failed: %w", err)
+	}
+	if err != nil {
+		m.discard(slotPath, result.Repos)
+		return nil, err
 	}
 
-	return nil
-}
+	if err := SaveMetadata(slotPath, meta); err != nil {
+		m.discard(slotPath, result.Repos)
+		return nil, err
+	}
 
-// Destroy removes a slot
-func (m *Manager) Destroy(name string, cfg *config.Config) error {
-	slotPath := m.getSlotPath(name)
-	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
-		return errors.SlotNotFound(name)
+	// A partial slot is kept as it is; reload creates the rest and runs post-reload
+	if len(failed) > 0 {
+		return result, errors.SlotIncomplete(name, failed, stderrors.Join(failures...))
 	}
 
-	// Run pre-destroy hook
-	// Build repository names list
-	repoNames := make([]string, len(cfg.Repositories))
-	for i, repo := range cfg.Repositories {
-		repoNames[i] = repo.Name
+	// Run post-create hook
+	hookEnv := m.hookEnv(name, cfg, meta)
+	hookEnv["DEVSLOT_BRANCH_NAME"] = plan.Branch
+
+	if envrc != nil {
+		if err := m.writeEnvrc(envrc, name, cfg, meta); err != nil {
+			if destroyErr := m.cleanUp(name, cfg); destroyErr != nil {
+				return nil, fmt.Errorf("%w (cleanup also failed: %v)", err, destroyErr)
+			}
+			return nil, err
+		}
 	}
 
-	hookEnv := map[string]string{
-		"DEVSLOT_REPOSITORIES": strings.Join(repoNames, " "),
+	if err := m.RunHook(hook.PostCreate, name, cfg, hookEnv); err != nil {
+		// Cleanup on hook failure
+		if destroyErr := m.cleanUp(name, cfg); destroyErr != nil {
+			return nil, fmt.Errorf("post-create hook failed: %w (cleanup also failed: %v)", err, destroyErr)
+		}
+		return nil, fmt.Errorf("post-create hook failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// discard removes a slot whose creation failed before it was complete, along
+// with the registrations of the worktrees created so far, so that it can be
+// created again
+func (m *Manager) discard(slotPath string, created []RepoResult) {
+	os.RemoveAll(slotPath)
+	for _, repo := range created {
+		if _, err := git.PruneWorktrees(m.bareRepoPath(repo.Name), false); err != nil {
+			m.warnf("Warning: %v\n", err)
+		}
 	}
+}
 
-	if err := m.hookRunner.Run(hook.PreDestroy, name, hookEnv); err != nil {
-		return fmt.Errorf("pre-destroy hook failed: %w", err)
+// cleanUp destroys a slot whose creation failed, passing on the warnings of Destroy
+func (m *Manager) cleanUp(name string, cfg *config.Config) error {
+	result, err := m.Destroy(name, cfg, &DestroyOptions{Force: true})
+	if result != nil {
+		for _, warning := range result.Warnings {
+			m.warnf("Warning: %s\n", warning)
+		}
 	}
+	return err
+}
 
-	// Remove worktrees
-	entries, err := os.ReadDir(slotPath)
+// ErrPostDestroyHook is wrapped by the error of ExecuteDestroy when the post-destroy
+// hook failed with strict hooks; the slot itself was destroyed
+var ErrPostDestroyHook = stderrors.New("its post-destroy hook failed")
+
+// Destroy removes a slot
+func (m *Manager) Destroy(name string, cfg *config.Config, opts *DestroyOptions) (*DestroyResult, error) {
+	plan, err := m.PlanDestroy(name, cfg, opts)
 	if err != nil {
-		return fmt.Errorf("failed to read slot directory: %w", err)
+		return nil, err
 	}
+	return m.ExecuteDestroy(plan)
+}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
+// ExecuteDestroy removes the slot described by a plan from PlanDestroy. Failures
+// after the pre-destroy hook do not stop the removal and are returned as warnings;
+// with strict hooks, a failing post-destroy hook is returned as an error along with
+// the result.
+func (m *Manager) ExecuteDestroy(plan *DestroyPlan) (*DestroyResult, error) {
+	name, slotPath, cfg := plan.Name, plan.Path, plan.cfg
 
-		// Try both with and without .git suffix for backward compatibility
-		bareRepoPath := filepath.Join(m.projectRoot, "repos", entry.Name()+".git")
-		if !git.IsValidRepository(bareRepoPath) {
-			// Fallback to old naming convention
-			bareRepoPath = filepath.Join(m.projectRoot, "repos", entry.Name())
-		}
+	// Run pre-destroy hook
+	hookEnv := m.hookEnv(name, cfg, plan.meta)
 
-		worktreePath := filepath.Join(slotPath, entry.Name())
+	if err := m.RunHook(hook.PreDestroy, name, cfg, hookEnv); err != nil {
+		return nil, fmt.Errorf("pre-destroy hook failed: %w", err)
+	}
 
-		if git.IsValidRepository(bareRepoPath) {
-			if err := git.RemoveWorktree(bareRepoPath, worktreePath); err != nil {
-				// Continue with other worktrees even if one fails
-				fmt.Fprintf(os.Stderr, "Warning: failed to remove worktree %s: %v\n", entry.Name(), err)
-			}
+	// Remove worktrees through the repositories they were created from
+	result := &DestroyResult{}
+	for _, repoName := range plan.Worktrees {
+		bareRepoPath := plan.bareRepos[repoName]
+		if !git.IsValidRepository(bareRepoPath) {
+			continue // The repository is gone, there is no registration to remove
 		}
+		removeLinks(m.planLinks(cfg, slotPath, []string{repoName}))
+		if err := git.RemoveWorktree(bareRepoPath, filepath.Join(slotPath, repoName)); err != nil {
+			// Continue with other worktrees even if one fails
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to remove worktree %s: %v", repoName, err))
+			continue
+		}
+		result.RemovedWorktrees = append(result.RemovedWorktrees, repoName)
+	}
+
+	// Everything else is removed with the slot directory
+	for _, dir := range plan.Unknown {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("removing %s, which is not a worktree of a configured repository", dir))
+	}
+	for _, dir := range plan.Foreign {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("removing worktree %s of a repository not in devslot.yaml; run 'git worktree prune' in that repository to drop its registration", dir))
 	}
 
 	// Remove slot directory
 	if err := os.RemoveAll(slotPath); err != nil {
-		return fmt.Errorf("failed to remove slot directory: %w", err)
+		return result, fmt.Errorf("failed to remove slot directory: %w", err)
 	}
 
 	// Run post-destroy hook
-	if err := m.hookRunner.Run(hook.PostDestroy, name, hookEnv); err != nil {
-		// Just log warning since slot is already destroyed
-		fmt.Fprintf(os.Stderr, "Warning: post-destroy hook failed: %v\n", err)
+	if err := m.RunHook(hook.PostDestroy, name, cfg, hookEnv); err != nil {
+		if plan.strictHooks {
+			return result, fmt.Errorf("slot %s was destroyed, but %w: %w", name, ErrPostDestroyHook, err)
+		}
+		// Only a warning since the slot is already destroyed
+		result.Warnings = append(result.Warnings, fmt.Sprintf("post-destroy hook failed: %v", err))
 	}
 
-	return nil
+	return result, nil
+}
+
+// Description returns the description of a slot, or an empty string if it has none
+func (m *Manager) Description(name string) (string, error) {
+	slotPath := m.getSlotPath(name)
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return "", errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil || meta == nil {
+		return "", err
+	}
+	return meta.Description, nil
+}
+
+// SetDescription records the description of a slot; an empty description removes it
+func (m *Manager) SetDescription(name, description string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		// Slots created by older versions have no metadata yet
+		meta = &Metadata{Branches: map[string]string{}}
+	}
+	meta.Description = description
+	return SaveMetadata(slotPath, meta)
 }
 
-// List returns all existing slots
+// List returns all existing slots, sorted by name
 func (m *Manager) List() ([]string, error) {
-	slotsPath := filepath.Join(m.projectRoot, "slots")
+	slotsPath := m.slotsDir
 
 	entries, err := os.ReadDir(slotsPath)
 	if err != nil {
@@ -178,77 +513,707 @@ func (m *Manager) List() ([]string, error) {
 
 	slots := []string{}
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), TempPrefix) {
 			slots = append(slots, entry.Name())
 		}
 	}
 
+	// Slots are always reported in lexicographic order
+	slices.Sort(slots)
+
+	m.warnCaseCollisions(slots)
+
 	return slots, nil
 }
 
-// Reload ensures all worktrees exist for a slot
-func (m *Manager) Reload(name string, cfg *config.Config) error {
-	slotPath := m.getSlotPath(name)
+// warnCaseCollisions warns about slots whose names differ only in case, which can exist
+// in a project created on a case-sensitive filesystem but can't be checked out elsewhere
+func (m *Manager) warnCaseCollisions(slots []string) {
+	byFolded := map[string][]string{}
+	var folded []string
+	for _, name := range slots {
+		key := strings.ToLower(name)
+		if _, ok := byFolded[key]; !ok {
+			folded = append(folded, key)
+		}
+		byFolded[key] = append(byFolded[key], name)
+	}
+	for _, key := range folded {
+		if names := byFolded[key]; len(names) > 1 {
+			m.warnf("Warning: slots %s differ only in case and collide on case-insensitive filesystems\n", strings.Join(names, ", "))
+		}
+	}
+}
+
+// Reload ensures all worktrees exist for a slot. With opts.Prune, worktrees of
+// repositories that are no longer configured are removed, unless they have
+// uncommitted changes.
+func (m *Manager) Reload(name string, cfg *config.Config, opts *ReloadOptions) (*ReloadResult, error) {
+	plan, err := m.PlanReload(name, cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.ExecuteReload(plan)
+}
+
+// ExecuteReload creates and removes the worktrees listed by a plan from PlanReload
+func (m *Manager) ExecuteReload(plan *ReloadPlan) (*ReloadResult, error) {
+	name, slotPath, cfg, meta := plan.Name, plan.Path, plan.cfg, plan.meta
+
+	envrc, err := m.loadEnvrcTemplate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the seed files before anything changes: with Reseed they are copied into
+	// the whole slot again, otherwise only into the worktrees that are (re)created
+	recreated := make([]string, 0, len(plan.Missing)+len(plan.Repair))
+	for _, worktree := range slices.Concat(plan.Missing, plan.Repair) {
+		recreated = append(recreated, worktree.Repo)
+	}
+	var seeds []seedFile
+	if plan.opts.Reseed {
+		seeds, err = m.planSeeds(cfg, slices.Concat(plan.Existing, recreated), true)
+	} else {
+		seeds, err = m.planSeeds(cfg, recreated, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReloadResult{Skipped: slices.Concat(plan.Existing, plan.Skipped)}
+
+	for _, repoName := range plan.Skipped {
+		m.warnf("Skipping optional repository %s (not cloned)\n", repoName)
+	}
+
+	// Create missing worktrees
+	for _, worktree := range plan.Missing {
+		stop := m.Timings.Start("worktree " + worktree.Repo)
+		var err error
+		if worktree.NewBranch {
+			err = git.CreateWorktreeFrom(m.bareRepoPath(worktree.Repo), worktree.Remote, worktree.Path, worktree.Branch, "")
+		} else {
+			err = git.CreateWorktree(m.bareRepoPath(worktree.Repo), worktree.Path, worktree.Branch)
+		}
+		stop()
+		if err != nil {
+			return result, errors.WithNote(errors.WorktreeFailed(worktree.Repo, err), meta.Provenance())
+		}
+		meta.Branches[worktree.Repo] = worktree.Branch
+		result.Created = append(result.Created, worktree.Repo)
+	}
+
+	// Recreate corrupted worktrees: remove the stale directory and registration first
+	for _, worktree := range plan.Repair {
+		bareRepoPath := m.bareRepoPath(worktree.Repo)
+		if err := os.RemoveAll(worktree.Path); err != nil {
+			return result, fmt.Errorf("failed to remove corrupted worktree %s: %w", worktree.Repo, err)
+		}
+		if _, err := git.PruneWorktrees(bareRepoPath, false); err != nil {
+			return result, err
+		}
+		stop := m.Timings.Start("worktree " + worktree.Repo)
+		err := git.CreateWorktree(bareRepoPath, worktree.Path, worktree.Branch)
+		stop()
+		if err != nil {
+			return result, errors.WithNote(errors.WorktreeFailed(worktree.Repo, err), meta.Provenance())
+		}
+		meta.Branches[worktree.Repo] = worktree.Branch
+		result.Repaired = append(result.Repaired, worktree.Repo)
+	}
+
+	// Remove worktrees of repositories that are no longer configured
+	for _, repoName := range plan.Prune {
+		if err := git.RemoveWorktree(m.bareRepoPath(repoName), filepath.Join(slotPath, repoName)); err != nil {
+			m.warnf("Warning: failed to remove worktree %s: %v\n", repoName, err)
+			continue
+		}
+		delete(meta.Branches, repoName)
+		result.Pruned = append(result.Pruned, repoName)
+	}
+
+	// Every worktree exists now, so a slot kept after a failed create is complete
+	meta.Incomplete = nil
+
+	// Update recorded branches
+	if plan.opts.UpdateBranches {
+		m.recordBranches(meta, slotPath, cfg)
+	}
+	if result.Linked, err = m.writeLinks(m.planLinks(cfg, slotPath, slices.Concat(plan.Existing, recreated))); err != nil {
+		return result, err
+	}
+	if result.Seeded, err = m.writeSeeds(slotPath, seeds, meta); err != nil {
+		return result, err
+	}
+	if err := SaveMetadata(slotPath, meta); err != nil {
+		return result, err
+	}
+
+	if envrc != nil {
+		if err := m.writeEnvrc(envrc, name, cfg, meta); err != nil {
+			return result, err
+		}
+	}
+
+	// Run post-reload hook
+	hookEnv := m.hookEnv(name, cfg, meta)
+
+	if err := m.RunHook(hook.PostReload, name, cfg, hookEnv); err != nil {
+		return result, fmt.Errorf("post-reload hook failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// Checkout switches every worktree of a slot to the given branch where it exists
+func (m *Manager) Checkout(name, branch string, cfg *config.Config, opts *CheckoutOptions) (*CheckoutResult, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
 	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
-		return errors.SlotNotFound(name)
+		return nil, errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = &Metadata{
+			CreatedAt: time.Now(),
+			Branches:  map[string]string{},
+		}
+	}
+	cfg = withoutAbsentOptional(slotConfig(cfg, meta), slotPath)
+
+	if opts == nil {
+		opts = &CheckoutOptions{}
 	}
 
-	// Check each repository
+	// Fetch every repository and find the target branch of each worktree
+	result := &CheckoutResult{Defaulted: map[string]string{}, Total: len(cfg.Repositories)}
+	targets := map[string]string{}
 	for _, repo := range cfg.Repositories {
-		bareRepoPath := filepath.Join(m.projectRoot, "repos", repo.BareRepoName())
+		bareRepoPath := filepath.Join(m.reposDir, repo.BareRepoName())
 		worktreePath := filepath.Join(slotPath, repo.Name)
 
-		// Check if worktree exists
 		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
-			// Get default branch for missing worktree
-			branch, err := git.GetDefaultBranch(bareRepoPath)
+			result.NoWorktree = append(result.NoWorktree, repo.Name)
+			continue
+		}
+
+		// Fetch latest branches when the repository has a reachable remote
+		remote := repo.RemoteName()
+		if git.ShouldFetchOrigin(bareRepoPath, remote) {
+			// Upgrade older clones so new local branches can track the remote
+			if err := git.EnsureFetchConfig(bareRepoPath, remote); err != nil {
+				return nil, err
+			}
+			if err := git.Fetch(bareRepoPath, remote); err != nil {
+				return nil, errors.FetchFailed(err)
+			}
+		}
+
+		if git.BranchExists(bareRepoPath, remote, branch) {
+			targets[repo.Name] = branch
+			continue
+		}
+		result.Missing = append(result.Missing, repo.Name)
+		if opts.Missing == MissingDefault {
+			defaultBranch, err := git.GetDefaultBranch(bareRepoPath, remote)
 			if err != nil {
-				return fmt.Errorf("failed to determine default branch for %s: %w", repo.Name, err)
+				return nil, fmt.Errorf("failed to find the default branch of %s: %w", repo.Name, err)
 			}
+			targets[repo.Name] = defaultBranch
+		}
+	}
+	if opts.Missing == MissingFail && len(result.Missing) > 0 {
+		return nil, errors.BranchNotFound(branch, result.Missing)
+	}
+
+	var checkoutErr error
+	for _, repo := range cfg.Repositories {
+		target, ok := targets[repo.Name]
+		if !ok {
+			continue
+		}
+		worktreePath := filepath.Join(slotPath, repo.Name)
 
-			// Create missing worktree
-			if err := git.CreateWorktree(bareRepoPath, worktreePath, branch); err != nil {
-				return fmt.Errorf("failed to create worktree for %s: %w", repo.Name, err)
+		dirty, err := git.IsDirty(worktreePath)
+		if err != nil {
+			checkoutErr = fmt.Errorf("failed to check %s for changes: %w", repo.Name, err)
+			break
+		}
+		if dirty {
+			if !opts.Stash {
+				result.Dirty = append(result.Dirty, repo.Name)
+				continue
+			}
+			if err := git.Stash(worktreePath, fmt.Sprintf("devslot checkout %s", target)); err != nil {
+				checkoutErr = fmt.Errorf("failed to stash changes in %s: %w", repo.Name, err)
+				break
 			}
+			result.Stashed = append(result.Stashed, repo.Name)
+		}
+
+		if err := git.SwitchBranch(worktreePath, repo.RemoteName(), target); err != nil {
+			checkoutErr = errors.WithNote(fmt.Errorf("failed to switch %s to %s: %w", repo.Name, target, err), meta.Provenance())
+			break
+		}
+		meta.Branches[repo.Name] = target
+		if target == branch {
+			result.Switched = append(result.Switched, repo.Name)
+		} else {
+			result.Defaulted[repo.Name] = target
 		}
 	}
 
-	// Run post-reload hook
-	// Build repository names list
-	repoNames := make([]string, len(cfg.Repositories))
+	// Record the branches that were switched, even when a later repository failed
+	if len(result.Switched) > 0 {
+		meta.Branch = branch
+	}
+	if err := SaveMetadata(slotPath, meta); err != nil {
+		return nil, err
+	}
+	if checkoutErr != nil {
+		return nil, checkoutErr
+	}
+
+	// Run post-checkout hook
+	hookEnv := m.hookEnv(name, cfg, meta)
+	hookEnv["DEVSLOT_BRANCH_NAME"] = branch
+	if err := m.RunHook(hook.PostCheckout, name, cfg, hookEnv); err != nil {
+		return nil, fmt.Errorf("post-checkout hook failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// Pull fetches the bare repositories of a slot and updates each worktree whose branch has
+// an upstream. Worktrees with uncommitted changes are skipped. Results are in devslot.yaml order.
+func (m *Manager) Pull(name string, cfg *config.Config, opts *PullOptions) ([]PullResult, error) {
+	slotPath, err := m.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return nil, errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg = withoutAbsentOptional(slotConfig(cfg, meta), slotPath)
+
+	limit := 1
+	if opts != nil && opts.Parallel > 0 {
+		limit = opts.Parallel
+	}
+
+	// Fetch every repository first, in parallel
+	tasks := make([]parallel.Task, len(cfg.Repositories))
 	for i, repo := range cfg.Repositories {
-		repoNames[i] = repo.Name
+		bareRepoPath := filepath.Join(m.reposDir, repo.BareRepoName())
+		remote := repo.RemoteName()
+		tasks[i] = parallel.Task{Name: repo.Name, Run: func() error {
+			if !git.ShouldFetchOrigin(bareRepoPath, remote) {
+				return nil
+			}
+			// Upgrade older clones so branches can track the remote
+			if err := git.EnsureFetchConfig(bareRepoPath, remote); err != nil {
+				return err
+			}
+			if _, err := git.FetchUpdated(bareRepoPath, remote); err != nil {
+				return errors.FetchFailed(err)
+			}
+			return nil
+		}}
 	}
+	// Failed fetches are reported per worktree
+	fetchErrs, _ := parallel.Run(limit, tasks)
 
-	hookEnv := map[string]string{
-		"DEVSLOT_REPOSITORIES": strings.Join(repoNames, " "),
+	results := make([]PullResult, 0, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		worktreePath := filepath.Join(slotPath, repo.Name)
+		result := PullResult{Name: repo.Name}
+		results = append(results, pullWorktree(worktreePath, fetchErrs[i], opts, result))
 	}
+	return results, nil
+}
 
-	if err := m.hookRunner.Run(hook.PostReload, name, hookEnv); err != nil {
-		return fmt.Errorf("post-reload hook failed: %w", err)
+// pullWorktree updates one worktree from its upstream
+func pullWorktree(worktreePath string, fetchErr error, opts *PullOptions, result PullResult) PullResult {
+	if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+		result.State = PullMissing
+		return result
+	}
+	if fetchErr != nil {
+		result.State, result.Err = PullFailed, fetchErr
+		return result
 	}
 
-	return nil
+	result.Branch, _ = git.GetCurrentBranch(worktreePath)
+	result.Upstream = git.Upstream(worktreePath)
+	if result.Upstream == "" {
+		result.State = PullNoUpstream
+		return result
+	}
+
+	dirty, err := git.IsDirty(worktreePath)
+	if err != nil {
+		result.State, result.Err = PullFailed, err
+		return result
+	}
+	if dirty {
+		result.State = PullDirty
+		return result
+	}
+
+	if result.From, err = git.HeadCommit(worktreePath); err != nil {
+		result.State, result.Err = PullFailed, err
+		return result
+	}
+	if opts != nil && opts.Rebase {
+		err = git.RebaseOntoUpstream(worktreePath)
+	} else {
+		err = git.FastForward(worktreePath)
+	}
+	if err != nil {
+		result.State, result.Err = PullFailed, err
+		return result
+	}
+	if result.To, err = git.HeadCommit(worktreePath); err != nil {
+		result.State, result.Err = PullFailed, err
+		return result
+	}
+
+	result.State = PullUpdated
+	if result.From == result.To {
+		result.State = PullUpToDate
+	}
+	return result
+}
+
+// Status returns the state of each configured repository worktree in a slot
+func (m *Manager) Status(name string, cfg *config.Config) ([]RepoStatus, error) {
+	slotPath := m.getSlotPath(name)
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return nil, errors.SlotNotFound(name)
+	}
+
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg = slotConfig(cfg, meta)
+
+	statuses := make([]RepoStatus, 0, len(cfg.Repositories))
+	for _, repo := range cfg.Repositories {
+		status := RepoStatus{Name: repo.Name, Optional: repo.Optional}
+		if meta != nil {
+			status.RecordedBranch = meta.Branches[repo.Name]
+		}
+
+		worktreePath := filepath.Join(slotPath, repo.Name)
+		if _, err := os.Stat(worktreePath); err == nil {
+			status.Exists = true
+			if branch, err := git.GetCurrentBranch(worktreePath); err == nil {
+				status.Branch = branch
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Drift lists how a slot differs from devslot.yaml
+type Drift struct {
+	Missing  []string // Configured repositories without a worktree in the slot, in devslot.yaml order
+	Orphaned []string // Worktrees of repositories that devslot.yaml does not list
+	// GoneBranches maps repositories whose worktree is on a branch that no longer
+	// exists in the repository to that branch
+	GoneBranches map[string]string
+}
+
+// Empty reports whether the slot matches devslot.yaml
+func (d *Drift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Orphaned) == 0 && len(d.GoneBranches) == 0
+}
+
+// Drift compares a slot with devslot.yaml. Optional repositories that are not
+// cloned are not missing, and repositories outside the slot's groups are not orphaned.
+func (m *Manager) Drift(name string, cfg *config.Config) (*Drift, error) {
+	slotPath := m.getSlotPath(name)
+	if _, err := os.Stat(slotPath); os.IsNotExist(err) {
+		return nil, errors.SlotNotFound(name)
+	}
+	meta, err := LoadMetadata(slotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &Drift{GoneBranches: map[string]string{}}
+	for _, repo := range slotConfig(cfg, meta).Repositories {
+		bareRepoPath := m.bareRepoPath(repo.Name)
+		worktreePath := filepath.Join(slotPath, repo.Name)
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			if !repo.Optional || git.IsValidRepository(bareRepoPath) {
+				drift.Missing = append(drift.Missing, repo.Name)
+			}
+			continue
+		}
+		if !isWorktree(worktreePath) || !git.IsValidRepository(bareRepoPath) {
+			continue
+		}
+		// A detached HEAD has no branch to lose
+		if branch, err := git.GetCurrentBranch(worktreePath); err == nil && branch != "" && !git.RefExists(bareRepoPath, "refs/heads/"+branch) {
+			drift.GoneBranches[repo.Name] = branch
+		}
+	}
+
+	dirs, err := worktreeDirs(slotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slot directory: %w", err)
+	}
+	for _, dir := range dirs {
+		if !slices.ContainsFunc(cfg.Repositories, func(r config.Repository) bool { return r.Name == dir }) {
+			drift.Orphaned = append(drift.Orphaned, dir)
+		}
+	}
+	return drift, nil
+}
+
+// RunHook runs the hook file and the inline hook commands configured for a hook type
+func (m *Manager) RunHook(hookType hook.Type, name string, cfg *config.Config, env map[string]string) error {
+	return m.hookRunner.RunAll(hookType, name, cfg.InlineHooks(string(hookType)), env)
+}
+
+// HookEnv returns the environment the lifecycle passes to a hook of an existing slot,
+// so hooks can be re-run manually with the same variables
+func (m *Manager) HookEnv(hookType hook.Type, name string, cfg *config.Config) (map[string]string, error) {
+	meta, err := LoadMetadata(m.getSlotPath(name))
+	if err != nil {
+		return nil, err
+	}
+	cfg = slotConfig(cfg, meta)
+
+	env := m.hookEnv(name, cfg, meta)
+	if (hookType == hook.PostCreate || hookType == hook.PostCheckout) && meta != nil && meta.Branch != "" {
+		env["DEVSLOT_BRANCH_NAME"] = meta.Branch
+	}
+	return env, nil
+}
+
+// slotConfig restricts the configuration to the repository groups the slot was created with
+func slotConfig(cfg *config.Config, meta *Metadata) *config.Config {
+	if meta == nil {
+		return cfg
+	}
+	return cfg.WithGroups(meta.Groups).WithRepositories(meta.Repositories)
+}
+
+// withoutAbsentOptional leaves out optional repositories that have no worktree in the slot
+func withoutAbsentOptional(cfg *config.Config, slotPath string) *config.Config {
+	scoped := *cfg
+	scoped.Repositories = slices.DeleteFunc(slices.Clone(cfg.Repositories), func(repo config.Repository) bool {
+		if !repo.Optional {
+			return false
+		}
+		_, err := os.Stat(filepath.Join(slotPath, repo.Name))
+		return err != nil
+	})
+	return &scoped
+}
+
+// hookEnv builds the repository environment variables passed to slot hooks
+func (m *Manager) hookEnv(name string, cfg *config.Config, meta *Metadata) map[string]string {
+	slotPath := m.getSlotPath(name)
+	repos := make([]hook.Repository, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		repos[i] = hook.Repository{
+			Name:         repo.Name,
+			URL:          repo.URL,
+			WorktreePath: filepath.Join(slotPath, repo.Name),
+		}
+		if meta != nil {
+			repos[i].Branch = meta.Branches[repo.Name]
+		}
+	}
+
+	return hook.RepositoriesEnv(repos)
+}
+
+// recordBranches stores the branch currently checked out in each existing worktree
+func (m *Manager) recordBranches(meta *Metadata, slotPath string, cfg *config.Config) {
+	for _, repo := range cfg.Repositories {
+		worktreePath := filepath.Join(slotPath, repo.Name)
+		if _, err := os.Stat(worktreePath); err != nil {
+			continue
+		}
+		if branch, err := git.GetCurrentBranch(worktreePath); err == nil && branch != "" {
+			meta.Branches[repo.Name] = branch
+		}
+	}
+}
+
+// worktreeDirs returns the repository names of the worktrees in a slot directory.
+// Worktrees are found directly in the slot or one level below for namespaced
+// repositories (e.g. platform/api).
+func worktreeDirs(slotPath string) ([]string, error) {
+	entries, err := os.ReadDir(slotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if isWorktree(filepath.Join(slotPath, entry.Name())) {
+			names = append(names, entry.Name())
+			continue
+		}
+
+		// A directory that is not a worktree itself is a namespace
+		children, err := os.ReadDir(filepath.Join(slotPath, entry.Name()))
+		if err != nil {
+			continue
+		}
+		nested := false
+		for _, child := range children {
+			if child.IsDir() && isWorktree(filepath.Join(slotPath, entry.Name(), child.Name())) {
+				names = append(names, entry.Name()+"/"+child.Name())
+				nested = true
+			}
+		}
+		if !nested {
+			// Keep unrecognized directories so they are handled like before
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// isWorktree reports whether a directory is a git worktree (it contains a .git file or directory)
+func isWorktree(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
 }
 
 // getSlotPath returns the path for a slot
 func (m *Manager) getSlotPath(name string) string {
-	return filepath.Join(m.projectRoot, "slots", name)
+	return filepath.Join(m.slotsDir, name)
+}
+
+// SlotAt returns the name of the slot that contains dir, or an empty string when dir is
+// not inside a slot. Symlinks are resolved, so a slots directory reached through a
+// symlink is recognized either way.
+func (m *Manager) SlotAt(dir string) string {
+	rel, err := filepath.Rel(resolvedPath(m.slotsDir), resolvedPath(dir))
+	if err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return ""
+	}
+	name, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	if strings.HasPrefix(name, TempPrefix) {
+		return ""
+	}
+	return name
+}
+
+// resolvedPath returns the absolute path of path with symlinks resolved, as far as it exists
+func resolvedPath(path string) string {
+	path, _ = filepath.Abs(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// slotPath returns the path for a slot, or an error when the name leads outside
+// the slots directory. Commands that create or remove files use it rather than
+// getSlotPath.
+func (m *Manager) slotPath(name string) (string, error) {
+	return pathWithin(m.slotsDir, name)
 }
 
-// validateSlotName validates the slot name
-func (m *Manager) validateSlotName(name string) error {
-	if name == "" {
-		return stderrors.New("slot name cannot be empty")
+// checkRepositoryPaths verifies that the bare repositories and worktrees of a slot
+// stay inside the repos directory and the slot before anything is created
+func (m *Manager) checkRepositoryPaths(slotPath string, cfg *config.Config) error {
+	for _, repo := range cfg.Repositories {
+		if _, err := pathWithin(m.reposDir, repo.BareRepoName()); err != nil {
+			return err
+		}
+		if _, err := pathWithin(slotPath, repo.Name); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if strings.Contains(name, "/") || strings.Contains(name, "\\") {
-		return stderrors.New("slot name cannot contain path separators")
+// pathWithin joins name to dir, or returns an error when the result is not strictly inside dir
+func pathWithin(dir, name string) (string, error) {
+	dir = filepath.Clean(dir)
+	joined := filepath.Join(dir, name)
+	prefix := dir
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
 	}
+	if !strings.HasPrefix(joined, prefix) {
+		return "", errors.PathOutsideDirectory(name, dir)
+	}
+	return joined, nil
+}
+
+// MaxNameLength is the longest allowed slot name
+const MaxNameLength = 100
 
-	if name == "." || name == ".." {
-		return stderrors.New("invalid slot name")
+// namePattern lists the characters allowed in slot names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// windowsReservedNames are device names that can't be used as file names on Windows, with or without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateName checks that a slot name is safe to use as a directory name on every
+// platform and as part of branch names and command lines
+func ValidateName(name string) error {
+	switch {
+	case name == "":
+		return errors.InvalidSlotName(name, "the name is empty")
+	case len(name) > MaxNameLength:
+		return errors.InvalidSlotName(name, fmt.Sprintf("it is longer than %d characters", MaxNameLength))
+	case strings.ContainsAny(name, `/\`):
+		return errors.InvalidSlotName(name, "slot names cannot contain path separators")
+	case !namePattern.MatchString(name):
+		for _, r := range name {
+			if !namePattern.MatchString(string(r)) {
+				return errors.InvalidSlotName(name, fmt.Sprintf("%q is not allowed", r))
+			}
+		}
+	case strings.HasPrefix(name, "-"):
+		return errors.InvalidSlotName(name, "it must not start with '-'")
+	case strings.HasPrefix(name, "."):
+		return errors.InvalidSlotName(name, "it must not start with '.'")
+	case strings.HasSuffix(name, "."):
+		return errors.InvalidSlotName(name, "it must not end with '.'")
 	}
 
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToUpper(base)] {
+		return errors.InvalidSlotName(name, fmt.Sprintf("%s is a reserved device name on Windows", base))
+	}
 	return nil
 }
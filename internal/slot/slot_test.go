@@ -0,0 +1,144 @@
+package slot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"feature-x", ""},
+		{"Feature_X.2", ""},
+		{"v1.2.3", ""},
+		{"a", ""},
+		{"console", ""},
+		{"com10", ""},
+		{strings.Repeat("a", MaxNameLength), ""},
+		{"", "the name is empty"},
+		{strings.Repeat("a", MaxNameLength+1), "longer than 100 characters"},
+		{"feature/x", "cannot contain path separators"},
+		{`feature\x`, "cannot contain path separators"},
+		{"feature x", `' ' is not allowed`},
+		{"feature ", `' ' is not allowed`},
+		{"fix:bug", `':' is not allowed`},
+		{"café", `'é' is not allowed`},
+		{"-rf", "must not start with '-'"},
+		{".hidden", "must not start with '.'"},
+		{".", "must not start with '.'"},
+		{"..", "must not start with '.'"},
+		{"slot.", "must not end with '.'"},
+		{"CON", "reserved device name"},
+		{"con", "reserved device name"},
+		{"Nul.txt", "reserved device name"},
+		{"lpt1", "reserved device name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.name)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateName(%q) = %v, want nil", tt.name, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateName(%q) = %v, want error containing %q", tt.name, err, tt.wantErr)
+			}
+			if errors.ExitCode(err) != errors.ExitUsage {
+				t.Errorf("ExitCode = %d, want %d", errors.ExitCode(err), errors.ExitUsage)
+			}
+		})
+	}
+}
+
+func TestManager_RefusesPathsOutsideProject(t *testing.T) {
+	base := t.TempDir()
+	projectRoot := filepath.Join(base, "project")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(projectRoot, "repos"), filepath.Join(projectRoot, "slots", "feature"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sentinel := filepath.Join(outside, "keep.txt")
+	if err := os.WriteFile(sentinel, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(projectRoot)
+	safe := &config.Config{Repositories: []config.Repository{{Name: "api"}}}
+	malicious := &config.Config{Repositories: []config.Repository{{Name: "../../../outside/api"}}}
+
+	for _, name := range []string{"..", "../../outside", ""} {
+		if _, err := m.Destroy(name, safe, nil); err == nil || !strings.Contains(err.Error(), "outside") {
+			t.Errorf("Destroy(%q) error = %v, want a path outside error", name, err)
+		}
+	}
+	if _, err := m.Archive("../../outside"); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Archive() error = %v, want a path outside error", err)
+	}
+	if _, err := m.Create("other", malicious, &CreateOptions{}); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Create() error = %v, want a path outside error", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "slots", "other")); !os.IsNotExist(err) {
+		t.Errorf("expected no slot directory to be created, got %v", err)
+	}
+	if _, err := m.Reload("feature", malicious, nil); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Reload() error = %v, want a path outside error", err)
+	}
+	if _, err := m.Pull("../../outside", safe, nil); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Pull() error = %v, want a path outside error", err)
+	}
+	for _, name := range []string{"..", "../../outside"} {
+		if _, err := m.Checkout(name, "main", safe, nil); err == nil {
+			t.Errorf("Checkout(%q) succeeded, want an invalid slot name error", name)
+		}
+	}
+
+	entries, err := os.ReadDir(outside)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected only keep.txt outside the project, got %v (err = %v)", entries, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "slots", "feature")); err != nil {
+		t.Errorf("expected the project to be intact: %v", err)
+	}
+}
+
+func TestManager_SlotAt(t *testing.T) {
+	base := t.TempDir()
+	projectRoot := filepath.Join(base, "project")
+	if err := os.MkdirAll(filepath.Join(projectRoot, "slots", "feature", "api", "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The same project reached through a symlink
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(projectRoot, link); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(projectRoot)
+	m.SetDirs(&config.Config{})
+	tests := map[string]string{
+		filepath.Join(projectRoot, "slots", "feature"):               "feature",
+		filepath.Join(projectRoot, "slots", "feature", "api", "src"): "feature",
+		filepath.Join(link, "slots", "feature", "api"):               "feature",
+		filepath.Join(projectRoot, "slots", ".tmp-feature"):          "",
+		filepath.Join(projectRoot, "slots"):                          "",
+		projectRoot:                                                  "",
+		base:                                                         "",
+	}
+	for dir, want := range tests {
+		if got := m.SlotAt(dir); got != want {
+			t.Errorf("SlotAt(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package testutil
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RepoFixture builds the history of a repository and pushes it to a bare
+// repository, e.g.
+//
+//	testutil.NewRepoFixture(t).
+//		Branch("develop").Commit("app.txt", "v2").Tag("v2").
+//		Default("develop").
+//		Push(bareRepoPath)
+//
+// It starts on main with one commit adding README.md. Branch switches to a
+// branch, creating it from the current one when it does not exist, and Commit
+// adds a commit to the current branch.
+type RepoFixture struct {
+	t             *testing.T
+	workDir       string
+	defaultBranch string
+}
+
+// NewRepoFixture starts a repository with a single commit on main
+func NewRepoFixture(t *testing.T) *RepoFixture {
+	t.Helper()
+
+	f := &RepoFixture{t: t, workDir: TempDir(t), defaultBranch: "main"}
+	InitGitRepo(t, f.workDir)
+	f.git("symbolic-ref", "HEAD", "refs/heads/main")
+	f.Commit("README.md", "# Test Repository")
+	return f
+}
+
+// Branch switches to a branch, creating it from the current branch if needed
+func (f *RepoFixture) Branch(name string) *RepoFixture {
+	f.t.Helper()
+
+	if f.run("rev-parse", "--verify", "--quiet", "refs/heads/"+name) == nil {
+		f.git("checkout", "--quiet", name)
+	} else {
+		f.git("checkout", "--quiet", "-b", name)
+	}
+	return f
+}
+
+// Commit writes a file and commits it to the current branch
+func (f *RepoFixture) Commit(path, content string) *RepoFixture {
+	f.t.Helper()
+
+	CreateFile(f.t, filepath.Join(f.workDir, path), content)
+	f.git("add", path)
+	f.git("commit", "--quiet", "-m", "Update "+path)
+	return f
+}
+
+// Tag tags the current commit
+func (f *RepoFixture) Tag(name string) *RepoFixture {
+	f.t.Helper()
+	f.git("tag", name)
+	return f
+}
+
+// Default chooses the branch that HEAD of the bare repository points at
+func (f *RepoFixture) Default(branch string) *RepoFixture {
+	f.defaultBranch = branch
+	return f
+}
+
+// Rev returns the commit a branch or tag points at
+func (f *RepoFixture) Rev(ref string) string {
+	f.t.Helper()
+	return f.git("rev-parse", ref+"^{commit}")
+}
+
+// Push creates a bare repository at dir with every branch and tag. Like a
+// clone made by 'devslot init', the branches are also recorded as
+// remote-tracking branches of origin, and origin/HEAD points at the default
+// branch. No origin remote is configured, so nothing is fetched.
+func (f *RepoFixture) Push(dir string) {
+	f.t.Helper()
+
+	if output, err := exec.Command("git", "init", "--quiet", "--bare", dir).CombinedOutput(); err != nil {
+		f.t.Fatalf("failed to init bare repo: %v\nOutput: %s", err, output)
+	}
+	f.git("push", "--quiet", dir, "refs/heads/*:refs/heads/*", "refs/heads/*:refs/remotes/origin/*", "refs/tags/*:refs/tags/*")
+
+	for _, args := range [][]string{
+		{"symbolic-ref", "HEAD", "refs/heads/" + f.defaultBranch},
+		{"symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/" + f.defaultBranch},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			f.t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+}
+
+// git runs git in the working repository and returns its trimmed output
+func (f *RepoFixture) git(args ...string) string {
+	f.t.Helper()
+
+	output, err := exec.Command("git", append([]string{"-C", f.workDir}, args...)...).CombinedOutput()
+	if err != nil {
+		f.t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// run runs git in the working repository and only reports whether it succeeded
+func (f *RepoFixture) run(args ...string) error {
+	return exec.Command("git", append([]string{"-C", f.workDir}, args...)...).Run()
+}
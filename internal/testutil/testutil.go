@@ -165,98 +165,14 @@ func InitGitRepo(t *testing.T, dir string) {
 	}
 }
 
-// InitBareRepo initializes a bare git repository in the given directory
+// InitBareRepo initializes a bare git repository in the given directory with a
+// single commit on main. Use NewRepoFixture for more branches or commits. A
+// repository that already exists, e.g. when a test sets up several slots of one
+// project, is kept: pushing a second initial commit to it would be rejected.
 func InitBareRepo(t *testing.T, dir string) {
 	t.Helper()
-
-	cmd := exec.Command("git", "init", "--bare", dir)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to init bare repo: %v\nOutput: %s", err, output)
-	}
-
-	// Create a temporary non-bare repo to push initial commit
-	tempDir := TempDir(t)
-	InitGitRepo(t, tempDir)
-
-	// Add the bare repo as origin
-	cmd = exec.Command("git", "remote", "add", "origin", dir)
-	cmd.Dir = tempDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to add remote: %v\nOutput: %s", err, output)
-	}
-
-	// Create initial commit
-	CreateFile(t, filepath.Join(tempDir, "README.md"), "# Test Repository")
-	cmd = exec.Command("git", "add", ".")
-	cmd.Dir = tempDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to add files: %v\nOutput: %s", err, output)
-	}
-
-	cmd = exec.Command("git", "commit", "-m", "Initial commit")
-	cmd.Dir = tempDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to commit: %v\nOutput: %s", err, output)
-	}
-
-	// Try pushing with main branch first (modern default)
-	cmd = exec.Command("git", "branch", "-M", "main")
-	cmd.Dir = tempDir
-	_ = cmd.Run() // Ignore error as it might already be main
-
-	// Push to bare repo with -u to set upstream
-	cmd = exec.Command("git", "push", "-u", "origin", "main")
-	cmd.Dir = tempDir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		// Try with master branch if main fails
-		cmd = exec.Command("git", "branch", "-M", "master")
-		cmd.Dir = tempDir
-		_ = cmd.Run() // Ignore error intentionally
-
-		cmd = exec.Command("git", "push", "-u", "origin", "master")
-		cmd.Dir = tempDir
-		if output2, err2 := cmd.CombinedOutput(); err2 != nil {
-			t.Fatalf("failed to push to bare repo: %v\nOutput: %s\n%s", err2, output, output2)
-		}
-	}
-
-	// Set HEAD in the bare repo to point to the default branch
-	cmd = exec.Command("git", "symbolic-ref", "HEAD", "refs/heads/main")
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
-		// Try master if main fails
-		cmd = exec.Command("git", "symbolic-ref", "HEAD", "refs/heads/master")
-		cmd.Dir = dir
-		_ = cmd.Run() // Ignore error intentionally
-	}
-
-	// Don't configure remote origin for test repos
-	// This prevents fetch attempts during tests
-
-	// Set up refs/remotes/origin/HEAD to point to main/master
-	originHeadPath := filepath.Join(dir, "refs", "remotes", "origin")
-	if err := os.MkdirAll(originHeadPath, 0755); err != nil {
-		t.Fatalf("Failed to create origin directory: %v", err)
-	}
-
-	// Check which branch exists and set origin/HEAD accordingly
-	if _, err := os.Stat(filepath.Join(dir, "refs", "heads", "main")); err == nil {
-		if err := os.WriteFile(filepath.Join(originHeadPath, "HEAD"), []byte("ref: refs/remotes/origin/main\n"), 0644); err != nil {
-			t.Fatalf("Failed to write origin/HEAD: %v", err)
-		}
-		// Copy main to origin/main
-		mainContent, _ := os.ReadFile(filepath.Join(dir, "refs", "heads", "main"))
-		if err := os.WriteFile(filepath.Join(originHeadPath, "main"), mainContent, 0644); err != nil {
-			t.Fatalf("Failed to write origin/main: %v", err)
-		}
-	} else if _, err := os.Stat(filepath.Join(dir, "refs", "heads", "master")); err == nil {
-		if err := os.WriteFile(filepath.Join(originHeadPath, "HEAD"), []byte("ref: refs/remotes/origin/master\n"), 0644); err != nil {
-			t.Fatalf("Failed to write origin/HEAD: %v", err)
-		}
-		// Copy master to origin/master
-		masterContent, _ := os.ReadFile(filepath.Join(dir, "refs", "heads", "master"))
-		if err := os.WriteFile(filepath.Join(originHeadPath, "master"), masterContent, 0644); err != nil {
-			t.Fatalf("Failed to write origin/master: %v", err)
-		}
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err == nil {
+		return
 	}
+	NewRepoFixture(t).Push(dir)
 }
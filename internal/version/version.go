@@ -0,0 +1,30 @@
+// Package version holds build information set via ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/yammerjp/devslot/internal/version.Version=1.2.3"
+package version
+
+// Build information; overridden by ldflags in release builds
+var (
+	// Version is the released version of devslot
+	Version = "dev"
+	// Commit is the git commit the binary was built from
+	Commit = "none"
+	// Date is the build date
+	Date = "unknown"
+)
+
+// Info is the build information of the running binary
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+// Get returns the build information
+func Get() Info {
+	return Info{
+		Version: Version,
+		Commit:  Commit,
+		Date:    Date,
+	}
+}
@@ -0,0 +1,372 @@
+// Package devslot is the Go API of devslot. It manages the repositories and
+// slots of a project like the devslot command does, but returns structured
+// results instead of printing them.
+//
+// The API of this package is kept stable: exported names are only added, not
+// changed or removed, within a major version. The packages under internal/ are
+// not part of it and may change at any time.
+package devslot
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/yammerjp/devslot/internal/command"
+	"github.com/yammerjp/devslot/internal/config"
+	"github.com/yammerjp/devslot/internal/errors"
+	"github.com/yammerjp/devslot/internal/git"
+	"github.com/yammerjp/devslot/internal/lock"
+	"github.com/yammerjp/devslot/internal/slot"
+	"github.com/yammerjp/devslot/internal/version"
+)
+
+// Project is a devslot project, the directory containing devslot.yaml.
+// Operations that change the project take the project lock, like the devslot
+// command, so they can run alongside devslot processes.
+type Project struct {
+	root string
+	// Output receives progress messages, warnings and hook output; nil discards
+	// them. Messages of the git commands run by an operation are still written
+	// to the standard output and error of the process.
+	Output io.Writer
+}
+
+// Open returns the project whose devslot.yaml is in root. The configuration
+// is read again by every operation, so changes to it are picked up.
+func Open(root string) (*Project, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	p := &Project{root: root}
+	if _, err := p.config(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Root returns the absolute path of the project directory
+func (p *Project) Root() string {
+	return p.root
+}
+
+// InitOptions contains options for Init
+type InitOptions struct {
+	Fetch       bool     // Fetch repositories that are already cloned
+	AllowDelete bool     // Delete repositories that devslot.yaml no longer lists
+	Force       bool     // With AllowDelete, delete repositories even if slots still use them
+	Groups      []string // Only clone repositories in these groups (all when empty)
+}
+
+// InitResult lists what Init did to each repository
+type InitResult struct {
+	Cloned  []string // Repositories that were cloned
+	Fetched []string // Existing repositories that were fetched
+	Skipped []string // Existing repositories that were left as they were
+	Failed  []string // Repositories that could not be cloned or fetched
+}
+
+// Init clones the repositories of devslot.yaml that are missing, like 'devslot init'.
+// When cloning fails after others succeeded, the result is returned with the error.
+func (p *Project) Init(opts InitOptions) (*InitResult, error) {
+	release, err := p.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cfg, err := p.config()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &command.InitCmd{
+		Fetch:       opts.Fetch,
+		AllowDelete: opts.AllowDelete,
+		Force:       opts.Force,
+		Group:       opts.Groups,
+		NoProgress:  true,
+	}
+	summary, err := cmd.Initialize(p.context(), p.root, cfg)
+	if summary == nil {
+		return nil, err
+	}
+	return &InitResult{
+		Cloned:  summary.Cloned,
+		Fetched: summary.Fetched,
+		Skipped: summary.Skipped,
+		Failed:  summary.Failed,
+	}, err
+}
+
+// CreateOptions contains options for CreateSlot
+type CreateOptions struct {
+	Branch      string   // Branch to check out; empty creates a branch named from the branch prefix
+	FreshBranch bool     // Add a numeric suffix to the new branch instead of reusing an existing one
+	Description string   // What the slot is for
+	Groups      []string // Only create worktrees for repositories in these groups (all when empty)
+	Template    string   // Slot template of devslot.yaml to apply; the options above take precedence
+}
+
+// CreateSlot creates a slot with a worktree of every repository, like 'devslot create'
+func (p *Project) CreateSlot(name string, opts CreateOptions) (*SlotInfo, error) {
+	release, err := p.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cfg, err := p.config()
+	if err != nil {
+		return nil, err
+	}
+	mgr := p.manager(cfg)
+	createOpts := &slot.CreateOptions{
+		Branch:       opts.Branch,
+		FreshBranch:  opts.FreshBranch,
+		Description:  opts.Description,
+		Groups:       opts.Groups,
+		BranchPrefix: git.GetBranchPrefix(cfg.BranchPrefix),
+		Version:      version.Version,
+	}
+	if opts.Template != "" {
+		if err := createOpts.ApplyTemplate(cfg, opts.Template); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := mgr.Create(name, cfg, createOpts); err != nil {
+		return nil, err
+	}
+	return p.slotInfo(mgr, cfg, name)
+}
+
+// DestroySlot removes a slot and its worktrees, like 'devslot destroy'. It fails
+// when the slot contains git worktrees of repositories that are not in devslot.yaml.
+func (p *Project) DestroySlot(name string) error {
+	release, err := p.lock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	cfg, err := p.config()
+	if err != nil {
+		return err
+	}
+	result, err := p.manager(cfg).Destroy(name, cfg, nil)
+	if result != nil {
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(p.output(), "Warning: %s\n", warning)
+		}
+	}
+	return err
+}
+
+// ReloadOptions contains options for ReloadSlot
+type ReloadOptions struct {
+	Prune          bool // Remove worktrees of repositories that devslot.yaml no longer lists
+	UpdateBranches bool // Record the currently checked-out branches as the slot's branches
+	Repair         bool // Recreate worktrees that are no longer connected to their repository
+	Reseed         bool // Copy the seed_files again, replacing copies that were not changed since
+}
+
+// ReloadResult lists the worktrees ReloadSlot changed
+type ReloadResult struct {
+	Created  []string // Repositories whose missing worktree was created
+	Repaired []string // Repositories whose corrupted worktree was recreated
+	Pruned   []string // Repositories whose worktree was removed
+	Seeded   []string // Seed files copied into the slot, relative to the slot directory
+	Linked   []string // Shared links created in the slot, relative to the slot directory
+}
+
+// ReloadSlot creates the missing worktrees of a slot, like 'devslot reload'.
+// When it fails part way, the result is returned with the error.
+func (p *Project) ReloadSlot(name string, opts ReloadOptions) (*ReloadResult, error) {
+	release, err := p.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cfg, err := p.config()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.manager(cfg).Reload(name, cfg, &slot.ReloadOptions{
+		Prune:          opts.Prune,
+		UpdateBranches: opts.UpdateBranches,
+		Repair:         opts.Repair,
+		Reseed:         opts.Reseed,
+	})
+	if result == nil {
+		return nil, err
+	}
+	return &ReloadResult{Created: result.Created, Repaired: result.Repaired, Pruned: result.Pruned, Seeded: result.Seeded, Linked: result.Linked}, err
+}
+
+// SlotInfo describes a slot
+type SlotInfo struct {
+	Name        string
+	Path        string
+	Description string
+	Branch      string     // Branch the slot was created with; empty for slots of old devslot versions
+	Template    string     // Slot template the slot was created from, if any
+	Worktrees   []Worktree // In devslot.yaml order
+}
+
+// Worktree describes the worktree of a repository in a slot
+type Worktree struct {
+	Repository     string
+	Path           string
+	Exists         bool   // False when the worktree is missing, e.g. for repositories added later
+	Branch         string // Branch checked out in the worktree
+	RecordedBranch string // Branch recorded when the worktree was created
+	Optional       bool   // Whether the repository is optional in devslot.yaml
+}
+
+// Drifted reports whether the worktree left the branch recorded at its creation
+func (w Worktree) Drifted() bool {
+	return w.Exists && w.RecordedBranch != "" && w.Branch != w.RecordedBranch
+}
+
+// ListSlots returns the slots of the project sorted by name, like 'devslot list'
+func (p *Project) ListSlots() ([]SlotInfo, error) {
+	cfg, err := p.config()
+	if err != nil {
+		return nil, err
+	}
+	mgr := p.manager(cfg)
+	names, err := mgr.List()
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]SlotInfo, 0, len(names))
+	for _, name := range names {
+		info, err := p.slotInfo(mgr, cfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect slot %s: %w", name, err)
+		}
+		slots = append(slots, *info)
+	}
+	return slots, nil
+}
+
+// Severity says how serious a Finding is
+type Severity string
+
+// Finding severities
+const (
+	SeverityError   Severity = "error"   // Makes 'devslot doctor' fail
+	SeverityWarning Severity = "warning" // Needs attention but does not make doctor fail
+	SeverityInfo    Severity = "info"    // Additional information
+	SeverityOK      Severity = "ok"      // A passed check
+)
+
+// Finding is the result of one doctor check about one target
+type Finding struct {
+	Check    string // Name of the check, e.g. "repositories"
+	Severity Severity
+	Target   string // What the finding is about, e.g. a repository or slot name
+	Message  string
+	Fixable  bool // Whether Doctor with Fix repairs the problem
+}
+
+// DoctorOptions contains options for Doctor
+type DoctorOptions struct {
+	Fix bool // Repair what can be repaired, like 'devslot doctor --fix'
+}
+
+// Doctor checks the consistency of the project, like 'devslot doctor'. Passed
+// checks are included with SeverityOK.
+func (p *Project) Doctor(opts DoctorOptions) []Finding {
+	cmd := &command.DoctorCmd{Fix: opts.Fix}
+	var findings []Finding
+	for _, finding := range cmd.Diagnose(p.context(), p.root) {
+		findings = append(findings, Finding{
+			Check:    finding.Check,
+			Severity: Severity(finding.Severity),
+			Target:   finding.Target,
+			Message:  finding.Message,
+			Fixable:  finding.Fixable,
+		})
+	}
+	return findings
+}
+
+// slotInfo describes a slot from its metadata and the state of its worktrees
+func (p *Project) slotInfo(mgr *slot.Manager, cfg *config.Config, name string) (*SlotInfo, error) {
+	statuses, err := mgr.Status(name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	info := &SlotInfo{Name: name, Path: filepath.Join(cfg.SlotsDir(p.root), name)}
+	meta, err := slot.LoadMetadata(info.Path)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		info.Description = meta.Description
+		info.Branch = meta.Branch
+		info.Template = meta.Template
+	}
+	for _, status := range statuses {
+		info.Worktrees = append(info.Worktrees, Worktree{
+			Repository:     status.Name,
+			Path:           filepath.Join(info.Path, status.Name),
+			Exists:         status.Exists,
+			Branch:         status.Branch,
+			RecordedBranch: status.RecordedBranch,
+			Optional:       status.Optional,
+		})
+	}
+	return info, nil
+}
+
+// output returns where progress, warnings and hook output go
+func (p *Project) output() io.Writer {
+	if p.Output == nil {
+		return io.Discard
+	}
+	return p.Output
+}
+
+// context returns the command context operations shared with the command layer run in
+func (p *Project) context() *command.Context {
+	return &command.Context{Out: p.output(), Err: p.output(), Dir: p.root}
+}
+
+// config loads devslot.yaml
+func (p *Project) config() (*config.Config, error) {
+	path, _ := config.FindFile(p.root)
+	if path == "" {
+		return nil, errors.ConfigNotFound(p.root)
+	}
+	cfg, err := config.LoadFile(p.root, path)
+	if err != nil {
+		return nil, err
+	}
+	git.SetEnv(cfg.GitEnv)
+	git.SetBinary(cfg.GitBinary)
+	return cfg, nil
+}
+
+// manager returns a slot manager reporting to Output
+func (p *Project) manager(cfg *config.Config) *slot.Manager {
+	mgr := slot.NewManager(p.root)
+	mgr.SetDirs(cfg)
+	mgr.Err = p.output()
+	mgr.SetHookStdout(p.output())
+	mgr.SetHookStderr(p.output())
+	return mgr
+}
+
+// lock takes the project lock and returns the function releasing it
+func (p *Project) lock() (release func(), err error) {
+	l := lock.New(filepath.Join(p.root, ".devslot.lock"))
+	if err := l.Acquire(); err != nil {
+		return nil, errors.LockFailed(err)
+	}
+	return func() { _ = l.Release() }, nil
+}
@@ -0,0 +1,101 @@
+package devslot_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/yammerjp/devslot/internal/testutil"
+	"github.com/yammerjp/devslot/pkg/devslot"
+)
+
+// newProject creates a project with one repository, app, that can be cloned from a local path
+func newProject(t *testing.T) *devslot.Project {
+	t.Helper()
+	t.Setenv("DEVSLOT_BRANCH_PREFIX", "test/")
+
+	root := testutil.TempDir(t)
+	testutil.CreateProjectStructure(t, root)
+	source := filepath.Join(testutil.TempDir(t), "app.git")
+	testutil.NewRepoFixture(t).Push(source)
+	testutil.CreateFile(t, filepath.Join(root, "devslot.yaml"), "version: 1\nrepositories:\n  - name: app\n    url: "+source+"\n")
+	testutil.CreateFile(t, filepath.Join(root, ".gitignore"), "/repos/\n/slots/\n/archives/\n/.devslot.lock\n")
+
+	project, err := devslot.Open(root)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return project
+}
+
+func TestProject_SlotLifecycle(t *testing.T) {
+	project := newProject(t)
+	var output bytes.Buffer
+	project.Output = &output
+
+	result, err := project.Init(devslot.InitOptions{})
+	if err != nil {
+		t.Fatalf("Init() error = %v\n%s", err, output.String())
+	}
+	if !slices.Equal(result.Cloned, []string{"app"}) {
+		t.Errorf("Cloned = %v, want [app]", result.Cloned)
+	}
+
+	slot, err := project.CreateSlot("feature", devslot.CreateOptions{Description: "New feature"})
+	if err != nil {
+		t.Fatalf("CreateSlot() error = %v\n%s", err, output.String())
+	}
+	if slot.Branch != "test/feature" || slot.Description != "New feature" {
+		t.Errorf("CreateSlot() = %+v", slot)
+	}
+	if len(slot.Worktrees) != 1 || !slot.Worktrees[0].Exists || slot.Worktrees[0].Branch != "test/feature" {
+		t.Errorf("Worktrees = %+v, want app on test/feature", slot.Worktrees)
+	}
+	if !testutil.DirExists(t, slot.Worktrees[0].Path) {
+		t.Errorf("worktree %s does not exist", slot.Worktrees[0].Path)
+	}
+
+	slots, err := project.ListSlots()
+	if err != nil {
+		t.Fatalf("ListSlots() error = %v", err)
+	}
+	if len(slots) != 1 || slots[0].Name != "feature" {
+		t.Errorf("ListSlots() = %+v, want feature", slots)
+	}
+
+	reload, err := project.ReloadSlot("feature", devslot.ReloadOptions{})
+	if err != nil {
+		t.Fatalf("ReloadSlot() error = %v", err)
+	}
+	if len(reload.Created) != 0 || len(reload.Pruned) != 0 {
+		t.Errorf("ReloadSlot() = %+v, want no changes", reload)
+	}
+
+	for _, finding := range project.Doctor(devslot.DoctorOptions{}) {
+		if finding.Severity == devslot.SeverityError || finding.Severity == devslot.SeverityWarning {
+			t.Errorf("unexpected doctor finding: %+v", finding)
+		}
+	}
+
+	if err := project.DestroySlot("feature"); err != nil {
+		t.Fatalf("DestroySlot() error = %v", err)
+	}
+	if slots, err := project.ListSlots(); err != nil || len(slots) != 0 {
+		t.Errorf("ListSlots() = %v, %v after destroying, want none", slots, err)
+	}
+}
+
+func TestProject_Errors(t *testing.T) {
+	if _, err := devslot.Open(testutil.TempDir(t)); err == nil {
+		t.Error("expected Open() to fail without devslot.yaml")
+	}
+
+	project := newProject(t)
+	if _, err := project.CreateSlot("feature", devslot.CreateOptions{}); err == nil {
+		t.Error("expected CreateSlot() to fail before Init")
+	}
+	if err := project.DestroySlot("missing"); err == nil {
+		t.Error("expected DestroySlot() to fail for a missing slot")
+	}
+}
@@ -0,0 +1,40 @@
+package devslot_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/yammerjp/devslot/pkg/devslot"
+)
+
+func Example() {
+	project, err := devslot.Open("/path/to/project")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := project.Init(devslot.InitOptions{}); err != nil {
+		log.Fatal(err)
+	}
+
+	slot, err := project.CreateSlot("feature-x", devslot.CreateOptions{Description: "Try out feature X"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, worktree := range slot.Worktrees {
+		fmt.Printf("%s: %s on %s\n", worktree.Repository, worktree.Path, worktree.Branch)
+	}
+}
+
+func ExampleProject_Doctor() {
+	project, err := devslot.Open("/path/to/project")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, finding := range project.Doctor(devslot.DoctorOptions{}) {
+		if finding.Severity == devslot.SeverityError {
+			fmt.Printf("%s: %s\n", finding.Check, finding.Message)
+		}
+	}
+}
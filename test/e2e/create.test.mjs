@@ -225,7 +225,7 @@ repositories: []
 `)
   
   // Try invalid names
-  const invalidNames = ['slot/with/slash', 'slot\\with\\backslash', '..', '.']
+  const invalidNames = ['slot/with/slash', 'slot\\with\\backslash', '..', '.', '-rf', 'CON', 'with space', 'a'.repeat(101)]
   
   for (const name of invalidNames) {
     const result = await $({ nothrow: true })`${devslotBinary} create "${name}"`
@@ -330,6 +330,11 @@ exit 1
     fail('Create should fail when post-create hook fails')
     return
   }
+
+  if (createResult.exitCode !== 5) {
+    fail(`Expected exit code 5 for a failed hook, got ${createResult.exitCode}`)
+    return
+  }
   
   // Check if slot was cleaned up
   // The slot directory might be created but should be empty or removed
@@ -122,11 +122,32 @@ async function testVersionFlag() {
     fail(`Version flags should output version string, got: -v="${output1}", --version="${output2}"`)
     return
   }
+
+  // The flags and the version command must report the same version
+  const result3 = await $({ nothrow: true })`${devslotBinary} version`
+  const commandVersion = result3.stdout.trim().split('\n')[0].replace('devslot version ', '')
+  if (output1 !== commandVersion || output2 !== commandVersion) {
+    fail(`Version flags and version command disagree: -v="${output1}", --version="${output2}", version="${commandVersion}"`)
+    return
+  }
   
   pass()
 }
 
 // Doctor command tests
+async function testUnknownCommandExitCode() {
+  await setupTest('unknown_command_exit_code')
+
+  const result = await $({ nothrow: true })`${devslotBinary} no-such-command`
+
+  if (result.exitCode !== 2) {
+    fail(`Expected exit code 2 for an unknown command, got ${result.exitCode}`)
+    return
+  }
+
+  pass()
+}
+
 async function testDoctorHealthyProject() {
   await setupTest('doctor_healthy')
   
@@ -187,6 +208,11 @@ async function testDoctorMissingConfig() {
     fail('Doctor should fail without devslot.yaml')
     return
   }
+
+  if (result.exitCode !== 3) {
+    fail(`Expected exit code 3 outside a devslot project, got ${result.exitCode}`)
+    return
+  }
   
   const errorOutput = result.stderr + result.stdout
   if (!errorOutput.includes('not in a devslot project')) {
@@ -218,6 +244,11 @@ repositories:
     fail('Doctor should report missing repository')
     return
   }
+
+  if (result.exitCode !== 6) {
+    fail(`Expected exit code 6 when doctor finds issues, got ${result.exitCode}`)
+    return
+  }
   
   pass()
 }
@@ -358,6 +389,7 @@ async function runTests() {
   echo(chalk.blue('\n--- Version Command Tests ---'))
   await testVersionCommand()
   await testVersionFlag()
+  await testUnknownCommandExitCode()
   
   // Run doctor tests
   echo(chalk.blue('\n--- Doctor Command Tests ---'))